@@ -0,0 +1,257 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	сategory "github.com/Anabol1ks/pers-fin-m/internal/category"
+	"github.com/Anabol1ks/pers-fin-m/internal/importer"
+	"github.com/Anabol1ks/pers-fin-m/internal/ledger"
+	"github.com/Anabol1ks/pers-fin-m/internal/migrations"
+	"github.com/Anabol1ks/pers-fin-m/internal/recurring"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository/postgres"
+	"github.com/Anabol1ks/pers-fin-m/internal/storage"
+	"github.com/Anabol1ks/pers-fin-m/internal/users"
+)
+
+const usage = `Использование:
+  pers-fin-m                      запуск сервера
+  pers-fin-m migrate up           применить все новые миграции
+  pers-fin-m migrate down         откатить последнюю миграцию
+  pers-fin-m migrate status       показать состояние миграций
+  pers-fin-m ledger check         сверить балансы всех пользователей с транзакциями
+  pers-fin-m recurring run        создать наступившие повторяющиеся операции
+  pers-fin-m seed categories      создать недостающие категории по умолчанию
+  pers-fin-m admin grant <email>  выдать пользователю права администратора
+  pers-fin-m admin revoke <email> отозвать права администратора
+  pers-fin-m import [флаги] <email> <файл>
+                                  импортировать выписку CSV, OFX, QIF или журнал ledger/beancount;
+                                  без -commit только показывает предпросмотр. Флаги: -account ID,
+                                  -mapping ID (для CSV), -format csv|ofx|qif|ledger|beancount, -encoding,
+                                  -date-format (для QIF), -commit, -skip-invalid`
+
+// runCommand выполняет служебную команду вместо запуска сервера.
+func runCommand(args []string) {
+	switch args[0] {
+	case "migrate":
+		if len(args) < 2 {
+			exitUsage()
+		}
+		storage.ConnectDatabase()
+		runMigrate(args[1])
+	case "ledger":
+		if len(args) < 2 || args[1] != "check" {
+			exitUsage()
+		}
+		storage.ConnectDatabase()
+		drifted := ledger.CheckAll(postgres.NewStore(storage.DB))
+		fmt.Printf("Пользователей с расхождениями: %d\n", drifted)
+	case "recurring":
+		if len(args) < 2 || args[1] != "run" {
+			exitUsage()
+		}
+		storage.ConnectDatabase()
+		created := recurring.GenerateAll(postgres.NewStore(storage.DB), time.Now())
+		fmt.Printf("Создано транзакций: %d\n", created)
+	case "seed":
+		if len(args) < 2 || args[1] != "categories" {
+			exitUsage()
+		}
+		storage.ConnectDatabase()
+		seedCategories(postgres.NewStore(storage.DB))
+	case "admin":
+		if len(args) < 3 || (args[1] != "grant" && args[1] != "revoke") {
+			exitUsage()
+		}
+		storage.ConnectDatabase()
+		if err := users.SetAdmin(postgres.NewStore(storage.DB), args[2], args[1] == "grant"); err != nil {
+			log.Fatal("Ошибка изменения прав: ", err)
+		}
+		fmt.Println("Права пользователя обновлены")
+	case "import":
+		storage.ConnectDatabase()
+		runImport(postgres.NewStore(storage.DB), args[1:])
+	default:
+		exitUsage()
+	}
+}
+
+// runImport импортирует выписку тем же путём, что и POST /transactions/import.
+func runImport(store repository.Store, args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	account := flags.String("account", "", "ID счёта, по умолчанию основной")
+	mappingID := flags.Uint("mapping", 0, "ID сохранённого формата CSV")
+	format := flags.String("format", "", "формат файла: csv, ofx, qif, ledger или beancount")
+	encoding := flags.String("encoding", "", "кодировка QIF")
+	dateFormat := flags.String("date-format", "", "формат дат QIF")
+	commit := flags.Bool("commit", false, "сохранить операции")
+	skipInvalid := flags.Bool("skip-invalid", false, "пропустить строки с ошибками")
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		exitUsage()
+	}
+
+	user, err := store.Users().GetByEmail(strings.ToLower(flags.Arg(0)))
+	if err != nil {
+		log.Fatal("Пользователь не найден: ", err)
+	}
+	path := flags.Arg(1)
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal("Не удалось открыть файл: ", err)
+	}
+	defer file.Close()
+
+	opts := importer.Options{Format: *format, Encoding: *encoding, DateFormat: *dateFormat}
+	if opts.Format == "" {
+		opts.Format = importer.DetectFormat(path)
+	}
+	if opts.Format == importer.FormatCSV {
+		if *mappingID == 0 {
+			log.Fatal("Для CSV укажите сохранённый формат: -mapping ID")
+		}
+		if opts.Mapping, err = store.ImportMappings().GetOwned(*mappingID, user.ID); err != nil {
+			log.Fatal("Формат не найден: ", err)
+		}
+	}
+
+	records, err := importer.Parse(file, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	target, errMsg := importer.ImportAccount(store, user.ID, *account)
+	if errMsg != "" {
+		log.Fatal(errMsg)
+	}
+	preview, err := importer.Prepare(store, user.ID, target, records)
+	if err != nil {
+		log.Fatal("Ошибка при проверке выписки: ", err)
+	}
+
+	for _, row := range preview.Rows {
+		if len(row.Errors) > 0 {
+			fmt.Printf("строка %d: %s\n", row.Line, strings.Join(row.Errors, "; "))
+		}
+	}
+	fmt.Printf("Счёт %q: корректных %d, с ошибками %d, дублей %d, доходы %s, расходы %s\n",
+		target.Name, preview.Valid, preview.Invalid, preview.Duplicate, preview.Income, preview.Expense)
+
+	if !*commit {
+		return
+	}
+	if preview.Invalid > 0 && !*skipInvalid {
+		log.Fatal("В выписке есть строки с ошибками, исправьте их или добавьте -skip-invalid")
+	}
+	if err := importer.Commit(store, user.ID, preview.Transactions()); err != nil {
+		log.Fatal("Ошибка при импорте операций: ", err)
+	}
+	fmt.Printf("Импортировано операций: %d\n", preview.Valid)
+}
+
+func runMigrate(action string) {
+	switch action {
+	case "up":
+		count, err := migrations.Up(storage.DB)
+		if err != nil {
+			log.Fatal("Ошибка применения миграций: ", err)
+		}
+		fmt.Printf("Применено миграций: %d\n", count)
+	case "down":
+		rolledBack, err := migrations.Down(storage.DB)
+		if err != nil {
+			log.Fatal("Ошибка отката миграции: ", err)
+		}
+		if !rolledBack {
+			fmt.Println("Нет применённых миграций")
+		}
+	case "status":
+		statuses, err := migrations.List(storage.DB)
+		if err != nil {
+			log.Fatal("Ошибка получения состояния миграций: ", err)
+		}
+		for _, s := range statuses {
+			applied := "не применена"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-40s %s\n", s.Version, s.Name, applied)
+		}
+	default:
+		exitUsage()
+	}
+}
+
+// applyMigrations применяет миграции при старте сервера.
+// При AUTO_MIGRATE=false сервер только проверяет, что схема актуальна.
+func applyMigrations() {
+	if os.Getenv("AUTO_MIGRATE") == "false" {
+		pending, err := migrations.Pending(storage.DB)
+		if err != nil {
+			log.Fatal("Ошибка проверки миграций: ", err)
+		}
+		if pending > 0 {
+			log.Fatalf("Есть неприменённые миграции (%d), выполните migrate up", pending)
+		}
+		return
+	}
+
+	if _, err := migrations.Up(storage.DB); err != nil {
+		log.Fatal("Ошибка применения миграций: ", err)
+	}
+}
+
+// seedCategories создаёт категории по умолчанию из DEFAULT_CATEGORIES_FILE
+// или встроенного списка.
+func seedCategories(store repository.Store) {
+	defaults, err := сategory.LoadDefaults(os.Getenv("DEFAULT_CATEGORIES_FILE"))
+	if err != nil {
+		log.Fatal("Ошибка загрузки категорий по умолчанию: ", err)
+	}
+	if _, err := сategory.Seed(store, defaults); err != nil {
+		log.Fatal("Ошибка создания категорий по умолчанию: ", err)
+	}
+}
+
+// startLedgerJob запускает периодическую сверку балансов.
+// Интервал задаётся LEDGER_CHECK_INTERVAL (по умолчанию 24h, 0 — отключить).
+func startLedgerJob(store repository.Store) {
+	interval := 24 * time.Hour
+	if value := os.Getenv("LEDGER_CHECK_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatal("Неверный LEDGER_CHECK_INTERVAL: ", err)
+		}
+		interval = parsed
+	}
+	if interval <= 0 {
+		return
+	}
+	go ledger.RunPeriodic(store, interval)
+}
+
+// startRecurringJob запускает планировщик повторяющихся операций.
+// Интервал задаётся RECURRING_INTERVAL (по умолчанию 1h, 0 — отключить).
+func startRecurringJob(store repository.Store) {
+	interval := time.Hour
+	if value := os.Getenv("RECURRING_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatal("Неверный RECURRING_INTERVAL: ", err)
+		}
+		interval = parsed
+	}
+	if interval <= 0 {
+		return
+	}
+	go recurring.RunPeriodic(store, interval)
+}
+
+func exitUsage() {
+	fmt.Fprintln(os.Stderr, usage)
+	os.Exit(2)
+}
@@ -15,9 +15,54 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
+        "/accounts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить счета пользователя с текущими балансами",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Accounts"
+                ],
+                "summary": "Получить счета",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Включить архивные счета",
+                        "name": "archived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Account"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении счетов",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Авторизация пользователя с указанием почты и пароля",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создать новый счёт или кошелёк пользователя",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,41 +70,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "Accounts"
                 ],
-                "summary": "Авторизация",
+                "summary": "Создать счёт",
                 "parameters": [
                     {
-                        "description": "Данные пользователя",
+                        "description": "Счёт для создания",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.LoginInput"
+                            "$ref": "#/definitions/accounts.CreateAccountInput"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Успешная авторизация",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/response.TokenResponse"
+                            "$ref": "#/definitions/models.Account"
                         }
                     },
                     "400": {
-                        "description": "Описание ошибки валидации",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Неверный пароль",
+                        "description": "Ошибка валидации",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Пользователя с такой почтой не существует",
+                    "500": {
+                        "description": "Ошибка создания счёта",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -67,55 +106,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/newVerify": {
-            "post": {
+        "/accounts/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Подтверждение аккаунта пользователя с указанием почты и кода подтверждения",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Получить счёт пользователя по ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "Accounts"
+                ],
+                "summary": "Получить счёт",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID счёта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Подтверждение аккаунта",
                 "responses": {
                     "200": {
-                        "description": "Письмо отправлено",
-                        "schema": {
-                            "$ref": "#/definitions/response.SuccessResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Неверный код подтверждения",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Аккаунт уже подтверждён",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/models.Account"
                         }
                     },
-                    "500": {
-                        "description": "Пользователь не существует",
+                    "404": {
+                        "description": "Счёт не найден",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "Регистрация пользователя с указанием никнейма, почты, пароля",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновить счёт пользователя. Изменение начального остатка сдвигает текущий баланс на ту же сумму. Валюту можно менять только у счёта без транзакций",
                 "consumes": [
                     "application/json"
                 ],
@@ -123,98 +159,98 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "Accounts"
                 ],
-                "summary": "Регистрация пользователя",
+                "summary": "Обновить счёт",
                 "parameters": [
                     {
-                        "description": "Данные пользователя",
+                        "type": "string",
+                        "description": "ID счёта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для обновления счёта",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.RegisterInput"
+                            "$ref": "#/definitions/accounts.UpdateAccountInput"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Успешная регистрация",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.SuccessResponse"
+                            "$ref": "#/definitions/models.Account"
                         }
                     },
                     "400": {
-                        "description": "Описание ошибки валидации",
+                        "description": "Ошибка валидации",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Почта уже зарегистрированы",
+                    "404": {
+                        "description": "Счёт не найден",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Не удалось хешировать пароль или создать пользователя",
+                        "description": "Ошибка обновления счёта",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/auth/verify": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Подтверждение аккаунта пользователя с указанием почты и кода подтверждения",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Удалить счёт без транзакций. Счета с транзакциями можно только архивировать",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "Accounts"
                 ],
-                "summary": "Подтверждение аккаунта",
+                "summary": "Удалить счёт",
                 "parameters": [
                     {
-                        "description": "Данные пользователя",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/auth.VerificationCodeInput"
-                        }
+                        "type": "string",
+                        "description": "ID счёта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Аккаунт успешно подтверждён",
+                        "description": "Счёт удалён",
                         "schema": {
                             "$ref": "#/definitions/response.SuccessResponse"
                         }
                     },
-                    "400": {
-                        "description": "Неверный код подтверждения",
+                    "404": {
+                        "description": "Счёт не найден",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Пользователь не существует",
+                    "409": {
+                        "description": "По счёту есть транзакции",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Аккаунт уже подтверждён",
+                    "500": {
+                        "description": "Ошибка удаления счёта",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -222,21 +258,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories": {
+        "/admin/categories": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получить все категории пользователя или категории по умолчанию",
+                "description": "Список всех категорий по умолчанию, включая выведенные из оборота. Только для администраторов",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Admin"
                 ],
-                "summary": "Получить категории",
+                "summary": "Категории по умолчанию",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -247,6 +283,12 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Ошибка при получении категорий",
                         "schema": {
@@ -261,7 +303,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Создать новую категорию для пользователя",
+                "description": "Добавляет категорию, доступную всем пользователям. Только для администраторов",
                 "consumes": [
                     "application/json"
                 ],
@@ -269,17 +311,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Admin"
                 ],
-                "summary": "Создать категорию",
+                "summary": "Добавить категорию по умолчанию",
                 "parameters": [
                     {
-                        "description": "Категория для создания",
+                        "description": "Категория по умолчанию",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/%D1%81ategory.CreateCategoryInput"
+                            "$ref": "#/definitions/%D1%81ategory.DefaultCategory"
                         }
                     }
                 ],
@@ -296,6 +338,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Категория с таким кодом уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Ошибка создания категории",
                         "schema": {
@@ -305,24 +359,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/{id}": {
-            "put": {
+        "/admin/categories/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Обновить информацию о категории пользователя",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Скрывает категорию по умолчанию для новых транзакций. Существующие транзакции сохраняют категорию. Только для администраторов",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Admin"
                 ],
-                "summary": "Обновить категорию",
+                "summary": "Вывести категорию по умолчанию из оборота",
                 "parameters": [
                     {
                         "type": "string",
@@ -330,26 +381,23 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Категория для обновления",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/%D1%81ategory.UpdateCategoryInput"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Категория успешно обновлена",
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/models.Category"
                         }
                     },
                     "400": {
-                        "description": "Ошибка валидации",
+                        "description": "Категорию нельзя вывести из оборота",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -367,21 +415,23 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/categories/{id}/restore": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Удалить категорию пользователя",
+                "description": "Возвращает ранее выведенную из оборота категорию по умолчанию. Только для администраторов",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Admin"
                 ],
-                "summary": "Удалить категорию",
+                "summary": "Вернуть категорию по умолчанию",
                 "parameters": [
                     {
                         "type": "string",
@@ -393,19 +443,25 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Категория успешно удалена",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.SuccessResponse"
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Категория не найдена или не принадлежит пользователю",
+                        "description": "Категория не найдена",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка удаления категории",
+                        "description": "Ошибка обновления категории",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -413,14 +469,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/transactions": {
+        "/auth/2fa": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Состояние двухфакторной аутентификации",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auth.TwoFactorStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/disable": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Создает новую транзакцию для пользователя",
+                "description": "Отключает двухфакторную аутентификацию. Требуется код из приложения или код восстановления.",
                 "consumes": [
                     "application/json"
                 ],
@@ -428,35 +514,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "auth"
                 ],
-                "summary": "Создать транзакцию",
+                "summary": "Отключить 2FA",
                 "parameters": [
                     {
-                        "description": "Транзакция для создания",
+                        "description": "Код из приложения или код восстановления",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/transactions.TransactionInput"
+                            "$ref": "#/definitions/auth.TwoFactorCodeInput"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "2FA отключена",
                         "schema": {
-                            "$ref": "#/definitions/transactions.TransactionInput"
+                            "$ref": "#/definitions/response.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Ошибка валидации",
+                        "description": "Неверный код или 2FA не включена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много неверных кодов",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка создания транзакции",
+                        "description": "Ошибка отключения 2FA",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -464,77 +556,62 @@ const docTemplate = `{
                 }
             }
         },
-        "/transactions/search": {
-            "get": {
+        "/auth/2fa/enable": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Ищет транзакции пользователя по различным опциональным параметрам",
+                "description": "Подтверждает подключение кодом из приложения и возвращает одноразовые коды восстановления.\nКоды показываются один раз, каждый позволяет войти без приложения.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "auth"
                 ],
-                "summary": "Поиск транзакций",
+                "summary": "Включить 2FA",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Название транзакции (частичное совпадение)",
-                        "name": "title",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Приблизительная сумма транзакции",
-                        "name": "amount",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Приблизительное количество бонусов",
-                        "name": "bonusChange",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Дата транзакции (формат YYYY-MM-DD)",
-                        "name": "date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID категории",
-                        "name": "category",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Тип транзакции (income или expense)",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Тип бонуса",
-                        "name": "typeBonus",
-                        "in": "query"
+                        "description": "Код из приложения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.TwoFactorCodeInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Найденные транзакции",
+                        "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/transactions.TransactionInput"
-                            }
+                            "$ref": "#/definitions/auth.RecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный код или подключение не начато",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "2FA уже включена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много неверных кодов",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при поиске транзакций",
+                        "description": "Ошибка включения 2FA",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -542,14 +619,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/transactions/{id}": {
-            "put": {
+        "/auth/2fa/recovery-codes": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Обновляет существующую транзакцию пользователя",
+                "description": "Выдаёт новый набор кодов восстановления, прежние перестают действовать.",
                 "consumes": [
                     "application/json"
                 ],
@@ -557,92 +634,78 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "auth"
                 ],
-                "summary": "Обновить транзакцию",
+                "summary": "Новые коды восстановления",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID транзакции",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Данные для обновления транзакции",
+                        "description": "Код из приложения или код восстановления",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/transactions.TransactionUpdate"
+                            "$ref": "#/definitions/auth.TwoFactorCodeInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Обновленная транзакция",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/transactions.TransactionInput"
+                            "$ref": "#/definitions/auth.RecoveryCodesResponse"
                         }
                     },
                     "400": {
-                        "description": "Ошибка валидации или неверные данные",
+                        "description": "Неверный код или 2FA не включена",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Транзакция не найдена",
+                    "429": {
+                        "description": "Слишком много неверных кодов",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка обновления транзакции",
+                        "description": "Ошибка создания кодов восстановления",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/2fa/setup": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Удаляет транзакцию пользователя по ее ID",
+                "description": "Создаёт новый TOTP-секрет и возвращает его вместе с otpauth:// URI и QR-кодом.\nДвухфакторная аутентификация включается только после подтверждения кодом в /auth/2fa/enable.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
-                ],
-                "summary": "Удалить транзакцию",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID транзакции",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "Начать подключение 2FA",
                 "responses": {
                     "200": {
-                        "description": "Транзакция удалена",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.SuccessResponse"
+                            "$ref": "#/definitions/auth.TwoFactorSetup"
                         }
                     },
-                    "404": {
-                        "description": "Транзакция не найдена",
+                    "409": {
+                        "description": "2FA уже включена",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при удалении транзакции",
+                        "description": "Ошибка создания секрета",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -650,43 +713,67 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/balance": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/auth/2fa/verify": {
+            "post": {
+                "description": "Обменивает токен, полученный при входе, и код 2FA на пару access- и refresh-токенов.\nВместо кода из приложения можно ввести код восстановления, каждый действует один раз.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Получает текущий баланс пользователя",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "auth"
+                ],
+                "summary": "Второй шаг входа",
+                "parameters": [
+                    {
+                        "description": "Токен второго шага и код",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.TwoFactorVerifyInput"
+                        }
+                    }
                 ],
-                "summary": "Получить текущий баланс пользователя",
                 "responses": {
                     "200": {
-                        "description": "Баланс пользователя",
+                        "description": "Успешная авторизация",
                         "schema": {
-                            "$ref": "#/definitions/response.BalanceResponse"
+                            "$ref": "#/definitions/response.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный код",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Недействительный токен второго шага",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много неверных кодов",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при получении баланса",
+                        "description": "Ошибка выдачи токенов",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Обновляет баланс пользователя",
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Авторизация пользователя с указанием почты и пароля. Возвращает короткоживущий access-токен\nи refresh-токен для получения новой пары через /auth/refresh.\nЕсли включена двухфакторная аутентификация, возвращает 202 и токен второго шага для /auth/2fa/verify",
                 "consumes": [
                     "application/json"
                 ],
@@ -694,79 +781,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "auth"
                 ],
-                "summary": "Обновить баланс пользователя",
+                "summary": "Авторизация",
                 "parameters": [
                     {
-                        "description": "Новый баланс",
+                        "description": "Данные пользователя",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/users.UpdateBalanceInput"
+                            "$ref": "#/definitions/auth.LoginInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Обновленный баланс",
+                        "description": "Успешная авторизация",
                         "schema": {
-                            "$ref": "#/definitions/response.BalanceResponse"
+                            "$ref": "#/definitions/response.TokenResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Требуется код двухфакторной аутентификации",
+                        "schema": {
+                            "$ref": "#/definitions/response.TwoFactorChallengeResponse"
                         }
                     },
                     "400": {
-                        "description": "Ошибка валидации",
+                        "description": "Описание ошибки валидации",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Ошибка при обновлении баланса",
+                    "401": {
+                        "description": "Неверный пароль",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/users/bonus": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Получает текущий баланс бонусов пользователя",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Users"
-                ],
-                "summary": "Получить текущий баланс бонусов пользователя",
-                "responses": {
-                    "200": {
-                        "description": "Бонусы пользователя",
+                    },
+                    "404": {
+                        "description": "Пользователя с такой почтой не существует",
                         "schema": {
-                            "$ref": "#/definitions/response.BonusResponse"
+                            "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при получении бонусов",
+                        "description": "Ошибка выдачи токенов",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Обновляет бонусы пользователя",
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "Завершает сессию refresh-токена: отзываются все её refresh-токены, а выданные access-токены перестают приниматься.",
                 "consumes": [
                     "application/json"
                 ],
@@ -774,35 +845,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "auth"
                 ],
-                "summary": "Обновить бонусов пользователя",
+                "summary": "Выход",
                 "parameters": [
                     {
-                        "description": "Новый баланс бонусов",
+                        "description": "Refresh-токен",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/users.UpdateBonusInput"
+                            "$ref": "#/definitions/auth.RefreshInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Обновленный баланс бонусов",
+                        "description": "Выход выполнен",
                         "schema": {
-                            "$ref": "#/definitions/response.BonusResponse"
+                            "$ref": "#/definitions/response.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Ошибка валидации",
+                        "description": "Описание ошибки валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Недействительный refresh-токен",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при обновлении баланса бонусов",
+                        "description": "Ошибка отзыва токена",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -810,13 +887,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/info": {
-            "get": {
+        "/auth/newVerify": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Отправляет новый код подтверждения, предыдущий код перестаёт действовать.\nКод действует 15 минут, повторно запросить его можно не чаще раза в минуту.",
                 "consumes": [
                     "application/json"
                 ],
@@ -824,104 +902,5292 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "auth"
                 ],
-                "summary": "Получить информацию о себе",
+                "summary": "Запрос на новое письмо с кодом",
                 "responses": {
                     "200": {
-                        "description": "Пользователь",
+                        "description": "Письмо отправлено",
                         "schema": {
-                            "$ref": "#/definitions/users.UserInfo"
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Аккаунт уже подтверждён",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Код недавно отправлен",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Пользователь не найден",
+                        "description": "Ошибка отправки письма",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "auth.LoginInput": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
-                "email": {
+        },
+        "/auth/password/forgot": {
+            "post": {
+                "description": "Отправляет на почту ссылку для сброса пароля. Ссылка действует час и один раз.\nПисьмо отправляется не чаще раза в минуту, действующих ссылок может быть не больше трёх.\nОтвет не зависит от того, зарегистрирована ли почта и отправлено ли письмо.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Запрос сброса пароля",
+                "parameters": [
+                    {
+                        "description": "Почта пользователя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.ForgotPasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Письмо отправлено, если почта зарегистрирована",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Описание ошибки валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password/reset": {
+            "post": {
+                "description": "Задаёт новый пароль по токену из письма. Токен действует один раз, после смены пароля\nзавершаются все сессии пользователя и отзываются остальные ссылки сброса.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Сброс пароля",
+                "parameters": [
+                    {
+                        "description": "Токен из письма и новый пароль",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.ResetPasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пароль изменён",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Недействительная ссылка или слабый пароль",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка смены пароля",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Обменивает refresh-токен на новую пару токенов. Каждый refresh-токен действует один раз:\nповторное использование считается утечкой, и все токены этого входа отзываются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Обновление токенов",
+                "parameters": [
+                    {
+                        "description": "Refresh-токен",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RefreshInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Новая пара токенов",
+                        "schema": {
+                            "$ref": "#/definitions/response.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Описание ошибки валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Недействительный refresh-токен",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка выдачи токенов",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Регистрация пользователя с указанием никнейма, почты, пароля. На почту отправляется код подтверждения",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Регистрация пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RegisterInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Успешная регистрация",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Описание ошибки валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Почта уже зарегистрированы",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Не удалось хешировать пароль или создать пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает устройства, с которых выполнен вход, последние использованные первыми.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Активные сессии",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/auth.SessionInfo"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении сессий",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Завершает все сессии пользователя. С exceptCurrent=true текущая сессия сохраняется.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Выйти на всех устройствах",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Не завершать текущую сессию",
+                        "name": "exceptCurrent",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сессии завершены",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка завершения сессий",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выходит из аккаунта на выбранном устройстве. Токены сессии сразу перестают приниматься.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Завершить сессию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID сессии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сессия завершена",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Сессия не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка завершения сессии",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Подтверждение аккаунта кодом из письма. После 5 неверных попыток код аннулируется и нужно запросить новый",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Подтверждение аккаунта",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.VerificationCodeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Аккаунт успешно подтверждён",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный или просроченный код подтверждения",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Аккаунт уже подтверждён",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышено число попыток",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Пользователь не существует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Список бюджетов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Budget"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении бюджетов",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт лимит расходов на неделю, месяц или год по категории или группе категорий.\nБез категорий бюджет охватывает все расходы. Учитываются только расходы в валюте бюджета.\nПри rollover=true остаток периода, в том числе отрицательный, переносится на следующий.\nПри расходовании 80% и 100% доступной суммы пользователь получает письмо, если не отключил уведомления.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Создать бюджет",
+                "parameters": [
+                    {
+                        "description": "Бюджет",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/budgets.BudgetInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Budget"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения бюджета",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets/progress": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает исполнение каждого бюджета пользователя за текущий период или период, в который попадает date.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Исполнение всех бюджетов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Дата внутри периода, YYYY-MM-DD",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/budgets.Progress"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверная дата",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при расчёте бюджетов",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Получить бюджет",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бюджета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Budget"
+                        }
+                    },
+                    "404": {
+                        "description": "Бюджет не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заменяет бюджет целиком. Если start не указан, сохраняется прежний.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Изменить бюджет",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бюджета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Бюджет",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/budgets.BudgetInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Budget"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Бюджет не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения бюджета",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Удалить бюджет",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бюджета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Бюджет удалён",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Бюджет не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка удаления бюджета",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets/{id}/progress": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает расходы за период бюджета, остаток и прогноз расходов к концу периода при текущем темпе.\nПо умолчанию — текущий период, параметр date выбирает период, в который попадает дата.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Исполнение бюджета",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бюджета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата внутри периода, YYYY-MM-DD",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/budgets.Progress"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверная дата",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Бюджет не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при расчёте бюджета",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить все категории пользователя или категории по умолчанию.\nНазвания категорий по умолчанию переводятся по параметру lang или заголовку Accept-Language",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Получить категории",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Язык названий категорий по умолчанию (ru, en)",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Category"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении категорий",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создать новую категорию для пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Создать категорию",
+                "parameters": [
+                    {
+                        "description": "Категория для создания",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/%D1%81ategory.CreateCategoryInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка создания категории",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновить информацию о категории пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Обновить категорию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Категория для обновления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/%D1%81ategory.UpdateCategoryInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Категория успешно обновлена",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Категория не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка обновления категории",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удалить категорию пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Удалить категорию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Категория успешно удалена",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Категория не найдена или не принадлежит пользователю",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка удаления категории",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/goals": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Goals"
+                ],
+                "summary": "Список целей накоплений",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Goal"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении целей",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт цель с целевой суммой, сроком и счётом, на котором копятся деньги. Валюта цели — валюта счёта.\nНакопления цели складываются из взносов и снятий, а не из баланса счёта, поэтому на одном счёте можно копить на несколько целей.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Goals"
+                ],
+                "summary": "Создать цель накоплений",
+                "parameters": [
+                    {
+                        "description": "Цель",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/goals.GoalInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Goal"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения цели",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/goals/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Goals"
+                ],
+                "summary": "Получить цель накоплений",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID цели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Goal"
+                        }
+                    },
+                    "404": {
+                        "description": "Цель не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заменяет цель целиком. Счёт цели нельзя сменить, если по ней уже были взносы.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Goals"
+                ],
+                "summary": "Изменить цель накоплений",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID цели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Цель",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/goals.GoalInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Goal"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Цель не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения цели",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет цель. Транзакции взносов и снятий остаются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Goals"
+                ],
+                "summary": "Удалить цель накоплений",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID цели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Цель удалена",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Цель не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка удаления цели",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/goals/{id}/contribute": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт перевод со счёта account (по умолчанию основного) на счёт цели, связанный с целью.\namount списывается в валюте счёта списания; если валюты различаются, укажите counterAmount в валюте цели.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Goals"
+                ],
+                "summary": "Внести деньги на цель",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID цели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Взнос",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/goals.ContributionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Цель не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка создания транзакции",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/goals/{id}/progress": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает накопленную сумму и процент выполнения, ежемесячный взнос, нужный, чтобы успеть к сроку,\nи прогноз даты достижения цели по среднему темпу взносов с первого взноса.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Goals"
+                ],
+                "summary": "Прогресс цели накоплений",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID цели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/goals.Progress"
+                        }
+                    },
+                    "404": {
+                        "description": "Цель не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при расчёте прогресса",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/goals/{id}/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Goals"
+                ],
+                "summary": "Взносы и снятия цели",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID цели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Transaction"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Цель не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении транзакций",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/goals/{id}/withdraw": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "С account создаёт перевод со счёта цели на этот счёт, без него — расход со счёта цели в категории category,\nнапример при покупке, на которую копили. amount указывается в валюте цели и не может превышать накопленное.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Goals"
+                ],
+                "summary": "Снять деньги с цели",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID цели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Снятие",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/goals.ContributionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Цель не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка создания транзакции",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recurring": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recurring"
+                ],
+                "summary": "Список повторяющихся операций",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RecurringRule"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении правил",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт правило: шаблон транзакции (доход, расход или перевод) и расписание — частоту, интервал,\nдату окончания until или число повторений count и перенос с выходных adjust (для ежедневных правил недоступен).\nТранзакции создаёт планировщик, наступившие повторения — сразу, в том числе с даты начала в прошлом.\nСозданные транзакции помечены полями RecurringID и Occurrence.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recurring"
+                ],
+                "summary": "Создать повторяющуюся операцию",
+                "parameters": [
+                    {
+                        "description": "Правило",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/recurring.RuleInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения правила",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recurring/upcoming": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает ещё не созданные повторения всех правил или одного правила за days дней, по дате. Пропущенные повторения помечены skipped.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recurring"
+                ],
+                "summary": "Ближайшие повторения",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Период в днях, по умолчанию 30, не больше 366",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID правила",
+                        "name": "rule",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/recurring.Upcoming"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Правило не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении правил",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recurring/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recurring"
+                ],
+                "summary": "Получить повторяющуюся операцию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID правила",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringRule"
+                        }
+                    },
+                    "404": {
+                        "description": "Правило не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заменяет правило целиком. Изменения касаются только будущих повторений, созданные транзакции не меняются.\nПри изменении расписания серия продолжается с первого повторения нового расписания после последнего созданного.\npaused=true приостанавливает создание транзакций. Без start сохраняется прежняя дата начала.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recurring"
+                ],
+                "summary": "Изменить серию повторяющихся операций",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID правила",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Правило",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/recurring.RuleInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Правило не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения правила",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет правило. Уже созданные транзакции остаются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recurring"
+                ],
+                "summary": "Удалить повторяющуюся операцию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID правила",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Правило удалено",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Правило не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка удаления правила",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recurring/{id}/skip": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отменяет одно ещё не созданное повторение. Дату можно указать по расписанию или после переноса с выходных.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recurring"
+                ],
+                "summary": "Пропустить повторение",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID правила",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Дата повторения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/recurring.SkipInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Повторения на эту дату нет",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Правило не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения правила",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recurring/{id}/skip/{date}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recurring"
+                ],
+                "summary": "Вернуть пропущенное повторение",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID правила",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата повторения, YYYY-MM-DD",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Повторения на эту дату нет",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Правило не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения правила",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает транзакции пользователя постранично. Для следующей страницы передайте nextCursor в параметре cursor\nс теми же фильтрами и сортировкой. Фильтры совпадают с /transactions/search. total и sums считаются по всему фильтру, общее количество дублируется в заголовке X-Total-Count",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Получить транзакции",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поле сортировки: date (по умолчанию), amount, title или created",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Порядок: desc (по умолчанию) или asc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, не больше 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Полнотекстовый поиск по названию и описанию: слова, «фразы» в кавычках, -исключения",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название транзакции (частичное совпадение)",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Приблизительная сумма транзакции (±10%)",
+                        "name": "amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Минимальная сумма",
+                        "name": "amountMin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Максимальная сумма",
+                        "name": "amountMax",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Приблизительное количество бонусов",
+                        "name": "bonusChange",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата транзакции (формат YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода включительно (YYYY-MM-DD)",
+                        "name": "dateFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "dateTo",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "today",
+                            "yesterday",
+                            "this_week",
+                            "last_week",
+                            "this_month",
+                            "last_month",
+                            "this_year",
+                            "last_year",
+                            "last_7_days",
+                            "last_30_days"
+                        ],
+                        "type": "string",
+                        "description": "Относительный период",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "ID категорий",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "ID счетов",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Валюты",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Типы транзакций (income, expense, transfer)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип бонуса",
+                        "name": "typeBonus",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Исключить категории",
+                        "name": "excludeCategory",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Исключить счета",
+                        "name": "excludeAccount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Исключить валюты",
+                        "name": "excludeCurrency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Исключить типы транзакций",
+                        "name": "excludeType",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница транзакций",
+                        "schema": {
+                            "$ref": "#/definitions/transactions.TransactionList"
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Количество транзакций по фильтру"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении транзакций",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создает новую транзакцию для пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Создать транзакцию",
+                "parameters": [
+                    {
+                        "description": "Транзакция для создания",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transactions.TransactionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/transactions.TransactionInput"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка создания транзакции",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выгружает транзакции в CSV (UTF-8, разделитель «,»), XLSX, JSON или журнал ledger/hledger и beancount в хронологическом порядке.\nВ журнале счета становятся счетами Assets (кредитные карты — Liabilities), категории — счетами Income и Expenses,\nкомиссии переводов — Expenses:Комиссии, бонусы учитываются товаром BONUS на счёте Assets:Бонусы. Без фильтров в журнал\nзаписываются начальные остатки счетов и бонусов. Журнал можно загрузить обратно через /transactions/import.\nФильтры совпадают с /transactions/search. Вместо ID указываются названия категорий и счетов, названия категорий по умолчанию переводятся по lang или Accept-Language.\nФайл отдаётся потоком, транзакции читаются из базы частями.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Выгрузить транзакции",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx",
+                            "json",
+                            "ledger",
+                            "beancount"
+                        ],
+                        "type": "string",
+                        "description": "Формат файла, по умолчанию csv",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык названий категорий",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Полнотекстовый поиск по названию и описанию: слова, «фразы» в кавычках, -исключения",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название транзакции (частичное совпадение)",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Приблизительная сумма транзакции (±10%)",
+                        "name": "amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Минимальная сумма",
+                        "name": "amountMin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Максимальная сумма",
+                        "name": "amountMax",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Приблизительное количество бонусов",
+                        "name": "bonusChange",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата транзакции (формат YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода включительно (YYYY-MM-DD)",
+                        "name": "dateFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "dateTo",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "today",
+                            "yesterday",
+                            "this_week",
+                            "last_week",
+                            "this_month",
+                            "last_month",
+                            "this_year",
+                            "last_year",
+                            "last_7_days",
+                            "last_30_days"
+                        ],
+                        "type": "string",
+                        "description": "Относительный период",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "ID категорий",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "ID счетов",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Валюты",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Типы транзакций (income, expense, transfer)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип бонуса",
+                        "name": "typeBonus",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Исключить категории",
+                        "name": "excludeCategory",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Исключить счета",
+                        "name": "excludeAccount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Исключить валюты",
+                        "name": "excludeCurrency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Исключить типы транзакций",
+                        "name": "excludeType",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл выгрузки; для format=json — массив строк",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/transactions.ExportRow"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при выгрузке транзакций",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Разбирает выписку и возвращает предпросмотр с ошибками по строкам, ничего не сохраняя.\nС commit=true сохраняет все операции одной транзакцией и один раз обновляет баланс счёта.\nЕсли в файле есть строки с ошибками, импорт отклоняется, пока не передан skipInvalid=true.\nОперации OFX с уже импортированным на этот счёт FITID помечаются как дубли и пропускаются.\nЖурналы ledger, hledger и beancount, в том числе выгруженные из /transactions/export: счета Assets и Liabilities ищутся по названию среди счетов пользователя,\nкатегории берутся из счетов Income и Expenses, проводки между двумя счетами становятся переводами, строки в BONUS — бонусами. Дубли ищутся по коду проводки или метаданным id.\nДля CSV формат колонок задаётся сохранённым mappingId или JSON-описанием в поле mapping (его можно сохранить, указав saveAs)",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import"
+                ],
+                "summary": "Импорт выписки CSV, OFX, QIF или журнала ledger/beancount",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Файл выписки",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ofx",
+                            "qif",
+                            "ledger",
+                            "beancount"
+                        ],
+                        "type": "string",
+                        "description": "Формат файла, по умолчанию по расширению",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID счёта, по умолчанию основной",
+                        "name": "account",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID сохранённого формата CSV",
+                        "name": "mappingId",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат CSV в JSON, как в POST /transactions/import/mappings",
+                        "name": "mapping",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сохранить формат из mapping под этим названием",
+                        "name": "saveAs",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Кодировка QIF: utf-8 (по умолчанию) или cp1251",
+                        "name": "encoding",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат дат QIF, по умолчанию MM/DD/YYYY",
+                        "name": "dateFormat",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Сохранить операции",
+                        "name": "commit",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Пропустить строки с ошибками",
+                        "name": "skipInvalid",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Предпросмотр",
+                        "schema": {
+                            "$ref": "#/definitions/importer.Preview"
+                        }
+                    },
+                    "201": {
+                        "description": "Операции импортированы",
+                        "schema": {
+                            "$ref": "#/definitions/importer.Preview"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в файле или параметрах",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Формат не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка импорта",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/import/mappings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить описания форматов CSV-выписок, сохранённые пользователем",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import"
+                ],
+                "summary": "Получить сохранённые форматы выписок",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ImportMapping"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении форматов",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сохраняет описание формата CSV-выписки для повторного импорта",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import"
+                ],
+                "summary": "Сохранить формат выписки",
+                "parameters": [
+                    {
+                        "description": "Формат выписки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/importer.MappingInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportMapping"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения формата",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/import/mappings/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заменяет сохранённое описание формата CSV-выписки целиком",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import"
+                ],
+                "summary": "Обновить формат выписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID формата",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Формат выписки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/importer.MappingInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportMapping"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Формат не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения формата",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import"
+                ],
+                "summary": "Удалить формат выписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID формата",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Формат удалён",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Формат не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка удаления формата",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/parse": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Распознаёт текст уведомления пользовательскими шаблонами и правилами банков (sber, tbank, alfa, vtb) и возвращает черновик транзакции: сумму, магазин в названии, тип, валюту и остаток.\nСчёт подбирается по валюте, категория — по прошлым операциям с тем же названием. Транзакция не создаётся.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Разобрать SMS или push-уведомление банка",
+                "parameters": [
+                    {
+                        "description": "Текст уведомления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/banksms.ParseInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/banksms.ParseResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Текст не распознан",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка разбора",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/parse/templates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить собственные шаблоны разбора SMS и push-уведомлений банков",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Получить шаблоны уведомлений",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SMSTemplate"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении шаблонов",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Шаблон — регулярное выражение с подстановками {amount}, {currency}, {merchant}, {balance}, {card}, {date}, {time} или группами (?P\u003camount\u003e...) с теми же именами. Сумма обязательна, регистр не учитывается.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Создать шаблон уведомления",
+                "parameters": [
+                    {
+                        "description": "Шаблон",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/banksms.TemplateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SMSTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения шаблона",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/parse/templates/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заменяет шаблон целиком",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Обновить шаблон уведомления",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID шаблона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Шаблон",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/banksms.TemplateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SMSTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Шаблон не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения шаблона",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Удалить шаблон уведомления",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID шаблона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Шаблон удалён",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Шаблон не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка удаления шаблона",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/receipt": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт расход (или доход для возврата) по строке из QR-кода кассового чека: дата, сумма и фискальные признаки fn, i, fp берутся из чека. Один чек нельзя добавить дважды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Добавить транзакцию по QR-коду чека",
+                "parameters": [
+                    {
+                        "description": "Строка QR-кода и данные транзакции",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transactions.ReceiptInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный QR-код или данные транзакции",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Чек уже добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка создания транзакции",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/receipt/image": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Распознаёт QR-код чека на изображении на сервере (утилитой zbarimg, путь задаётся QR_DECODER) и создаёт транзакцию так же, как POST /transactions/receipt.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Добавить транзакцию по фотографии QR-кода чека",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Изображение с QR-кодом, до 10 МБ",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название, по умолчанию «Покупка по чеку»",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Описание",
+                        "name": "description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID счёта, по умолчанию основной",
+                        "name": "account",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Изменение бонусов",
+                        "name": "bonusChange",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип изменения бонусов",
+                        "name": "typeBonus",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "QR-код не найден или данные неверны",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Чек уже добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка создания транзакции",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Распознавание изображений не настроено",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ищет транзакции пользователя по различным опциональным параметрам. Все условия объединяются через И,\nзначения одного списочного параметра — через ИЛИ. Период, дата и диапазон дат пересекаются.\nС параметром q результаты сортируются по релевантности, а titleHighlight и descriptionHighlight\nсодержат HTML-фрагменты с найденными словами в теге \u003cmark\u003e. Остальной текст фрагментов экранирован, title и description возвращаются как есть",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Поиск транзакций",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Полнотекстовый поиск по названию и описанию: слова, «фразы» в кавычках, -исключения",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название транзакции (частичное совпадение)",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Приблизительная сумма транзакции (±10%)",
+                        "name": "amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Минимальная сумма",
+                        "name": "amountMin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Максимальная сумма",
+                        "name": "amountMax",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Приблизительное количество бонусов",
+                        "name": "bonusChange",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата транзакции (формат YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода включительно (YYYY-MM-DD)",
+                        "name": "dateFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "dateTo",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "today",
+                            "yesterday",
+                            "this_week",
+                            "last_week",
+                            "this_month",
+                            "last_month",
+                            "this_year",
+                            "last_year",
+                            "last_7_days",
+                            "last_30_days"
+                        ],
+                        "type": "string",
+                        "description": "Относительный период",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "ID категорий",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "ID счетов",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Валюты",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Типы транзакций (income, expense, transfer)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип бонуса",
+                        "name": "typeBonus",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Исключить категории",
+                        "name": "excludeCategory",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Исключить счета",
+                        "name": "excludeAccount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Исключить валюты",
+                        "name": "excludeCurrency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Исключить типы транзакций",
+                        "name": "excludeType",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Найденные транзакции",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/repository.SearchHit"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при поиске транзакций",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновляет существующую транзакцию пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Обновить транзакцию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для обновления транзакции",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transactions.TransactionUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленная транзакция",
+                        "schema": {
+                            "$ref": "#/definitions/transactions.TransactionInput"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации или неверные данные",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Транзакция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка обновления транзакции",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет транзакцию пользователя по ее ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Удалить транзакцию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Транзакция удалена",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Транзакция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при удалении транзакции",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Списывает сумму и комиссию с одного счёта и зачисляет на другой одной транзакцией.\nДля счетов в разных валютах нужно указать counterAmount или rate. Переводы не учитываются как доходы и расходы",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Перевод между счетами",
+                "parameters": [
+                    {
+                        "description": "Перевод",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transactions.TransferInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка создания перевода",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Изменяет перевод целиком: балансы обоих счетов пересчитываются в одной транзакции.\nПри смене суммы между разными валютами нужно заново указать counterAmount или rate",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Обновить перевод",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID перевода",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для обновления перевода",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transactions.TransferUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Перевод не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка обновления перевода",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получает сумму балансов всех неархивных счетов: balance — в рублях, totals — по каждой валюте",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Получить текущий баланс пользователя",
+                "responses": {
+                    "200": {
+                        "description": "Баланс пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/response.BalanceResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении баланса",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Устанавливает баланс основного счёта пользователя. Для остальных счетов используйте PUT /accounts/{id}",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Обновить баланс пользователя",
+                "parameters": [
+                    {
+                        "description": "Новый баланс",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.UpdateBalanceInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный баланс",
+                        "schema": {
+                            "$ref": "#/definitions/response.BalanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при обновлении баланса",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/balance/check": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сравнивает сохранённые балансы счетов и бонусов с суммой транзакций, ничего не изменяя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Сверить баланс с транзакциями",
+                "responses": {
+                    "200": {
+                        "description": "Результат сверки",
+                        "schema": {
+                            "$ref": "#/definitions/ledger.Report"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сверки баланса",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/balance/recalculate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Приводит балансы счетов и бонусов к значениям, вычисленным по транзакциям. Возвращает расхождения до исправления",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Пересчитать баланс по транзакциям",
+                "responses": {
+                    "200": {
+                        "description": "Результат пересчёта",
+                        "schema": {
+                            "$ref": "#/definitions/ledger.Report"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка пересчёта баланса",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/bonus": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получает текущий баланс бонусов пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Получить текущий баланс бонусов пользователя",
+                "responses": {
+                    "200": {
+                        "description": "Бонусы пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/response.BonusResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении бонусов",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновляет бонусы пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Обновить бонусов пользователя",
+                "parameters": [
+                    {
+                        "description": "Новый баланс бонусов",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.UpdateBonusInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный баланс бонусов",
+                        "schema": {
+                            "$ref": "#/definitions/response.BonusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при обновлении баланса бонусов",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/info": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Получить информацию о себе",
+                "responses": {
+                    "200": {
+                        "description": "Пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/users.UserInfo"
+                        }
+                    },
+                    "500": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Настройки уведомлений",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.NotificationSettings"
+                        }
+                    },
+                    "500": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет переданные настройки, остальные остаются прежними. Письма отправляются только на подтверждённую почту.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Изменить настройки уведомлений",
+                "parameters": [
+                    {
+                        "description": "Настройки уведомлений",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.UpdateNotificationsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.NotificationSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при сохранении настроек",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "accounts.CreateAccountInput": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "currency": {
+                    "description": "код ISO 4217, по умолчанию RUB",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "openingBalance": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "card",
+                        "credit",
+                        "cash",
+                        "savings"
+                    ]
+                }
+            }
+        },
+        "accounts.UpdateAccountInput": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "openingBalance": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "card",
+                        "credit",
+                        "cash",
+                        "savings"
+                    ]
+                }
+            }
+        },
+        "auth.ForgotPasswordInput": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.LoginInput": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "deviceName": {
+                    "description": "по умолчанию определяется по User-Agent",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recoveryCodes": {
+                    "description": "показываются один раз",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "auth.RefreshInput": {
+            "type": "object",
+            "required": [
+                "refreshToken"
+            ],
+            "properties": {
+                "refreshToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RegisterInput": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.ResetPasswordInput": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.SessionInfo": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "current": {
+                    "description": "сессия, которой выполнен запрос",
+                    "type": "boolean"
+                },
+                "deviceName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "userAgent": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.TwoFactorCodeInput": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "description": "код из приложения или код восстановления",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.TwoFactorSetup": {
+            "type": "object",
+            "properties": {
+                "qrCode": {
+                    "description": "PNG с URI в виде data:image/png;base64,...",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "для ручного ввода",
+                    "type": "string"
+                },
+                "uri": {
+                    "description": "otpauth://",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.TwoFactorStatus": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "recoveryCodesLeft": {
+                    "type": "integer"
+                }
+            }
+        },
+        "auth.TwoFactorVerifyInput": {
+            "type": "object",
+            "required": [
+                "challengeToken",
+                "code"
+            ],
+            "properties": {
+                "challengeToken": {
+                    "type": "string"
+                },
+                "code": {
+                    "description": "код из приложения или код восстановления",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.VerificationCodeInput": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "banksms.ParseInput": {
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "bank": {
+                    "description": "если не указан, банк определяется по тексту",
+                    "type": "string",
+                    "enum": [
+                        "sber",
+                        "tbank",
+                        "alfa",
+                        "vtb"
+                    ]
+                },
+                "text": {
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "banksms.ParseResult": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "остаток по счёту из уведомления",
+                    "type": "number"
+                },
+                "bank": {
+                    "type": "string"
+                },
+                "card": {
+                    "type": "string"
+                },
+                "template": {
+                    "description": "ID пользовательского шаблона",
+                    "type": "integer"
+                },
+                "transaction": {
+                    "$ref": "#/definitions/transactions.TransactionInput"
+                }
+            }
+        },
+        "banksms.TemplateInput": {
+            "type": "object",
+            "required": [
+                "name",
+                "pattern",
+                "type"
+            ],
+            "properties": {
+                "account": {
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "pattern": {
+                    "description": "например: Списание {amount}{currency} {merchant} Остаток {balance}",
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "sample": {
+                    "description": "пример уведомления, который шаблон должен распознать",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "income",
+                        "expense"
+                    ]
+                }
+            }
+        },
+        "budgets.BudgetInput": {
+            "type": "object",
+            "required": [
+                "limit",
+                "name"
+            ],
+            "properties": {
+                "categories": {
+                    "description": "пустой список — все расходы",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "currency": {
+                    "description": "по умолчанию валюта основного счёта",
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "period": {
+                    "description": "по умолчанию monthly",
+                    "type": "string",
+                    "enum": [
+                        "weekly",
+                        "monthly",
+                        "yearly"
+                    ]
+                },
+                "rollover": {
+                    "type": "boolean"
+                },
+                "start": {
+                    "description": "с какого периода действует бюджет, по умолчанию с текущего",
+                    "type": "string"
+                }
+            }
+        },
+        "budgets.Progress": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "лимит вместе с переносом",
+                    "type": "number"
+                },
+                "budget": {
+                    "type": "integer"
+                },
+                "carried": {
+                    "description": "остаток прошлых периодов, при перерасходе отрицательный",
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "over": {
+                    "description": "потрачено больше доступного",
+                    "type": "boolean"
+                },
+                "percent": {
+                    "description": "доля потраченного от доступного, %",
+                    "type": "number"
+                },
+                "periodEnd": {
+                    "description": "не включительно",
+                    "type": "string"
+                },
+                "periodStart": {
+                    "type": "string"
+                },
+                "projected": {
+                    "description": "прогноз расходов к концу периода",
+                    "type": "number"
+                },
+                "projectedOver": {
+                    "description": "ProjectedOver — при текущем темпе к концу периода доступное будет превышено.",
+                    "type": "boolean"
+                },
+                "remaining": {
+                    "type": "number"
+                },
+                "spent": {
+                    "type": "number"
+                }
+            }
+        },
+        "goals.ContributionInput": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "account": {
+                    "description": "счёт списания взноса или зачисления снятия",
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "description": "категория расхода при снятии без счёта",
+                    "type": "integer"
+                },
+                "counterAmount": {
+                    "description": "сумма зачисления, если валюты счетов различаются",
+                    "type": "number"
+                },
+                "date": {
+                    "description": "по умолчанию сейчас",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "title": {
+                    "description": "по умолчанию название цели",
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "goals.GoalInput": {
+            "type": "object",
+            "required": [
+                "account",
+                "name",
+                "target"
+            ],
+            "properties": {
+                "account": {
+                    "description": "счёт, на котором копятся деньги",
+                    "type": "integer"
+                },
+                "currency": {
+                    "description": "должна совпадать с валютой счёта",
+                    "type": "string"
+                },
+                "deadline": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "target": {
+                    "type": "number"
+                }
+            }
+        },
+        "goals.Progress": {
+            "type": "object",
+            "properties": {
+                "averageMonthly": {
+                    "description": "AverageMonthly — средний чистый взнос в месяц с первого взноса.",
+                    "type": "number"
+                },
+                "completed": {
+                    "type": "boolean"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "deadline": {
+                    "type": "string"
+                },
+                "goal": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "onTrack": {
+                    "description": "OnTrack — успевает ли цель к сроку при среднем темпе. Пусто, если срок не задан.",
+                    "type": "boolean"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "projectedDate": {
+                    "description": "ProjectedDate — когда цель будет достигнута при среднем темпе взносов.\nПусто, если цель достигнута или накопления не растут.",
+                    "type": "string"
+                },
+                "remaining": {
+                    "type": "number"
+                },
+                "requiredMonthly": {
+                    "description": "RequiredMonthly — сколько вносить в месяц, чтобы успеть к сроку. После срока — весь остаток.",
+                    "type": "number"
+                },
+                "saved": {
+                    "type": "number"
+                },
+                "target": {
+                    "type": "number"
+                }
+            }
+        },
+        "gorm.DeletedAt": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
+        },
+        "importer.MappingInput": {
+            "type": "object",
+            "required": [
+                "dateColumn",
+                "dateFormat",
+                "titleColumn"
+            ],
+            "properties": {
+                "amountColumn": {
+                    "type": "string"
+                },
+                "amountSign": {
+                    "description": "по умолчанию negative_expense",
+                    "type": "string",
+                    "enum": [
+                        "negative_expense",
+                        "negative_income",
+                        "separate"
+                    ]
+                },
+                "categoryColumn": {
+                    "type": "string"
+                },
+                "currencyColumn": {
+                    "type": "string"
+                },
+                "dateColumn": {
+                    "type": "string"
+                },
+                "dateFormat": {
+                    "description": "например DD.MM.YYYY",
+                    "type": "string"
+                },
+                "decimalSeparator": {
+                    "description": "по умолчанию \".\"",
+                    "type": "string"
+                },
+                "delimiter": {
+                    "description": "по умолчанию \",\"; для табуляции \\t",
+                    "type": "string"
+                },
+                "descriptionColumn": {
+                    "type": "string"
+                },
+                "encoding": {
+                    "description": "utf-8 (по умолчанию) или cp1251",
+                    "type": "string"
+                },
+                "expenseColumn": {
+                    "description": "для amountSign=separate",
+                    "type": "string"
+                },
+                "hasHeader": {
+                    "description": "по умолчанию true",
+                    "type": "boolean"
+                },
+                "incomeColumn": {
+                    "description": "для amountSign=separate",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "skipRows": {
+                    "description": "строки перед заголовком",
+                    "type": "integer"
+                },
+                "titleColumn": {
+                    "type": "string"
+                }
+            }
+        },
+        "importer.Preview": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "type": "integer"
+                },
+                "committed": {
+                    "type": "boolean"
+                },
+                "duplicate": {
+                    "type": "integer"
+                },
+                "expense": {
+                    "description": "сумма корректных расходов",
+                    "type": "number"
+                },
+                "income": {
+                    "description": "сумма корректных доходов",
+                    "type": "number"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/importer.Row"
+                    }
+                },
+                "valid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "importer.Row": {
+            "type": "object",
+            "properties": {
+                "duplicate": {
+                    "description": "операция уже импортирована, будет пропущена",
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "transaction": {
+                    "$ref": "#/definitions/models.Transaction"
+                }
+            }
+        },
+        "ledger.AccountDrift": {
+            "type": "object",
+            "properties": {
+                "accountId": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "drift": {
+                    "description": "Stored - Expected",
+                    "type": "number"
+                },
+                "expected": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "stored": {
+                    "type": "number"
+                }
+            }
+        },
+        "ledger.BonusDrift": {
+            "type": "object",
+            "properties": {
+                "drift": {
+                    "type": "number"
+                },
+                "expected": {
+                    "type": "number"
+                },
+                "stored": {
+                    "type": "number"
+                }
+            }
+        },
+        "ledger.Report": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ledger.AccountDrift"
+                    }
+                },
+                "bonus": {
+                    "$ref": "#/definitions/ledger.BonusDrift"
+                },
+                "fixed": {
+                    "description": "балансы приведены к ожидаемым",
+                    "type": "boolean"
+                },
+                "hasDrift": {
+                    "type": "boolean"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Account": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                },
+                "balance": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "openingBalance": {
+                    "type": "number"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.AccountType"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AccountType": {
+            "type": "string",
+            "enum": [
+                "card",
+                "credit",
+                "cash",
+                "savings"
+            ],
+            "x-enum-comments": {
+                "AccountCard": "дебетовая карта",
+                "AccountCash": "наличные",
+                "AccountCredit": "кредитная карта",
+                "AccountSavings": "накопительный счёт"
+            },
+            "x-enum-varnames": [
+                "AccountCard",
+                "AccountCredit",
+                "AccountCash",
+                "AccountSavings"
+            ]
+        },
+        "models.Budget": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "period": {
+                    "description": "weekly, monthly или yearly",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Frequency"
+                        }
+                    ]
+                },
+                "rollover": {
+                    "description": "Rollover переносит остаток (или перерасход) каждого периода на следующий.",
+                    "type": "boolean"
+                },
+                "start": {
+                    "description": "начало первого периода бюджета",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BusinessDayAdjustment": {
+            "type": "string",
+            "enum": [
+                "none",
+                "following",
+                "preceding",
+                "modified_following"
+            ],
+            "x-enum-comments": {
+                "AdjustFollowing": "на следующий рабочий день",
+                "AdjustModifiedFollowing": "на следующий, если он в том же месяце, иначе на предыдущий",
+                "AdjustPreceding": "на предыдущий рабочий день"
+            },
+            "x-enum-varnames": [
+                "AdjustNone",
+                "AdjustFollowing",
+                "AdjustPreceding",
+                "AdjustModifiedFollowing"
+            ]
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "isDefault": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "retired": {
+                    "description": "дефолтная категория скрыта для новых транзакций",
+                    "type": "boolean"
+                },
+                "slug": {
+                    "description": "код дефолтной категории, у пользовательских nil",
+                    "type": "string"
+                },
+                "translations": {
+                    "$ref": "#/definitions/models.Translations"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "description": "nil для дефолтных категорий",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Frequency": {
+            "type": "string",
+            "enum": [
+                "daily",
+                "weekly",
+                "monthly",
+                "yearly"
+            ],
+            "x-enum-varnames": [
+                "Daily",
+                "Weekly",
+                "Monthly",
+                "Yearly"
+            ]
+        },
+        "models.Goal": {
+            "type": "object",
+            "properties": {
+                "accountID": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "валюта связанного счёта",
+                    "type": "string"
+                },
+                "deadline": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "number"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportMapping": {
+            "type": "object",
+            "properties": {
+                "amountColumn": {
+                    "type": "string"
+                },
+                "amountSign": {
+                    "type": "string"
+                },
+                "categoryColumn": {
+                    "description": "название категории пользователя или встроенной",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currencyColumn": {
+                    "type": "string"
+                },
+                "dateColumn": {
+                    "type": "string"
+                },
+                "dateFormat": {
+                    "description": "например DD.MM.YYYY HH:mm",
+                    "type": "string"
+                },
+                "decimalSeparator": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "delimiter": {
+                    "type": "string"
+                },
+                "descriptionColumn": {
+                    "type": "string"
+                },
+                "encoding": {
+                    "description": "utf-8 или cp1251",
+                    "type": "string"
+                },
+                "expenseColumn": {
+                    "type": "string"
+                },
+                "hasHeader": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "incomeColumn": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "skipRows": {
+                    "description": "строки перед заголовком",
+                    "type": "integer"
+                },
+                "titleColumn": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RecurringRule": {
+            "type": "object",
+            "properties": {
+                "accountID": {
+                    "type": "integer"
+                },
+                "adjust": {
+                    "$ref": "#/definitions/models.BusinessDayAdjustment"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "type": "integer"
+                },
+                "count": {
+                    "description": "число повторений, 0 — без ограничения",
+                    "type": "integer"
+                },
+                "counterAccountID": {
+                    "description": "счёт зачисления для переводов",
+                    "type": "integer"
+                },
+                "counterAmount": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "description": "Расписание",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Frequency"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "nextIndex": {
+                    "description": "Состояние планировщика: номер и дата следующего несозданного повторения.\nNextRun пуст, когда повторения закончились.",
+                    "type": "integer"
+                },
+                "nextRun": {
+                    "type": "string"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "skipped": {
+                    "description": "пропущенные повторения по дате по расписанию",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "start": {
+                    "description": "первое повторение вместе со временем суток",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Шаблон транзакции",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TransactionType"
+                        }
+                    ]
+                },
+                "until": {
+                    "description": "последний день повторений включительно",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SMSTemplate": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "description": "счёт для черновика, если задан",
+                    "type": "integer"
+                },
+                "category": {
+                    "description": "категория для черновика, если задана",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "income или expense",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TransactionType"
+                        }
+                    ]
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Transaction": {
+            "type": "object",
+            "properties": {
+                "accountID": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "bonusChange": {
+                    "type": "number"
+                },
+                "bonusType": {
+                    "$ref": "#/definitions/models.TransactionType"
+                },
+                "category": {
+                    "type": "integer"
+                },
+                "counterAccountID": {
+                    "description": "Поля перевода: Amount списывается с AccountID вместе с Fee,\nCounterAmount зачисляется на CounterAccountID в его валюте.",
+                    "type": "integer"
+                },
+                "counterAmount": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "externalID": {
+                    "description": "идентификатор операции в банке (FITID) для импорта без дублей",
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "number"
+                },
+                "fiscalDocument": {
+                    "type": "string"
+                },
+                "fiscalDrive": {
+                    "description": "Фискальные признаки кассового чека из QR-кода: номер фискального\nнакопителя (fn), номер документа (i) и фискальный признак (fp).",
+                    "type": "string"
+                },
+                "fiscalSign": {
+                    "type": "string"
+                },
+                "goalID": {
+                    "description": "цель накоплений, для взносов и снятий",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "occurrence": {
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "курс пересчёта для переводов между валютами",
+                    "type": "string"
+                },
+                "recurringID": {
+                    "description": "Повторяющаяся операция, создавшая транзакцию, и дата повторения\nпо расписанию: по этой паре повторение не создаётся дважды.",
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "income или expense //доход или расход",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TransactionType"
+                        }
+                    ]
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TransactionType": {
+            "type": "string",
+            "enum": [
+                "income",
+                "expense",
+                "transfer"
+            ],
+            "x-enum-comments": {
+                "Transfer": "перевод между счетами, не доход и не расход"
+            },
+            "x-enum-varnames": [
+                "Income",
+                "Expense",
+                "Transfer"
+            ]
+        },
+        "models.Translations": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "recurring.RuleInput": {
+            "type": "object",
+            "required": [
+                "amount",
+                "frequency",
+                "title",
+                "type"
+            ],
+            "properties": {
+                "account": {
+                    "description": "если не указан, используется основной счёт",
+                    "type": "integer"
+                },
+                "adjust": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "following",
+                        "preceding",
+                        "modified_following"
+                    ]
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "type": "integer"
+                },
+                "count": {
+                    "description": "число повторений, 0 — без ограничения",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "counterAccount": {
+                    "description": "счёт зачисления перевода",
+                    "type": "integer"
+                },
+                "counterAmount": {
+                    "description": "для перевода между валютами",
+                    "type": "number"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly",
+                        "yearly"
+                    ]
+                },
+                "interval": {
+                    "description": "по умолчанию 1",
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 0
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "start": {
+                    "description": "первое повторение, по умолчанию сейчас",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "income",
+                        "expense",
+                        "transfer"
+                    ]
+                },
+                "until": {
+                    "description": "последний день повторений включительно",
+                    "type": "string"
+                }
+            }
+        },
+        "recurring.SkipInput": {
+            "type": "object",
+            "required": [
+                "date"
+            ],
+            "properties": {
+                "date": {
+                    "description": "дата повторения по расписанию или после переноса, YYYY-MM-DD",
+                    "type": "string",
+                    "example": "2026-11-01"
+                }
+            }
+        },
+        "recurring.Upcoming": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "date": {
+                    "description": "дата после переноса с выходных",
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "nominal": {
+                    "description": "дата по расписанию",
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.TransactionType"
+                }
+            }
+        },
+        "repository.SearchHit": {
+            "type": "object",
+            "properties": {
+                "accountID": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "bonusChange": {
+                    "type": "number"
+                },
+                "bonusType": {
+                    "$ref": "#/definitions/models.TransactionType"
+                },
+                "category": {
+                    "type": "integer"
+                },
+                "counterAccountID": {
+                    "description": "Поля перевода: Amount списывается с AccountID вместе с Fee,\nCounterAmount зачисляется на CounterAccountID в его валюте.",
+                    "type": "integer"
+                },
+                "counterAmount": {
+                    "type": "number"
+                },
+                "createdAt": {
                     "type": "string"
                 },
-                "password": {
+                "currency": {
                     "type": "string"
-                }
-            }
-        },
-        "auth.RegisterInput": {
-            "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
-            "properties": {
-                "email": {
+                },
+                "date": {
                     "type": "string"
                 },
-                "password": {
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "description": {
                     "type": "string"
                 },
-                "username": {
+                "descriptionHighlight": {
                     "type": "string"
-                }
-            }
-        },
-        "auth.VerificationCodeInput": {
-            "type": "object",
-            "required": [
-                "code"
-            ],
-            "properties": {
-                "code": {
+                },
+                "externalID": {
+                    "description": "идентификатор операции в банке (FITID) для импорта без дублей",
                     "type": "string"
-                }
-            }
-        },
-        "models.Category": {
-            "type": "object",
-            "properties": {
-                "color": {
+                },
+                "fee": {
+                    "type": "number"
+                },
+                "fiscalDocument": {
                     "type": "string"
                 },
-                "createdAt": {
+                "fiscalDrive": {
+                    "description": "Фискальные признаки кассового чека из QR-кода: номер фискального\nнакопителя (fn), номер документа (i) и фискальный признак (fp).",
+                    "type": "string"
+                },
+                "fiscalSign": {
                     "type": "string"
                 },
+                "goalID": {
+                    "description": "цель накоплений, для взносов и снятий",
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "isDefault": {
-                    "type": "boolean"
+                "occurrence": {
+                    "type": "string"
                 },
-                "name": {
+                "rank": {
+                    "type": "number"
+                },
+                "rate": {
+                    "description": "курс пересчёта для переводов между валютами",
+                    "type": "string"
+                },
+                "recurringID": {
+                    "description": "Повторяющаяся операция, создавшая транзакцию, и дата повторения\nпо расписанию: по этой паре повторение не создаётся дважды.",
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "titleHighlight": {
                     "type": "string"
                 },
+                "type": {
+                    "description": "income или expense //доход или расход",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TransactionType"
+                        }
+                    ]
+                },
                 "updatedAt": {
                     "type": "string"
                 },
                 "userID": {
-                    "description": "nil для дефолтных категорий",
                     "type": "integer"
                 }
             }
         },
+        "repository.TransactionSums": {
+            "type": "object",
+            "properties": {
+                "expense": {
+                    "type": "number"
+                },
+                "income": {
+                    "type": "number"
+                }
+            }
+        },
         "response.BalanceResponse": {
             "type": "object",
             "properties": {
                 "balance": {
                     "type": "number"
+                },
+                "totals": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
                 }
             }
         },
@@ -954,12 +6220,112 @@ const docTemplate = `{
         "response.TokenResponse": {
             "type": "object",
             "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "refreshExpiresAt": {
+                    "type": "string"
+                },
+                "refreshToken": {
+                    "type": "string"
+                },
                 "token": {
+                    "description": "access-токен для заголовка Authorization",
                     "type": "string",
                     "example": "Ваш токен"
                 }
             }
         },
+        "response.TwoFactorChallengeResponse": {
+            "type": "object",
+            "properties": {
+                "challengeToken": {
+                    "description": "передаётся в /auth/2fa/verify вместе с кодом",
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "twoFactorRequired": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "transactions.ExportRow": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "bonusChange": {
+                    "type": "number"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "counterAccount": {
+                    "type": "string"
+                },
+                "counterAmount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "typeBonus": {
+                    "type": "string"
+                }
+            }
+        },
+        "transactions.ReceiptInput": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "description": "если не указан, используется основной счёт",
+                    "type": "integer"
+                },
+                "bonusChange": {
+                    "type": "number"
+                },
+                "category": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "qr": {
+                    "description": "строка из QR-кода; для /transactions/receipt/image не нужна",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "typeBonus": {
+                    "type": "string"
+                }
+            }
+        },
         "transactions.TransactionInput": {
             "type": "object",
             "required": [
@@ -968,6 +6334,10 @@ const docTemplate = `{
                 "type"
             ],
             "properties": {
+                "account": {
+                    "description": "если не указан, используется основной счёт",
+                    "type": "integer"
+                },
                 "amount": {
                     "type": "number"
                 },
@@ -1001,9 +6371,37 @@ const docTemplate = `{
                 }
             }
         },
+        "transactions.TransactionList": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transaction"
+                    }
+                },
+                "nextCursor": {
+                    "description": "пустой на последней странице",
+                    "type": "string"
+                },
+                "sums": {
+                    "description": "по валютам",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/repository.TransactionSums"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
         "transactions.TransactionUpdate": {
             "type": "object",
             "properties": {
+                "account": {
+                    "type": "integer"
+                },
                 "amount": {
                     "type": "number"
                 },
@@ -1037,6 +6435,87 @@ const docTemplate = `{
                 }
             }
         },
+        "transactions.TransferInput": {
+            "type": "object",
+            "required": [
+                "amount",
+                "fromAccount",
+                "toAccount"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "списывается в валюте счёта-источника",
+                    "type": "number"
+                },
+                "counterAmount": {
+                    "description": "зачисляется в валюте счёта-получателя",
+                    "type": "number"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "number"
+                },
+                "fromAccount": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "description": "курс вместо counterAmount",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "toAccount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "transactions.TransferUpdate": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "counterAmount": {
+                    "type": "number"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "number"
+                },
+                "fromAccount": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "toAccount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "users.NotificationSettings": {
+            "type": "object",
+            "properties": {
+                "budgetAlerts": {
+                    "description": "письма при расходовании 80% и 100% бюджета",
+                    "type": "boolean"
+                }
+            }
+        },
         "users.UpdateBalanceInput": {
             "type": "object",
             "required": [
@@ -1059,6 +6538,14 @@ const docTemplate = `{
                 }
             }
         },
+        "users.UpdateNotificationsInput": {
+            "type": "object",
+            "properties": {
+                "budgetAlerts": {
+                    "type": "boolean"
+                }
+            }
+        },
         "users.UserInfo": {
             "type": "object",
             "properties": {
@@ -1071,6 +6558,10 @@ const docTemplate = `{
                 "email": {
                     "type": "string"
                 },
+                "twoFactor": {
+                    "description": "включена двухфакторная аутентификация",
+                    "type": "boolean"
+                },
                 "username": {
                     "type": "string"
                 },
@@ -1093,6 +6584,30 @@ const docTemplate = `{
                 }
             }
         },
+        "сategory.DefaultCategory": {
+            "type": "object",
+            "required": [
+                "name",
+                "slug"
+            ],
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "translations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "сategory.UpdateCategoryInput": {
             "type": "object",
             "properties": {
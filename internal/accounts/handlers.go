@@ -0,0 +1,254 @@
+package accounts
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/httputil"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	store repository.Store
+}
+
+func NewHandler(store repository.Store) *Handler {
+	return &Handler{store: store}
+}
+
+// NewPrimaryAccount возвращает счёт, который создаётся каждому новому пользователю.
+func NewPrimaryAccount(userID uint) models.Account {
+	return models.Account{
+		UserID:   userID,
+		Name:     "Основной счёт",
+		Type:     models.AccountCard,
+		Currency: "RUB",
+	}
+}
+
+// @Security BearerAuth
+// ListAccounts godoc
+// @Summary Получить счета
+// @Description Получить счета пользователя с текущими балансами
+// @Tags Accounts
+// @Produce json
+// @Param archived query bool false "Включить архивные счета"
+// @Success 200 {array} models.Account
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении счетов"
+// @Router /accounts [get]
+func (h *Handler) ListAccounts(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	accounts, err := h.store.Accounts().ListForUser(userID, c.Query("archived") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении счетов"})
+		return
+	}
+
+	c.JSON(http.StatusOK, accounts)
+}
+
+// @Security BearerAuth
+// GetAccount godoc
+// @Summary Получить счёт
+// @Description Получить счёт пользователя по ID
+// @Tags Accounts
+// @Produce json
+// @Param id path string true "ID счёта"
+// @Success 200 {object} models.Account
+// @Failure 404 {object} response.ErrorResponse "Счёт не найден"
+// @Router /accounts/{id} [get]
+func (h *Handler) GetAccount(c *gin.Context) {
+	account, ok := h.ownedAccount(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+type CreateAccountInput struct {
+	Name           string       `json:"name" binding:"required,max=100"`
+	Type           string       `json:"type" binding:"required,oneof=card credit cash savings"`
+	Currency       string       `json:"currency" binding:"omitempty,len=3,alpha"` // код ISO 4217, по умолчанию RUB
+	OpeningBalance money.Amount `json:"openingBalance" swaggertype:"number"`
+}
+
+// @Security BearerAuth
+// CreateAccount godoc
+// @Summary Создать счёт
+// @Description Создать новый счёт или кошелёк пользователя
+// @Tags Accounts
+// @Accept json
+// @Produce json
+// @Param input body CreateAccountInput true "Счёт для создания"
+// @Success 201 {object} models.Account
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 500 {object} response.ErrorResponse "Ошибка создания счёта"
+// @Router /accounts [post]
+func (h *Handler) CreateAccount(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input CreateAccountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Currency == "" {
+		input.Currency = "RUB"
+	}
+
+	account := models.Account{
+		UserID:         userID,
+		Name:           input.Name,
+		Type:           models.AccountType(input.Type),
+		Currency:       strings.ToUpper(input.Currency),
+		OpeningBalance: input.OpeningBalance,
+		Balance:        input.OpeningBalance,
+	}
+
+	if err := h.store.Accounts().Create(&account); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании счёта"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, account)
+}
+
+type UpdateAccountInput struct {
+	Name           *string       `json:"name" binding:"omitempty,max=100"`
+	Type           *string       `json:"type" binding:"omitempty,oneof=card credit cash savings"`
+	Currency       *string       `json:"currency" binding:"omitempty,len=3,alpha"`
+	OpeningBalance *money.Amount `json:"openingBalance" swaggertype:"number"`
+	Archived       *bool         `json:"archived"`
+}
+
+// @Security BearerAuth
+// UpdateAccount godoc
+// @Summary Обновить счёт
+// @Description Обновить счёт пользователя. Изменение начального остатка сдвигает текущий баланс на ту же сумму. Валюту можно менять только у счёта без транзакций
+// @Tags Accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "ID счёта"
+// @Param input body UpdateAccountInput true "Данные для обновления счёта"
+// @Success 200 {object} models.Account
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 404 {object} response.ErrorResponse "Счёт не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка обновления счёта"
+// @Router /accounts/{id} [put]
+func (h *Handler) UpdateAccount(c *gin.Context) {
+	var input UpdateAccountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, ok := h.ownedAccount(c)
+	if !ok {
+		return
+	}
+
+	if input.Name != nil && *input.Name != "" {
+		account.Name = *input.Name
+	}
+	if input.Type != nil {
+		account.Type = models.AccountType(*input.Type)
+	}
+	if input.Currency != nil && strings.ToUpper(*input.Currency) != account.Currency {
+		used, err := h.store.Transactions().ExistsForAccount(account.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении счёта"})
+			return
+		}
+		if used {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Нельзя сменить валюту счёта с транзакциями"})
+			return
+		}
+		account.Currency = strings.ToUpper(*input.Currency)
+	}
+	if input.Archived != nil {
+		account.Archived = *input.Archived
+	}
+
+	// Остатки меняются только атомарным сдвигом: транзакции, проведённые после
+	// чтения счёта, не должны потеряться
+	err := h.store.Atomic(func(s repository.Store) error {
+		if err := s.Accounts().UpdateDetails(account); err != nil {
+			return err
+		}
+		if input.OpeningBalance != nil {
+			return s.Accounts().AdjustOpeningBalance(account.ID, *input.OpeningBalance-account.OpeningBalance)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении счёта"})
+		return
+	}
+
+	updated, err := h.store.Accounts().GetOwned(account.ID, account.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении счёта"})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// @Security BearerAuth
+// DeleteAccount godoc
+// @Summary Удалить счёт
+// @Description Удалить счёт без транзакций. Счета с транзакциями можно только архивировать
+// @Tags Accounts
+// @Produce json
+// @Param id path string true "ID счёта"
+// @Success 200 {object} response.SuccessResponse "Счёт удалён"
+// @Failure 404 {object} response.ErrorResponse "Счёт не найден"
+// @Failure 409 {object} response.ErrorResponse "По счёту есть транзакции"
+// @Failure 500 {object} response.ErrorResponse "Ошибка удаления счёта"
+// @Router /accounts/{id} [delete]
+func (h *Handler) DeleteAccount(c *gin.Context) {
+	account, ok := h.ownedAccount(c)
+	if !ok {
+		return
+	}
+
+	used, err := h.store.Transactions().ExistsForAccount(account.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении счёта"})
+		return
+	}
+	if used {
+		c.JSON(http.StatusConflict, gin.H{"error": "По счёту есть транзакции, его можно только архивировать"})
+		return
+	}
+
+	if err := h.store.Accounts().Delete(account); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении счёта"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Счёт удалён"})
+}
+
+// ownedAccount загружает счёт из параметра пути и отвечает 404, если его нет.
+func (h *Handler) ownedAccount(c *gin.Context) (*models.Account, bool) {
+	userID := c.GetUint("userID")
+
+	accountID, err := httputil.ParamID(c, "id")
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Счёт не найден"})
+		return nil, false
+	}
+
+	account, err := h.store.Accounts().GetOwned(accountID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Счёт не найден"})
+		return nil, false
+	}
+	return account, true
+}
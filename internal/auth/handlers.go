@@ -1,24 +1,35 @@
 package auth
 
 import (
-	"crypto/rand"
 	"errors"
-	"fmt"
 	"log"
-	"math/big"
 	"net/http"
-	"os"
 	"regexp"
 	"strings"
-	"time"
 
-	"github.com/Anabol1ks/pers-fin-m/internal/storage"
-	"github.com/Anabol1ks/pers-fin-m/internal/users"
+	"github.com/Anabol1ks/pers-fin-m/internal/accounts"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Mailer отправляет письма пользователям. Реализуется пакетом email,
+// который сам импортирует auth.
+type Mailer interface {
+	SendVerifyCode(username, email, code, ttl string) error
+	SendPasswordReset(username, email, link, ttl string) error
+}
+
+type Handler struct {
+	store  repository.Store
+	mailer Mailer
+}
+
+func NewHandler(store repository.Store, mailer Mailer) *Handler {
+	return &Handler{store: store, mailer: mailer}
+}
+
 type RegisterInput struct {
 	Username string `json:"username" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
@@ -27,7 +38,7 @@ type RegisterInput struct {
 
 // Registerhandler godoc
 // @Summary Регистрация пользователя
-// @Description Регистрация пользователя с указанием никнейма, почты, пароля
+// @Description Регистрация пользователя с указанием никнейма, почты, пароля. На почту отправляется код подтверждения
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -37,7 +48,7 @@ type RegisterInput struct {
 // @Failure 409 {object} response.ErrorResponse "Почта уже зарегистрированы"
 // @Failure 500 {object} response.ErrorResponse "Не удалось хешировать пароль или создать пользователя"
 // @Router /auth/register [post]
-func RegisterHandler(c *gin.Context) {
+func (h *Handler) RegisterHandler(c *gin.Context) {
 	var input RegisterInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -51,8 +62,7 @@ func RegisterHandler(c *gin.Context) {
 
 	input.Email = strings.ToLower(input.Email)
 
-	var existingUser users.User
-	if err := storage.DB.Where("email = ?", input.Email).First(&existingUser).Error; err == nil {
+	if _, err := h.store.Users().GetByEmail(input.Email); err == nil {
 		c.JSON(http.StatusConflict, gin.H{"error": "Почта уже зарегистрированны"})
 		return
 	}
@@ -63,25 +73,34 @@ func RegisterHandler(c *gin.Context) {
 		return
 	}
 
-	verifCode, err := GenerateVerificationCode()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка генерации кода подтверждения"})
-		return
+	user := models.User{
+		Username:     input.Username,
+		Email:        input.Email,
+		Password:     string(hashedPassword),
+		BudgetAlerts: true,
 	}
 
-	user := users.User{
-		Username:         input.Username,
-		Email:            input.Email,
-		Password:         string(hashedPassword),
-		VerificationCode: verifCode,
+	// Пользователь сразу получает основной счёт, на который записываются транзакции
+	err = h.store.Atomic(func(s repository.Store) error {
+		if err := s.Users().Create(&user); err != nil {
+			return err
+		}
+		account := accounts.NewPrimaryAccount(user.ID)
+		return s.Accounts().Create(&account)
+	})
+	if err != nil {
+		c.JSON(http.StatusInsufficientStorage, gin.H{"error": "Не удалось создать пользователя"})
+		return
 	}
 
-	if err := storage.DB.Create(&user).Error; err != nil {
-		c.JSON(http.StatusInsufficientStorage, gin.H{"error": "Не удалось создать пользователя"})
+	// Пользователь уже создан: если письмо не ушло, код можно запросить повторно через /auth/newVerify
+	if err := h.sendVerificationCode(&user); err != nil {
+		log.Printf("Ошибка отправки кода подтверждения пользователю %d: %v", user.ID, err)
+		c.JSON(http.StatusCreated, gin.H{"message": "Регистрация успешна, но письмо с кодом подтверждения не отправлено, запросите код повторно"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Регистрация успешна"})
+	c.JSON(http.StatusCreated, gin.H{"message": "Регистрация успешна, код подтверждения отправлен на почту"})
 }
 
 func ValidatePassword(password string) error {
@@ -106,26 +125,29 @@ func ValidatePassword(password string) error {
 	return nil
 }
 
-var jwtSecret = []byte(os.Getenv("JWT_KEY"))
-
 type LoginInput struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required"`
+	DeviceName string `json:"deviceName" binding:"max=100"` // по умолчанию определяется по User-Agent
 }
 
 // LoginHandler godoc
 // @Summary Авторизация
-// @Description Авторизация пользователя с указанием почты и пароля
+// @Description Авторизация пользователя с указанием почты и пароля. Возвращает короткоживущий access-токен
+// @Description и refresh-токен для получения новой пары через /auth/refresh.
+// @Description Если включена двухфакторная аутентификация, возвращает 202 и токен второго шага для /auth/2fa/verify
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param input body LoginInput true "Данные пользователя"
 // @Success 200 {object} response.TokenResponse "Успешная авторизация"
+// @Success 202 {object} response.TwoFactorChallengeResponse "Требуется код двухфакторной аутентификации"
 // @Failure 400 {object} response.ErrorResponse "Описание ошибки валидации"
 // @Failure 401 {object} response.ErrorResponse "Неверный пароль"
 // @Failure 404 {object} response.ErrorResponse "Пользователя с такой почтой не существует"
+// @Failure 500 {object} response.ErrorResponse "Ошибка выдачи токенов"
 // @Router /auth/login [post]
-func LoginHandler(c *gin.Context) {
+func (h *Handler) LoginHandler(c *gin.Context) {
 	var input LoginInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -134,8 +156,8 @@ func LoginHandler(c *gin.Context) {
 
 	input.Email = strings.ToLower(input.Email)
 
-	var user users.User
-	if err := storage.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
+	user, err := h.store.Users().GetByEmail(input.Email)
+	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователя с такой почтой не существует"})
 		return
 	}
@@ -145,78 +167,15 @@ func LoginHandler(c *gin.Context) {
 		return
 	}
 
-	token := GenerateJWT(user.ID)
-	c.JSON(http.StatusOK, gin.H{"token": token})
-}
-
-func GenerateJWT(userID uint) string {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 300).Unix(),
-	})
-	tokenString, _ := token.SignedString(jwtSecret)
-	return tokenString
-}
-
-func GenerateVerificationCode() (string, error) {
-	max := big.NewInt(1000000) // Диапазон [0, 1000000)
-	n, err := rand.Int(rand.Reader, max)
-	if err != nil {
-		return "", err
-	}
-	code := fmt.Sprintf("%06d", n.Int64())
-	return code, nil
-}
-
-type VerificationCodeInput struct {
-	Code string `json:"code" binding:"required,len=6"`
-}
-
-// @Security BearerAuth
-// VerifyEmailHandler godoc
-// @Summary Подтверждение аккаунта
-// @Description Подтверждение аккаунта пользователя с указанием почты и кода подтверждения
-// @Tags auth
-// @Accept json
-// @Produce json
-// @Param input body VerificationCodeInput true "Данные пользователя"
-// @Success 200 {object} response.SuccessResponse "Аккаунт успешно подтверждён"
-// @Failure 400 {object} response.ErrorResponse "Неверный код подтверждения"
-// @Failure 404 {object} response.ErrorResponse "Пользователь не существует"
-// @Failure 409 {object} response.ErrorResponse "Аккаунт уже подтверждён"
-// @Router /auth/verify [post]
-func VerifyEmailHandler(c *gin.Context) {
-	userID := c.GetUint("userID")
-
-	var input VerificationCodeInput
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		log.Println(input, err)
-		return
-	}
-
-	var user users.User
-	if err := storage.DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-
-	if user.Verified {
-		c.JSON(http.StatusConflict, gin.H{"error": "Аккаунт уже подтверждён"})
+	if user.TOTPEnabled {
+		loginChallenge(c, user, input.DeviceName)
 		return
 	}
 
-	if user.VerificationCode != input.Code {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный код подтверждения"})
-		return
-	}
-
-	user.Verified = true
-	user.VerificationCode = ""
-	if err := storage.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось обновить пользователя"})
+	tokens, err := h.startSession(c, user.ID, input.DeviceName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка выдачи токенов"})
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Аккаунт успешно подтверждён"})
+	c.JSON(http.StatusOK, tokens)
 }
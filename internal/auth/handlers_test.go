@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository/memory"
+	"github.com/Anabol1ks/pers-fin-m/internal/response"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testPassword = "Secret123"
+
+// mailbox запоминает письма вместо отправки.
+type mailbox struct {
+	codes chan string
+	links chan string
+}
+
+func (m *mailbox) SendVerifyCode(username, email, code, ttl string) error {
+	m.codes <- code
+	return nil
+}
+
+func (m *mailbox) SendPasswordReset(username, email, link, ttl string) error {
+	m.links <- link
+	return nil
+}
+
+type testServer struct {
+	t       *testing.T
+	store   *memory.Store
+	handler *Handler
+	mail    *mailbox
+	router  *gin.Engine
+}
+
+// newTestServer поднимает маршруты авторизации как в main.go поверх хранилища в памяти.
+func newTestServer(t *testing.T) *testServer {
+	t.Setenv("JWT_KEY", "test-key")
+	gin.SetMode(gin.TestMode)
+
+	store := memory.NewStore()
+	mail := &mailbox{codes: make(chan string, 10), links: make(chan string, 10)}
+	h := NewHandler(store, mail)
+
+	r := gin.New()
+	r.POST("/auth/register", h.RegisterHandler)
+	r.POST("/auth/login", h.LoginHandler)
+	r.POST("/auth/refresh", h.RefreshHandler)
+	r.POST("/auth/logout", h.LogoutHandler)
+	r.POST("/auth/password/forgot", h.ForgotPasswordHandler)
+	r.POST("/auth/password/reset", h.ResetPasswordHandler)
+	r.POST("/auth/2fa/verify", h.VerifyTwoFactor)
+
+	authorized := r.Group("/", AuthMiddleware(store))
+	authorized.POST("/auth/verify", h.VerifyEmailHandler)
+	authorized.POST("/auth/newVerify", h.SendNewVerify)
+	authorized.GET("/auth/sessions", h.ListSessions)
+	authorized.DELETE("/auth/sessions", h.DeleteSessions)
+	authorized.DELETE("/auth/sessions/:id", h.DeleteSession)
+	authorized.POST("/auth/2fa/setup", h.SetupTwoFactor)
+	authorized.POST("/auth/2fa/enable", h.EnableTwoFactor)
+
+	return &testServer{t: t, store: store, handler: h, mail: mail, router: r}
+}
+
+// call выполняет запрос и разбирает ответ в out, если он задан.
+func (s *testServer) call(method, path, token string, body, out any) int {
+	s.t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			s.t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0) Chrome/120.0")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	if out != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			s.t.Fatalf("%s %s: %v: %s", method, path, err, w.Body)
+		}
+	}
+	return w.Code
+}
+
+// addUser создаёт подтверждённого пользователя с паролем testPassword.
+func (s *testServer) addUser(email string) *models.User {
+	s.t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	user := &models.User{Username: "test", Email: email, Password: string(hash), Verified: true}
+	if err := s.store.Users().Create(user); err != nil {
+		s.t.Fatal(err)
+	}
+	return user
+}
+
+func (s *testServer) login(email, device string) *response.TokenResponse {
+	s.t.Helper()
+	var tokens response.TokenResponse
+	if code := s.call("POST", "/auth/login", "", LoginInput{Email: email, Password: testPassword, DeviceName: device}, &tokens); code != http.StatusOK {
+		s.t.Fatalf("login: status %d", code)
+	}
+	return &tokens
+}
+
+type errorBody struct {
+	Error string `json:"error"`
+}
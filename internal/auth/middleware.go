@@ -1,14 +1,18 @@
 package auth
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware пропускает запросы с действующим access-токеном неотозванной сессии.
+func AuthMiddleware(store repository.Store) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -17,19 +21,48 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return jwtSecret, nil
-		})
+		claims, err := parseToken(strings.TrimPrefix(authHeader, "Bearer "))
+		userID, _ := claims["user_id"].(float64)
+		// Токен второго шага входа не даёт доступа к API
+		if err != nil || userID <= 0 || claims["typ"] == challengeTokenType {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Недействительный или просроченный токен"})
+			c.Abort()
+			return
+		}
 
-		if err != nil || !token.Valid {
-			c.Next()
+		sessionID, _ := claims["sid"].(string)
+		session, err := store.Sessions().GetByToken(sessionID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка проверки сессии"})
+			c.Abort()
 			return
 		}
+		if err != nil || session.RevokedAt != nil || session.UserID != uint(userID) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Сессия завершена, войдите заново"})
+			c.Abort()
+			return
+		}
+		if now := time.Now(); now.Sub(session.LastUsedAt) > sessionTouchInterval {
+			if err := store.Sessions().Touch(sessionID, now, c.ClientIP()); err != nil {
+				log.Println("Ошибка обновления сессии:", err)
+			}
+		}
 
-		claims := token.Claims.(jwt.MapClaims)
-		userID := uint(claims["user_id"].(float64))
-		c.Set("userID", userID)
+		c.Set("userID", uint(userID))
+		c.Set("sessionID", sessionID)
+		c.Next()
+	}
+}
+
+// AdminMiddleware пропускает только администраторов. Должен стоять после AuthMiddleware.
+func AdminMiddleware(store repository.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := store.Users().GetByID(c.GetUint("userID"))
+		if err != nil || !user.IsAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Недостаточно прав"})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
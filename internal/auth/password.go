@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// passwordResetTTL — срок действия ссылки сброса пароля.
+	passwordResetTTL     = time.Hour
+	passwordResetTTLText = "1 час"
+	// passwordResetCooldown — минимальный интервал между письмами сброса одному пользователю.
+	passwordResetCooldown = time.Minute
+	// maxActivePasswordResets — сколько действующих ссылок может быть у пользователя одновременно.
+	maxActivePasswordResets = 3
+	// defaultResetURL — страница фронтенда, на которую ведёт ссылка из письма.
+	// Переопределяется переменной PASSWORD_RESET_URL.
+	defaultResetURL = "http://localhost:3001/reset-password"
+)
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+var (
+	errResetUsed    = errors.New("ссылка для сброса пароля уже использована")
+	errResetLimited = errors.New("превышен лимит запросов сброса пароля")
+)
+
+// ForgotPasswordHandler godoc
+// @Summary Запрос сброса пароля
+// @Description Отправляет на почту ссылку для сброса пароля. Ссылка действует час и один раз.
+// @Description Письмо отправляется не чаще раза в минуту, действующих ссылок может быть не больше трёх.
+// @Description Ответ не зависит от того, зарегистрирована ли почта и отправлено ли письмо.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body ForgotPasswordInput true "Почта пользователя"
+// @Success 200 {object} response.SuccessResponse "Письмо отправлено, если почта зарегистрирована"
+// @Failure 400 {object} response.ErrorResponse "Описание ошибки валидации"
+// @Router /auth/password/forgot [post]
+func (h *Handler) ForgotPasswordHandler(c *gin.Context) {
+	var input ForgotPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Письмо уходит в фоне, чтобы по ответу и времени ответа нельзя было узнать, есть ли такая почта
+	go h.sendPasswordReset(strings.ToLower(input.Email))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Если почта зарегистрирована, на неё отправлено письмо со ссылкой для сброса пароля"})
+}
+
+func (h *Handler) sendPasswordReset(email string) {
+	user, err := h.store.Users().GetByEmail(email)
+	if err != nil {
+		return
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		log.Println("Ошибка генерации токена сброса пароля:", err)
+		return
+	}
+
+	// Запрос не требует авторизации, поэтому письма одному пользователю
+	// ограничены: не чаще раза в passwordResetCooldown и не больше
+	// maxActivePasswordResets действующих ссылок
+	err = h.store.Atomic(func(s repository.Store) error {
+		now := time.Now()
+		if err := s.PasswordResets().DeleteInactive(user.ID, now); err != nil {
+			return err
+		}
+		active, err := s.PasswordResets().ListActive(user.ID, now)
+		if err != nil {
+			return err
+		}
+		if len(active) >= maxActivePasswordResets || (len(active) > 0 && now.Sub(active[0].CreatedAt) < passwordResetCooldown) {
+			return errResetLimited
+		}
+		return s.PasswordResets().Create(&models.PasswordReset{
+			UserID:    user.ID,
+			TokenHash: hashToken(token),
+			ExpiresAt: now.Add(passwordResetTTL),
+		})
+	})
+	if errors.Is(err, errResetLimited) {
+		log.Printf("Сброс пароля пользователя %d запрошен слишком часто, письмо не отправлено", user.ID)
+		return
+	}
+	if err != nil {
+		log.Println("Ошибка сохранения токена сброса пароля:", err)
+		return
+	}
+
+	if err := h.mailer.SendPasswordReset(user.Username, user.Email, resetLink(token), passwordResetTTLText); err != nil {
+		log.Printf("Ошибка отправки письма сброса пароля пользователю %d: %v", user.ID, err)
+	}
+}
+
+func resetLink(token string) string {
+	base := os.Getenv("PASSWORD_RESET_URL")
+	if base == "" {
+		base = defaultResetURL
+	}
+	separator := "?"
+	if strings.Contains(base, "?") {
+		separator = "&"
+	}
+	return base + separator + "token=" + url.QueryEscape(token)
+}
+
+// ResetPasswordHandler godoc
+// @Summary Сброс пароля
+// @Description Задаёт новый пароль по токену из письма. Токен действует один раз, после смены пароля
+// @Description завершаются все сессии пользователя и отзываются остальные ссылки сброса.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body ResetPasswordInput true "Токен из письма и новый пароль"
+// @Success 200 {object} response.SuccessResponse "Пароль изменён"
+// @Failure 400 {object} response.ErrorResponse "Недействительная ссылка или слабый пароль"
+// @Failure 500 {object} response.ErrorResponse "Ошибка смены пароля"
+// @Router /auth/password/reset [post]
+func (h *Handler) ResetPasswordHandler(c *gin.Context) {
+	var input ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := ValidatePassword(input.Password); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reset, err := h.store.PasswordResets().GetByHash(hashToken(input.Token))
+	if err != nil || reset.UsedAt != nil || reset.ExpiresAt.Before(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ссылка для сброса пароля недействительна или устарела"})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось хешировать пароль"})
+		return
+	}
+
+	err = h.store.Atomic(func(s repository.Store) error {
+		now := time.Now()
+		used, err := s.PasswordResets().MarkUsed(reset.ID, now)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return errResetUsed
+		}
+
+		if err := s.Users().SetPassword(reset.UserID, string(hashedPassword)); err != nil {
+			return err
+		}
+
+		if err := s.PasswordResets().InvalidateUser(reset.UserID, now); err != nil {
+			return err
+		}
+		if err := s.RefreshTokens().RevokeUser(reset.UserID, "", now); err != nil {
+			return err
+		}
+		return s.Sessions().RevokeUser(reset.UserID, "", now)
+	})
+	if errors.Is(err, errResetUsed) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ссылка для сброса пароля недействительна или устарела"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка смены пароля"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Пароль изменён, войдите с новым паролем"})
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestResetPassword(t *testing.T) {
+	s := newTestServer(t)
+	user := s.addUser("user@example.com")
+	session := s.login("user@example.com", "")
+
+	if code := s.call("POST", "/auth/password/forgot", "", ForgotPasswordInput{"USER@example.com"}, nil); code != http.StatusOK {
+		t.Fatalf("forgot: status %d", code)
+	}
+	var link string
+	select {
+	case link = <-s.mail.links:
+	case <-time.After(5 * time.Second):
+		t.Fatal("письмо сброса пароля не отправлено")
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := parsed.Query().Get("token")
+
+	// Повторный запрос в течение минуты письма не отправляет
+	s.handler.sendPasswordReset("user@example.com")
+	if active, _ := s.store.PasswordResets().ListActive(user.ID, time.Now()); len(active) != 1 {
+		t.Errorf("действующих ссылок: %d, want 1", len(active))
+	}
+	// Незарегистрированная почта получает тот же ответ
+	if code := s.call("POST", "/auth/password/forgot", "", ForgotPasswordInput{"nobody@example.com"}, nil); code != http.StatusOK {
+		t.Errorf("forgot для чужой почты: status %d", code)
+	}
+
+	const newPassword = "NewSecret456"
+	steps := []struct {
+		name string
+		body ResetPasswordInput
+		want int
+	}{
+		{"слабый пароль", ResetPasswordInput{Token: token, Password: "short"}, http.StatusBadRequest},
+		{"неверный токен", ResetPasswordInput{Token: "wrong", Password: newPassword}, http.StatusBadRequest},
+		{"смена пароля", ResetPasswordInput{Token: token, Password: newPassword}, http.StatusOK},
+		{"повторное использование", ResetPasswordInput{Token: token, Password: newPassword + "x"}, http.StatusBadRequest},
+	}
+	for _, step := range steps {
+		if code := s.call("POST", "/auth/password/reset", "", step.body, nil); code != step.want {
+			t.Errorf("%s: status %d, want %d", step.name, code, step.want)
+		}
+	}
+
+	// Сессии, открытые до сброса, завершены
+	if code := s.call("GET", "/auth/sessions", session.Token, nil, nil); code != http.StatusUnauthorized {
+		t.Errorf("старый access-токен: status %d, want 401", code)
+	}
+	if code := s.call("POST", "/auth/refresh", "", RefreshInput{session.RefreshToken}, nil); code != http.StatusUnauthorized {
+		t.Errorf("старый refresh-токен: status %d, want 401", code)
+	}
+	if code := s.call("POST", "/auth/login", "", LoginInput{Email: "user@example.com", Password: testPassword}, nil); code != http.StatusUnauthorized {
+		t.Errorf("вход со старым паролем: status %d, want 401", code)
+	}
+	if code := s.call("POST", "/auth/login", "", LoginInput{Email: "user@example.com", Password: newPassword}, nil); code != http.StatusOK {
+		t.Errorf("вход с новым паролем: status %d, want 200", code)
+	}
+}
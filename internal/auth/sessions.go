@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/httputil"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/Anabol1ks/pers-fin-m/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// sessionTouchInterval — как часто запросы с access-токеном обновляют время последнего использования сессии.
+const sessionTouchInterval = time.Minute
+
+// SessionInfo — вход пользователя с устройства.
+type SessionInfo struct {
+	ID         uint      `json:"id"`
+	DeviceName string    `json:"deviceName"`
+	UserAgent  string    `json:"userAgent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	Current    bool      `json:"current"` // сессия, которой выполнен запрос
+}
+
+// startSession начинает новый вход: создаёт сессию с данными устройства
+// и выдаёт первую пару токенов.
+func (h *Handler) startSession(c *gin.Context, userID uint, deviceName string) (*response.TokenResponse, error) {
+	tokenID, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	userAgent := c.Request.UserAgent()
+	if deviceName = strings.TrimSpace(deviceName); deviceName == "" {
+		deviceName = describeDevice(userAgent)
+	}
+	now := time.Now()
+	session := &models.Session{
+		UserID:     userID,
+		TokenID:    tokenID,
+		DeviceName: truncate(deviceName, 100),
+		UserAgent:  truncate(userAgent, 255),
+		IP:         c.ClientIP(),
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now,
+	}
+
+	var tokens *response.TokenResponse
+	err = h.store.Atomic(func(s repository.Store) error {
+		if err := s.Sessions().Create(session); err != nil {
+			return err
+		}
+		tokens, err = issueTokens(s, userID, tokenID)
+		return err
+	})
+	return tokens, err
+}
+
+// revokeSession завершает сессию: отзывает её refresh-токены и запрещает access-токены.
+func revokeSession(store repository.Store, tokenID string, at time.Time) error {
+	return store.Atomic(func(s repository.Store) error {
+		if err := s.RefreshTokens().RevokeFamily(tokenID, at); err != nil {
+			return err
+		}
+		return s.Sessions().Revoke(tokenID, at)
+	})
+}
+
+// @Security BearerAuth
+// ListSessions godoc
+// @Summary Активные сессии
+// @Description Возвращает устройства, с которых выполнен вход, последние использованные первыми.
+// @Tags auth
+// @Produce json
+// @Success 200 {array} SessionInfo
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении сессий"
+// @Router /auth/sessions [get]
+func (h *Handler) ListSessions(c *gin.Context) {
+	sessions, err := h.store.Sessions().ListActive(c.GetUint("userID"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении сессий"})
+		return
+	}
+
+	current := c.GetString("sessionID")
+	result := make([]SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, SessionInfo{
+			ID:         session.ID,
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			Current:    session.TokenID == current,
+		})
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Security BearerAuth
+// DeleteSession godoc
+// @Summary Завершить сессию
+// @Description Выходит из аккаунта на выбранном устройстве. Токены сессии сразу перестают приниматься.
+// @Tags auth
+// @Produce json
+// @Param id path string true "ID сессии"
+// @Success 200 {object} response.SuccessResponse "Сессия завершена"
+// @Failure 404 {object} response.ErrorResponse "Сессия не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка завершения сессии"
+// @Router /auth/sessions/{id} [delete]
+func (h *Handler) DeleteSession(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	sessionID, err := httputil.ParamID(c, "id")
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Сессия не найдена"})
+		return
+	}
+	session, err := h.store.Sessions().GetOwned(sessionID, userID)
+	if err != nil || session.RevokedAt != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Сессия не найдена"})
+		return
+	}
+
+	if err := revokeSession(h.store, session.TokenID, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка завершения сессии"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Сессия завершена"})
+}
+
+// @Security BearerAuth
+// DeleteSessions godoc
+// @Summary Выйти на всех устройствах
+// @Description Завершает все сессии пользователя. С exceptCurrent=true текущая сессия сохраняется.
+// @Tags auth
+// @Produce json
+// @Param exceptCurrent query bool false "Не завершать текущую сессию"
+// @Success 200 {object} response.SuccessResponse "Сессии завершены"
+// @Failure 500 {object} response.ErrorResponse "Ошибка завершения сессий"
+// @Router /auth/sessions [delete]
+func (h *Handler) DeleteSessions(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	except := ""
+	if c.Query("exceptCurrent") == "true" {
+		except = c.GetString("sessionID")
+	}
+
+	now := time.Now()
+	err := h.store.Atomic(func(s repository.Store) error {
+		if err := s.RefreshTokens().RevokeUser(userID, except, now); err != nil {
+			return err
+		}
+		return s.Sessions().RevokeUser(userID, except, now)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка завершения сессий"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Сессии завершены"})
+}
+
+var (
+	deviceBrowsers = []struct{ marker, name string }{
+		{"YaBrowser/", "Яндекс Браузер"},
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+	}
+	deviceSystems = []struct{ marker, name string }{
+		{"Android", "Android"},
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	}
+)
+
+// describeDevice составляет название устройства по User-Agent, например «Chrome, Windows».
+// Для остальных клиентов используется первое слово User-Agent.
+func describeDevice(userAgent string) string {
+	var parts []string
+	for _, browser := range deviceBrowsers {
+		if strings.Contains(userAgent, browser.marker) {
+			parts = append(parts, browser.name)
+			break
+		}
+	}
+	for _, system := range deviceSystems {
+		if strings.Contains(userAgent, system.marker) {
+			parts = append(parts, system.name)
+			break
+		}
+	}
+	if len(parts) > 0 {
+		return strings.Join(parts, ", ")
+	}
+	if fields := strings.Fields(userAgent); len(fields) > 0 {
+		return fields[0]
+	}
+	return "Неизвестное устройство"
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
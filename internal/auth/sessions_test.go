@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestSessions(t *testing.T) {
+	s := newTestServer(t)
+	s.addUser("user@example.com")
+	s.addUser("other@example.com")
+	laptop := s.login("user@example.com", "")
+	phone := s.login("user@example.com", "Телефон")
+	tablet := s.login("user@example.com", "Планшет")
+	other := s.login("other@example.com", "")
+
+	var sessions []SessionInfo
+	if code := s.call("GET", "/auth/sessions", laptop.Token, nil, &sessions); code != http.StatusOK || len(sessions) != 3 {
+		t.Fatalf("sessions: status %d, %+v", code, sessions)
+	}
+	ids := map[string]uint{}
+	for _, session := range sessions {
+		ids[session.DeviceName] = session.ID
+		if session.Current != (session.DeviceName == "Chrome, Windows") {
+			t.Errorf("сессия %q: Current = %v", session.DeviceName, session.Current)
+		}
+	}
+
+	var otherSessions []SessionInfo
+	s.call("GET", "/auth/sessions", other.Token, nil, &otherSessions)
+
+	steps := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   any
+		want   int
+	}{
+		{"завершить телефон", "DELETE", fmt.Sprintf("/auth/sessions/%d", ids["Телефон"]), laptop.Token, nil, http.StatusOK},
+		{"access телефона", "GET", "/auth/sessions", phone.Token, nil, http.StatusUnauthorized},
+		{"refresh телефона", "POST", "/auth/refresh", "", RefreshInput{phone.RefreshToken}, http.StatusUnauthorized},
+		{"завершить телефон повторно", "DELETE", fmt.Sprintf("/auth/sessions/%d", ids["Телефон"]), laptop.Token, nil, http.StatusNotFound},
+		{"чужая сессия", "DELETE", fmt.Sprintf("/auth/sessions/%d", otherSessions[0].ID), laptop.Token, nil, http.StatusNotFound},
+		{"неверный id", "DELETE", "/auth/sessions/abc", laptop.Token, nil, http.StatusNotFound},
+		{"кроме текущей", "DELETE", "/auth/sessions?exceptCurrent=true", laptop.Token, nil, http.StatusOK},
+		{"access планшета", "GET", "/auth/sessions", tablet.Token, nil, http.StatusUnauthorized},
+		{"access текущей", "GET", "/auth/sessions", laptop.Token, nil, http.StatusOK},
+		{"все сессии", "DELETE", "/auth/sessions", laptop.Token, nil, http.StatusOK},
+		{"access после выхода везде", "GET", "/auth/sessions", laptop.Token, nil, http.StatusUnauthorized},
+		{"refresh после выхода везде", "POST", "/auth/refresh", "", RefreshInput{laptop.RefreshToken}, http.StatusUnauthorized},
+		{"сессия другого пользователя", "GET", "/auth/sessions", other.Token, nil, http.StatusOK},
+		{"выход", "POST", "/auth/logout", "", RefreshInput{other.RefreshToken}, http.StatusOK},
+		{"access после выхода", "GET", "/auth/sessions", other.Token, nil, http.StatusUnauthorized},
+	}
+	for _, step := range steps {
+		if code := s.call(step.method, step.path, step.token, step.body, nil); code != step.want {
+			t.Errorf("%s: status %d, want %d", step.name, code, step.want)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/Anabol1ks/pers-fin-m/internal/response"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+)
+
+// Время жизни токенов по умолчанию. Переопределяется переменными
+// ACCESS_TOKEN_TTL и REFRESH_TOKEN_TTL в формате time.ParseDuration.
+const (
+	defaultAccessTTL  = 15 * time.Minute
+	defaultRefreshTTL = 30 * 24 * time.Hour
+	// challengeTTL — сколько действует токен второго шага входа с 2FA.
+	challengeTTL = 5 * time.Minute
+)
+
+// Значение claim typ. Access-токены его не содержат.
+const challengeTokenType = "2fa"
+
+// jwtKey возвращает ключ подписи. Читается при каждом вызове, потому что
+// переменные из .env загружаются уже после инициализации пакета.
+func jwtKey() []byte {
+	return []byte(os.Getenv("JWT_KEY"))
+}
+
+func envTTL(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		log.Printf("Неверный %s, используется %s", name, fallback)
+		return fallback
+	}
+	return ttl
+}
+
+// GenerateJWT выдаёт короткоживущий access-токен входа familyID.
+func GenerateJWT(userID uint, familyID string) (string, time.Time, error) {
+	expires := time.Now().Add(envTTL("ACCESS_TOKEN_TTL", defaultAccessTTL))
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"sid":     familyID,
+		"exp":     expires.Unix(),
+	})
+	signed, err := token.SignedString(jwtKey())
+	return signed, expires, err
+}
+
+// generateChallenge выдаёт токен второго шага входа: по нему и коду 2FA
+// /auth/2fa/verify выдаёт полноценную пару токенов. Название устройства,
+// указанное при входе, передаётся в токене.
+func generateChallenge(userID uint, deviceName string) (string, time.Time, error) {
+	expires := time.Now().Add(challengeTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"typ":     challengeTokenType,
+		"device":  deviceName,
+		"exp":     expires.Unix(),
+	})
+	signed, err := token.SignedString(jwtKey())
+	return signed, expires, err
+}
+
+// parseToken проверяет подпись и срок действия токена и возвращает его claims.
+func parseToken(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// Принимаем только HMAC, иначе подпись можно подменить сменой алгоритма
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("неожиданный алгоритм подписи %v", token.Header["alg"])
+		}
+		return jwtKey(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("недействительный токен")
+	}
+	return claims, nil
+}
+
+// issueTokens выдаёт пару access- и refresh-токенов сессии familyID
+// и продлевает сессию до срока нового refresh-токена.
+func issueTokens(s repository.Store, userID uint, familyID string) (*response.TokenResponse, error) {
+	refresh, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	refreshExpires := time.Now().Add(envTTL("REFRESH_TOKEN_TTL", defaultRefreshTTL))
+	err = s.RefreshTokens().Create(&models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refresh),
+		ExpiresAt: refreshExpires,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Sessions().Extend(familyID, refreshExpires); err != nil {
+		return nil, err
+	}
+
+	access, expires, err := GenerateJWT(userID, familyID)
+	if err != nil {
+		return nil, err
+	}
+	return &response.TokenResponse{
+		Token:            access,
+		ExpiresAt:        expires,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: refreshExpires,
+	}, nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashCode хеширует короткий код подтверждения. Шесть цифр перебираются по
+// простому sha256 мгновенно, поэтому используется HMAC с секретом сервера:
+// без JWT_KEY утёкший хеш бесполезен.
+func hashCode(code string) string {
+	mac := hmac.New(sha256.New, jwtKey())
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type RefreshInput struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+var errTokenReused = errors.New("refresh-токен использован повторно")
+
+// RefreshHandler godoc
+// @Summary Обновление токенов
+// @Description Обменивает refresh-токен на новую пару токенов. Каждый refresh-токен действует один раз:
+// @Description повторное использование считается утечкой, и все токены этого входа отзываются.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body RefreshInput true "Refresh-токен"
+// @Success 200 {object} response.TokenResponse "Новая пара токенов"
+// @Failure 400 {object} response.ErrorResponse "Описание ошибки валидации"
+// @Failure 401 {object} response.ErrorResponse "Недействительный refresh-токен"
+// @Failure 500 {object} response.ErrorResponse "Ошибка выдачи токенов"
+// @Router /auth/refresh [post]
+func (h *Handler) RefreshHandler(c *gin.Context) {
+	var input RefreshInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stored, err := h.store.RefreshTokens().GetByHash(hashToken(input.RefreshToken))
+	if err != nil || stored.RevokedAt != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Недействительный refresh-токен"})
+		return
+	}
+	if stored.ExpiresAt.Before(time.Now()) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Срок действия refresh-токена истёк"})
+		return
+	}
+
+	var tokens *response.TokenResponse
+	err = h.store.Atomic(func(s repository.Store) error {
+		used, err := s.RefreshTokens().MarkUsed(stored.ID, time.Now())
+		if err != nil {
+			return err
+		}
+		if !used {
+			return errTokenReused
+		}
+		tokens, err = issueTokens(s, stored.UserID, stored.FamilyID)
+		return err
+	})
+	if errors.Is(err, errTokenReused) {
+		log.Printf("Повторное использование refresh-токена пользователя %d, вход отозван", stored.UserID)
+		if err := revokeSession(h.store, stored.FamilyID, time.Now()); err != nil {
+			log.Println("Ошибка отзыва токенов:", err)
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Недействительный refresh-токен"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка выдачи токенов"})
+		return
+	}
+	if err := h.store.Sessions().Touch(stored.FamilyID, time.Now(), c.ClientIP()); err != nil {
+		log.Println("Ошибка обновления сессии:", err)
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// LogoutHandler godoc
+// @Summary Выход
+// @Description Завершает сессию refresh-токена: отзываются все её refresh-токены, а выданные access-токены перестают приниматься.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body RefreshInput true "Refresh-токен"
+// @Success 200 {object} response.SuccessResponse "Выход выполнен"
+// @Failure 400 {object} response.ErrorResponse "Описание ошибки валидации"
+// @Failure 401 {object} response.ErrorResponse "Недействительный refresh-токен"
+// @Failure 500 {object} response.ErrorResponse "Ошибка отзыва токена"
+// @Router /auth/logout [post]
+func (h *Handler) LogoutHandler(c *gin.Context) {
+	var input RefreshInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stored, err := h.store.RefreshTokens().GetByHash(hashToken(input.RefreshToken))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Недействительный refresh-токен"})
+		return
+	}
+	if err := revokeSession(h.store, stored.FamilyID, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка отзыва токена"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Выход выполнен"})
+}
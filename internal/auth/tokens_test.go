@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/response"
+)
+
+func TestRefreshReuse(t *testing.T) {
+	s := newTestServer(t)
+	s.addUser("user@example.com")
+	first := s.login("user@example.com", "")
+
+	var second response.TokenResponse
+	if code := s.call("POST", "/auth/refresh", "", RefreshInput{first.RefreshToken}, &second); code != http.StatusOK {
+		t.Fatalf("refresh: status %d", code)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("refresh вернул тот же refresh-токен")
+	}
+
+	// Повторное использование отзывает весь вход, включая новые токены
+	steps := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   any
+		want   int
+	}{
+		{"повторный refresh", "POST", "/auth/refresh", "", RefreshInput{first.RefreshToken}, http.StatusUnauthorized},
+		{"новый refresh", "POST", "/auth/refresh", "", RefreshInput{second.RefreshToken}, http.StatusUnauthorized},
+		{"новый access", "GET", "/auth/sessions", second.Token, nil, http.StatusUnauthorized},
+		{"неизвестный refresh", "POST", "/auth/refresh", "", RefreshInput{"unknown"}, http.StatusUnauthorized},
+	}
+	for _, step := range steps {
+		if code := s.call(step.method, step.path, step.token, step.body, nil); code != step.want {
+			t.Errorf("%s: status %d, want %d", step.name, code, step.want)
+		}
+	}
+
+	// Другой вход того же пользователя не затронут
+	other := s.login("user@example.com", "")
+	if code := s.call("POST", "/auth/refresh", "", RefreshInput{other.RefreshToken}, nil); code != http.StatusOK {
+		t.Errorf("refresh другого входа: status %d", code)
+	}
+}
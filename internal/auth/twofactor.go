@@ -0,0 +1,441 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image/png"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/Anabol1ks/pers-fin-m/internal/response"
+	"github.com/gin-gonic/gin"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	// totpPeriod — длина интервала TOTP. Принимаются коды текущего и соседних интервалов.
+	totpPeriod = 30
+	totpSkew   = 1
+	// maxTOTPAttempts неверных кодов подряд блокируют ввод на totpLockout.
+	maxTOTPAttempts = 5
+	totpLockout     = 15 * time.Minute
+	// recoveryCodeCount — сколько кодов восстановления выдаётся за раз.
+	recoveryCodeCount = 10
+	defaultTOTPIssuer = "PFM"
+	qrCodeSize        = 256
+)
+
+// TwoFactorSetup — данные для добавления аккаунта в приложение-аутентификатор.
+type TwoFactorSetup struct {
+	Secret string `json:"secret"` // для ручного ввода
+	URI    string `json:"uri"`    // otpauth://
+	QRCode string `json:"qrCode"` // PNG с URI в виде data:image/png;base64,...
+}
+
+type TwoFactorStatus struct {
+	Enabled           bool `json:"enabled"`
+	RecoveryCodesLeft int  `json:"recoveryCodesLeft"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"` // показываются один раз
+}
+
+type TwoFactorCodeInput struct {
+	Code string `json:"code" binding:"required"` // код из приложения или код восстановления
+}
+
+type TwoFactorVerifyInput struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required"` // код из приложения или код восстановления
+}
+
+// @Security BearerAuth
+// GetTwoFactor godoc
+// @Summary Состояние двухфакторной аутентификации
+// @Tags auth
+// @Produce json
+// @Success 200 {object} TwoFactorStatus
+// @Failure 500 {object} response.ErrorResponse "Пользователь не найден"
+// @Router /auth/2fa [get]
+func (h *Handler) GetTwoFactor(c *gin.Context) {
+	user, err := h.store.Users().GetByID(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
+		return
+	}
+
+	status := TwoFactorStatus{Enabled: user.TOTPEnabled}
+	if user.TOTPEnabled {
+		if status.RecoveryCodesLeft, err = h.store.RecoveryCodes().CountUnused(user.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении кодов восстановления"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// @Security BearerAuth
+// SetupTwoFactor godoc
+// @Summary Начать подключение 2FA
+// @Description Создаёт новый TOTP-секрет и возвращает его вместе с otpauth:// URI и QR-кодом.
+// @Description Двухфакторная аутентификация включается только после подтверждения кодом в /auth/2fa/enable.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} TwoFactorSetup
+// @Failure 409 {object} response.ErrorResponse "2FA уже включена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка создания секрета"
+// @Router /auth/2fa/setup [post]
+func (h *Handler) SetupTwoFactor(c *gin.Context) {
+	user, err := h.store.Users().GetByID(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
+		return
+	}
+	if user.TOTPEnabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Двухфакторная аутентификация уже включена"})
+		return
+	}
+
+	issuer := os.Getenv("TOTP_ISSUER")
+	if issuer == "" {
+		issuer = defaultTOTPIssuer
+	}
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: user.Email,
+		Period:      totpPeriod,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка создания секрета"})
+		return
+	}
+
+	image, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка создания QR-кода"})
+		return
+	}
+	var qr bytes.Buffer
+	if err := png.Encode(&qr, image); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка создания QR-кода"})
+		return
+	}
+
+	if err := h.store.Users().SetTOTP(user.ID, key.Secret(), false); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось обновить пользователя"})
+		return
+	}
+
+	c.JSON(http.StatusOK, TwoFactorSetup{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(qr.Bytes()),
+	})
+}
+
+// @Security BearerAuth
+// EnableTwoFactor godoc
+// @Summary Включить 2FA
+// @Description Подтверждает подключение кодом из приложения и возвращает одноразовые коды восстановления.
+// @Description Коды показываются один раз, каждый позволяет войти без приложения.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body TwoFactorCodeInput true "Код из приложения"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} response.ErrorResponse "Неверный код или подключение не начато"
+// @Failure 409 {object} response.ErrorResponse "2FA уже включена"
+// @Failure 429 {object} response.ErrorResponse "Слишком много неверных кодов"
+// @Failure 500 {object} response.ErrorResponse "Ошибка включения 2FA"
+// @Router /auth/2fa/enable [post]
+func (h *Handler) EnableTwoFactor(c *gin.Context) {
+	var input TwoFactorCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.store.Users().GetByID(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
+		return
+	}
+	if user.TOTPEnabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Двухфакторная аутентификация уже включена"})
+		return
+	}
+	if user.TOTPSecret == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Сначала начните подключение через /auth/2fa/setup"})
+		return
+	}
+	if !h.checkSecondFactor(c, user, input.Code) {
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка создания кодов восстановления"})
+		return
+	}
+	err = h.store.Atomic(func(s repository.Store) error {
+		if err := s.Users().SetTOTP(user.ID, user.TOTPSecret, true); err != nil {
+			return err
+		}
+		return s.RecoveryCodes().Replace(user.ID, hashes)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка включения двухфакторной аутентификации"})
+		return
+	}
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// @Security BearerAuth
+// DisableTwoFactor godoc
+// @Summary Отключить 2FA
+// @Description Отключает двухфакторную аутентификацию. Требуется код из приложения или код восстановления.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body TwoFactorCodeInput true "Код из приложения или код восстановления"
+// @Success 200 {object} response.SuccessResponse "2FA отключена"
+// @Failure 400 {object} response.ErrorResponse "Неверный код или 2FA не включена"
+// @Failure 429 {object} response.ErrorResponse "Слишком много неверных кодов"
+// @Failure 500 {object} response.ErrorResponse "Ошибка отключения 2FA"
+// @Router /auth/2fa/disable [post]
+func (h *Handler) DisableTwoFactor(c *gin.Context) {
+	user, ok := h.enabledTwoFactor(c)
+	if !ok {
+		return
+	}
+
+	err := h.store.Atomic(func(s repository.Store) error {
+		if err := s.Users().SetTOTP(user.ID, "", false); err != nil {
+			return err
+		}
+		return s.RecoveryCodes().DeleteForUser(user.ID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка отключения двухфакторной аутентификации"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Двухфакторная аутентификация отключена"})
+}
+
+// @Security BearerAuth
+// RegenerateRecoveryCodes godoc
+// @Summary Новые коды восстановления
+// @Description Выдаёт новый набор кодов восстановления, прежние перестают действовать.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body TwoFactorCodeInput true "Код из приложения или код восстановления"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} response.ErrorResponse "Неверный код или 2FA не включена"
+// @Failure 429 {object} response.ErrorResponse "Слишком много неверных кодов"
+// @Failure 500 {object} response.ErrorResponse "Ошибка создания кодов восстановления"
+// @Router /auth/2fa/recovery-codes [post]
+func (h *Handler) RegenerateRecoveryCodes(c *gin.Context) {
+	user, ok := h.enabledTwoFactor(c)
+	if !ok {
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err == nil {
+		err = h.store.RecoveryCodes().Replace(user.ID, hashes)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка создания кодов восстановления"})
+		return
+	}
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// VerifyTwoFactor godoc
+// @Summary Второй шаг входа
+// @Description Обменивает токен, полученный при входе, и код 2FA на пару access- и refresh-токенов.
+// @Description Вместо кода из приложения можно ввести код восстановления, каждый действует один раз.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body TwoFactorVerifyInput true "Токен второго шага и код"
+// @Success 200 {object} response.TokenResponse "Успешная авторизация"
+// @Failure 400 {object} response.ErrorResponse "Неверный код"
+// @Failure 401 {object} response.ErrorResponse "Недействительный токен второго шага"
+// @Failure 429 {object} response.ErrorResponse "Слишком много неверных кодов"
+// @Failure 500 {object} response.ErrorResponse "Ошибка выдачи токенов"
+// @Router /auth/2fa/verify [post]
+func (h *Handler) VerifyTwoFactor(c *gin.Context) {
+	var input TwoFactorVerifyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, err := parseToken(input.ChallengeToken)
+	userID, _ := claims["user_id"].(float64)
+	if err != nil || userID <= 0 || claims["typ"] != challengeTokenType {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Недействительный или просроченный токен, войдите заново"})
+		return
+	}
+	user, err := h.store.Users().GetByID(uint(userID))
+	if err != nil || !user.TOTPEnabled {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Недействительный или просроченный токен, войдите заново"})
+		return
+	}
+	if !h.checkSecondFactor(c, user, input.Code) {
+		return
+	}
+
+	deviceName, _ := claims["device"].(string)
+	tokens, err := h.startSession(c, user.ID, deviceName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка выдачи токенов"})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// loginChallenge отвечает на вход пользователя с 2FA токеном второго шага.
+func loginChallenge(c *gin.Context, user *models.User, deviceName string) {
+	challenge, expires, err := generateChallenge(user.ID, deviceName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка выдачи токенов"})
+		return
+	}
+	c.JSON(http.StatusAccepted, response.TwoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    challenge,
+		ExpiresAt:         expires,
+	})
+}
+
+// enabledTwoFactor читает код из запроса и проверяет его для пользователя с включённой 2FA.
+func (h *Handler) enabledTwoFactor(c *gin.Context) (*models.User, bool) {
+	var input TwoFactorCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	user, err := h.store.Users().GetByID(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
+		return nil, false
+	}
+	if !user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Двухфакторная аутентификация не включена"})
+		return nil, false
+	}
+	return user, h.checkSecondFactor(c, user, input.Code)
+}
+
+var (
+	errInvalidCode = errors.New("неверный код")
+	errCodeReused  = errors.New("код уже использован")
+)
+
+// checkSecondFactor проверяет TOTP-код, а при включённой 2FA — и код восстановления.
+// При ошибке отвечает клиенту сам. Неверные коды считаются, после
+// maxTOTPAttempts подряд ввод блокируется на totpLockout.
+func (h *Handler) checkSecondFactor(c *gin.Context, user *models.User, code string) bool {
+	now := time.Now()
+	if user.TOTPLockedUntil != nil && user.TOTPLockedUntil.After(now) {
+		minutes := int(user.TOTPLockedUntil.Sub(now).Minutes()) + 1
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": fmt.Sprintf("Слишком много неверных кодов, попробуйте через %d мин", minutes)})
+		return false
+	}
+
+	code = strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+	var err error
+	if step, ok := matchTOTP(user.TOTPSecret, code, now); ok {
+		var used bool
+		if used, err = h.store.Users().UseTOTPStep(user.ID, step); err == nil && !used {
+			err = errCodeReused
+		}
+	} else if user.TOTPEnabled && len(code) != 6 {
+		var used bool
+		if used, err = h.store.RecoveryCodes().Use(user.ID, hashToken(code), now); err == nil && !used {
+			err = errInvalidCode
+		}
+	} else {
+		err = errInvalidCode
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, errInvalidCode), errors.Is(err, errCodeReused):
+		msg := "Неверный код"
+		if errors.Is(err, errCodeReused) {
+			msg = "Код уже использован, дождитесь следующего"
+		}
+		if err := h.store.Users().RecordTOTPFailure(user.ID, maxTOTPAttempts, now.Add(totpLockout)); err != nil {
+			log.Println("Ошибка учёта неверного кода 2FA:", err)
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return false
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка проверки кода"})
+		return false
+	}
+
+	if user.TOTPFailedAttempts > 0 || user.TOTPLockedUntil != nil {
+		if err := h.store.Users().ResetTOTPFailures(user.ID); err != nil {
+			log.Println("Ошибка сброса счётчика кодов 2FA:", err)
+		}
+	}
+	return true
+}
+
+// matchTOTP ищет интервал, которому соответствует код, среди текущего и соседних.
+func matchTOTP(secret, code string, now time.Time) (int64, bool) {
+	if secret == "" || len(code) != 6 {
+		return 0, false
+	}
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0), totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// newRecoveryCodes создаёт коды восстановления вида xxxxx-xxxxx и их хеши.
+// Хеш считается от кода в нижнем регистре без дефиса.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for range recoveryCodeCount {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(encoding.EncodeToString(b))[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashToken(raw))
+	}
+	return codes, hashes, nil
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/response"
+	"github.com/pquerna/otp/totp"
+)
+
+func TestTwoFactor(t *testing.T) {
+	s := newTestServer(t)
+	s.addUser("user@example.com")
+	token := s.login("user@example.com", "").Token
+
+	var setup TwoFactorSetup
+	if code := s.call("POST", "/auth/2fa/setup", token, nil, &setup); code != http.StatusOK {
+		t.Fatalf("setup: status %d", code)
+	}
+	if !strings.HasPrefix(setup.URI, "otpauth://totp/") || !strings.HasPrefix(setup.QRCode, "data:image/png;base64,") {
+		t.Errorf("setup = %+v", setup)
+	}
+	totpCode := func(at time.Time) string {
+		code, err := totp.GenerateCode(setup.Secret, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+	// Код, заведомо не совпадающий ни с одним из принимаемых интервалов
+	wrong := "000000"
+	for _, at := range []time.Duration{-totpPeriod, 0, totpPeriod} {
+		if totpCode(time.Now().Add(at*time.Second)) == wrong {
+			wrong = "999999"
+		}
+	}
+
+	if code := s.call("POST", "/auth/2fa/enable", token, TwoFactorCodeInput{wrong}, nil); code != http.StatusBadRequest {
+		t.Errorf("enable с неверным кодом: status %d, want 400", code)
+	}
+	current := totpCode(time.Now())
+	var recovery RecoveryCodesResponse
+	if code := s.call("POST", "/auth/2fa/enable", token, TwoFactorCodeInput{current}, &recovery); code != http.StatusOK {
+		t.Fatalf("enable: status %d", code)
+	}
+	if len(recovery.RecoveryCodes) != recoveryCodeCount {
+		t.Fatalf("кодов восстановления: %d, want %d", len(recovery.RecoveryCodes), recoveryCodeCount)
+	}
+
+	challenge := func() string {
+		var resp response.TwoFactorChallengeResponse
+		if code := s.call("POST", "/auth/login", "", LoginInput{Email: "user@example.com", Password: testPassword, DeviceName: "Телефон"}, &resp); code != http.StatusAccepted || !resp.TwoFactorRequired {
+			t.Fatalf("login с 2FA: status %d, %+v", code, resp)
+		}
+		return resp.ChallengeToken
+	}
+	pending := challenge()
+	if code := s.call("GET", "/auth/sessions", pending, nil, nil); code != http.StatusUnauthorized {
+		t.Errorf("токен второго шага как access: status %d, want 401", code)
+	}
+
+	recoveryCode := strings.ToUpper(recovery.RecoveryCodes[0])
+	steps := []struct {
+		name      string
+		token     string
+		code      string
+		want      int
+		wantError string
+	}{
+		{"неверный токен", token, current, http.StatusUnauthorized, "Недействительный или просроченный токен, войдите заново"},
+		{"код уже использован", pending, current, http.StatusBadRequest, "Код уже использован, дождитесь следующего"},
+		{"код следующего интервала", pending, totpCode(time.Now().Add(totpPeriod * time.Second)), http.StatusOK, ""},
+		{"код восстановления", challenge(), recoveryCode, http.StatusOK, ""},
+		{"код восстановления повторно", challenge(), recoveryCode, http.StatusBadRequest, "Неверный код"},
+		{"ошибка 2", pending, wrong, http.StatusBadRequest, "Неверный код"},
+		{"ошибка 3", pending, wrong, http.StatusBadRequest, "Неверный код"},
+		{"ошибка 4", pending, wrong, http.StatusBadRequest, "Неверный код"},
+		{"ошибка 5", pending, wrong, http.StatusBadRequest, "Неверный код"},
+		{"блокировка", pending, recovery.RecoveryCodes[1], http.StatusTooManyRequests, "Слишком много неверных кодов, попробуйте через 15 мин"},
+	}
+	for _, step := range steps {
+		var body struct {
+			errorBody
+			response.TokenResponse
+		}
+		code := s.call("POST", "/auth/2fa/verify", "", TwoFactorVerifyInput{ChallengeToken: step.token, Code: step.code}, &body)
+		if code != step.want || body.Error != step.wantError {
+			t.Errorf("%s: status %d %q, want %d %q", step.name, code, body.Error, step.want, step.wantError)
+		}
+		if code == http.StatusOK && s.call("GET", "/auth/sessions", body.Token, nil, nil) != http.StatusOK {
+			t.Errorf("%s: выданный access-токен не принимается", step.name)
+		}
+	}
+}
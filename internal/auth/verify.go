@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// verificationCodeTTL — срок действия кода подтверждения почты.
+	verificationCodeTTL     = 15 * time.Minute
+	verificationCodeTTLText = "15 минут"
+	// maxVerifyAttempts — сколько раз можно ввести код, после чего он аннулируется.
+	maxVerifyAttempts = 5
+	// verifyResendCooldown — минимальный интервал между письмами с кодом.
+	verifyResendCooldown = time.Minute
+)
+
+func GenerateVerificationCode() (string, error) {
+	max := big.NewInt(1000000) // Диапазон [0, 1000000)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+	return code, nil
+}
+
+// sendVerificationCode выдаёт пользователю новый код подтверждения и отправляет
+// его на почту. В базе сохраняется только хеш кода, счётчик попыток сбрасывается.
+func (h *Handler) sendVerificationCode(user *models.User) error {
+	code, err := GenerateVerificationCode()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := h.store.Users().SetVerificationCode(user.ID, hashCode(code), now.Add(verificationCodeTTL), now); err != nil {
+		return err
+	}
+
+	if err := h.mailer.SendVerifyCode(user.Username, user.Email, code, verificationCodeTTLText); err != nil {
+		// Письмо не ушло — не считаем его отправленным, чтобы не блокировать повторный запрос
+		if err := h.store.Users().SetVerificationSentAt(user.ID, user.VerificationSentAt); err != nil {
+			log.Println("Ошибка обновления пользователя:", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// @Security BearerAuth
+// SendNewVerify godoc
+// @Summary Запрос на новое письмо с кодом
+// @Description Отправляет новый код подтверждения, предыдущий код перестаёт действовать.
+// @Description Код действует 15 минут, повторно запросить его можно не чаще раза в минуту.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.SuccessResponse "Письмо отправлено"
+// @Failure 409 {object} response.ErrorResponse "Аккаунт уже подтверждён"
+// @Failure 429 {object} response.ErrorResponse "Код недавно отправлен"
+// @Failure 500 {object} response.ErrorResponse "Ошибка отправки письма"
+// @Router /auth/newVerify [post]
+func (h *Handler) SendNewVerify(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	user, err := h.store.Users().GetByID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
+		return
+	}
+
+	if user.Verified {
+		c.JSON(http.StatusConflict, gin.H{"error": "Аккаунт уже подтверждён"})
+		return
+	}
+
+	if user.VerificationSentAt != nil {
+		if wait := time.Until(user.VerificationSentAt.Add(verifyResendCooldown)); wait > 0 {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": fmt.Sprintf("Код уже отправлен, повторный запрос возможен через %d с", int(wait.Seconds())+1)})
+			return
+		}
+	}
+
+	if err := h.sendVerificationCode(user); err != nil {
+		log.Printf("Ошибка отправки кода подтверждения пользователю %d: %v", user.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка отправки письма"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Письмо отправлено"})
+}
+
+type VerificationCodeInput struct {
+	Code string `json:"code" binding:"required,len=6"`
+}
+
+// @Security BearerAuth
+// VerifyEmailHandler godoc
+// @Summary Подтверждение аккаунта
+// @Description Подтверждение аккаунта кодом из письма. После 5 неверных попыток код аннулируется и нужно запросить новый
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body VerificationCodeInput true "Данные пользователя"
+// @Success 200 {object} response.SuccessResponse "Аккаунт успешно подтверждён"
+// @Failure 400 {object} response.ErrorResponse "Неверный или просроченный код подтверждения"
+// @Failure 409 {object} response.ErrorResponse "Аккаунт уже подтверждён"
+// @Failure 429 {object} response.ErrorResponse "Превышено число попыток"
+// @Failure 500 {object} response.ErrorResponse "Пользователь не существует"
+// @Router /auth/verify [post]
+func (h *Handler) VerifyEmailHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input VerificationCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.store.Users().GetByID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
+		return
+	}
+
+	if user.Verified {
+		c.JSON(http.StatusConflict, gin.H{"error": "Аккаунт уже подтверждён"})
+		return
+	}
+
+	if user.VerificationCode == "" || user.VerificationExpiresAt == nil || user.VerificationExpiresAt.Before(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Код подтверждения истёк, запросите новый"})
+		return
+	}
+
+	// Попытка засчитывается до сравнения, чтобы параллельные запросы не обошли лимит
+	allowed, err := h.store.Users().RecordVerifyAttempt(user.ID, maxVerifyAttempts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось обновить пользователя"})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Превышено число попыток, запросите новый код"})
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashCode(input.Code)), []byte(user.VerificationCode)) != 1 {
+		left := maxVerifyAttempts - user.VerificationAttempts - 1
+		if left <= 0 {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Неверный код подтверждения, попытки исчерпаны, запросите новый код"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Неверный код подтверждения, осталось попыток: %d", left)})
+		return
+	}
+
+	if err := h.store.Users().MarkVerified(user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось обновить пользователя"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Аккаунт успешно подтверждён"})
+}
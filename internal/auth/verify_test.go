@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestVerifyLockout(t *testing.T) {
+	s := newTestServer(t)
+	register := RegisterInput{Username: "test", Email: "user@example.com", Password: testPassword}
+	if code := s.call("POST", "/auth/register", "", register, nil); code != http.StatusCreated {
+		t.Fatalf("register: status %d", code)
+	}
+	code := <-s.mail.codes
+	wrong := "000000"
+	if code == wrong {
+		wrong = "000001"
+	}
+	token := s.login("user@example.com", "").Token
+
+	steps := []struct {
+		name      string
+		code      string
+		want      int
+		wantError string
+	}{
+		{"первая ошибка", wrong, http.StatusBadRequest, "Неверный код подтверждения, осталось попыток: 4"},
+		{"вторая ошибка", wrong, http.StatusBadRequest, "Неверный код подтверждения, осталось попыток: 3"},
+		{"третья ошибка", wrong, http.StatusBadRequest, "Неверный код подтверждения, осталось попыток: 2"},
+		{"четвёртая ошибка", wrong, http.StatusBadRequest, "Неверный код подтверждения, осталось попыток: 1"},
+		{"пятая ошибка", wrong, http.StatusTooManyRequests, "Неверный код подтверждения, попытки исчерпаны, запросите новый код"},
+		{"верный код после блокировки", code, http.StatusTooManyRequests, "Превышено число попыток, запросите новый код"},
+	}
+	for _, step := range steps {
+		var body errorBody
+		if got := s.call("POST", "/auth/verify", token, VerificationCodeInput{step.code}, &body); got != step.want || body.Error != step.wantError {
+			t.Errorf("%s: status %d %q, want %d %q", step.name, got, body.Error, step.want, step.wantError)
+		}
+	}
+
+	// Новый код можно запросить не раньше чем через минуту
+	if got := s.call("POST", "/auth/newVerify", token, nil, nil); got != http.StatusTooManyRequests {
+		t.Errorf("newVerify сразу: status %d, want 429", got)
+	}
+	user, err := s.store.Users().GetByEmail("user@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sentAt := time.Now().Add(-verifyResendCooldown)
+	if err := s.store.Users().SetVerificationSentAt(user.ID, &sentAt); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.call("POST", "/auth/newVerify", token, nil, nil); got != http.StatusOK {
+		t.Fatalf("newVerify: status %d, want 200", got)
+	}
+	fresh := <-s.mail.codes
+
+	// Новый код сбрасывает счётчик попыток, прежний код не действует
+	if fresh != code {
+		if got := s.call("POST", "/auth/verify", token, VerificationCodeInput{code}, nil); got != http.StatusBadRequest {
+			t.Errorf("прежний код: status %d, want 400", got)
+		}
+	}
+	if got := s.call("POST", "/auth/verify", token, VerificationCodeInput{fresh}, nil); got != http.StatusOK {
+		t.Errorf("новый код: status %d, want 200", got)
+	}
+	if got := s.call("POST", "/auth/verify", token, VerificationCodeInput{fresh}, nil); got != http.StatusConflict {
+		t.Errorf("повторное подтверждение: status %d, want 409", got)
+	}
+}
@@ -0,0 +1,69 @@
+package banksms
+
+import "github.com/Anabol1ks/pers-fin-m/internal/models"
+
+// Коды встроенных банков.
+const (
+	Sber  = "sber"
+	TBank = "tbank"
+	Alfa  = "alfa"
+	VTB   = "vtb"
+)
+
+// Шаблоны внутри банка проверяются по порядку: поступления идут раньше
+// списаний, потому что «Перевод» без уточнения — это исходящий перевод.
+// Необязательный магазин захватывается лениво (??), чтобы не поглотить остаток.
+func init() {
+	// ECMC1234 14:32 Покупка 1 250р MAGNIT Баланс: 10 000р
+	// СЧЁТ1234 10:00 перевод 5000р от Иван И. Баланс: 15 000р
+	// Иван Иванович И. перевёл(а) вам 1 000р.
+	sberPrefix := `^(?:СБЕР:? )?(?:{card} )?(?:{date} )?(?:{time} )?`
+	Register(RuleSet{Name: Sber, Templates: []Template{
+		{Type: models.Income, Pattern: MustCompile(sberPrefix + `{merchant} перев[её]л(?:\(а\))? вам {amount} ?{currency}`)},
+		{Type: models.Income, Pattern: MustCompile(sberPrefix + `Перевод от {merchant} {amount} ?{currency}(?: Баланс:? {balance})?\.?$`)},
+		{Type: models.Income, Pattern: MustCompile(sberPrefix + `Перевод {amount} ?{currency} от {merchant}(?: Баланс:? {balance})?\.?$`)},
+		{Type: models.Income, Pattern: MustCompile(sberPrefix +
+			`(?:Зачисление(?: зарплаты)?|Поступление|Пополнение|Возврат(?: покупки)?|Отмена покупки|Зарплата|Аванс) {amount} ?{currency}` +
+			`(?: {merchant})??(?: Баланс:? {balance})?\.?$`)},
+		{Type: models.Expense, Pattern: MustCompile(sberPrefix +
+			`(?:Покупка|Оплата(?: услуг)?|Списание|Выдача(?: наличных)?|Перевод|Платёж|Платеж)(?: на сумму)? {amount} ?{currency}` +
+			`(?: {merchant})??(?: Баланс:? {balance})?\.?$`)},
+	}})
+
+	// Покупка, карта *1234. 1250 RUB. MAGNIT. Доступно 10000 RUB
+	// Пополнение, счет RUB. 5000 RUB. Иван И. Доступно 15000 RUB
+	tbankPrefix := `^(?:(?:Т-Банк|T-Bank|Тинькофф|Tinkoff):? )?`
+	tbankTail := `[.,]?(?:(?: от)? {merchant})??(?:[.,]? (?:Доступно|Баланс):? {balance})?\.?$`
+	Register(RuleSet{Name: TBank, Templates: []Template{
+		{Type: models.Income, Pattern: MustCompile(tbankPrefix +
+			`(?:Пополнение|Зачисление|Поступление|Возврат|Отмена операции|Входящий перевод|Перевод от)(?: СБП)?[.,]?` +
+			`(?: (?:карта|счет|счёт) (?:{card}|[A-Z]{3})[.,]?)?(?: Сумма)? {amount} ?{currency}` + tbankTail)},
+		{Type: models.Expense, Pattern: MustCompile(tbankPrefix +
+			`(?:Покупка|Оплата|Списание|Снятие(?: наличных)?|Перевод|Платёж|Платеж)(?: СБП)?[.,]?` +
+			`(?: (?:карта|счет|счёт) (?:{card}|[A-Z]{3})[.,]?)?(?: Сумма)? {amount} ?{currency}` + tbankTail)},
+	}})
+
+	// Покупка 1250,00 RUR MAGNIT. Карта *1234. Остаток: 10000,00 RUR
+	// Поступление 5000,00 RUR на счет *1234 от Иван И. Остаток 15000,00 RUR
+	alfaPrefix := `^(?:Альфа-Банк:? |Alfa-Bank:? )?(?:(?:Карта )?{card}[.,]? )?`
+	alfaTail := `(?:[.,]? (?:Карта|Сч[её]т) {card})?(?:[.,]? {date}(?: {time})?)?(?:[.,]? (?:Остаток|Доступно|Баланс):? {balance})?\.?$`
+	Register(RuleSet{Name: Alfa, Templates: []Template{
+		{Type: models.Income, Pattern: MustCompile(alfaPrefix +
+			`(?:Поступление|Зачисление|Пополнение|Возврат|Входящий перевод|Перевод от) {amount} ?{currency}` +
+			`(?: на (?:сч[её]т|карту) {card})?(?:(?: от)? {merchant})??` + alfaTail)},
+		{Type: models.Expense, Pattern: MustCompile(alfaPrefix +
+			`(?:Покупка|Оплата|Списание|Снятие(?: наличных)?|Перевод|Платёж|Платеж)(?: на)? {amount} ?{currency}` +
+			`(?: {merchant})??` + alfaTail)},
+	}})
+
+	// Оплата 1250р Карта*1234 MAGNIT Баланс 10000.00р 14:32
+	// Поступление 5000р Счет*1234 от ИВАН И. Баланс 15000р 10:00
+	vtbPrefix := `^(?:ВТБ:? |VTB:? )?`
+	vtbTail := ` (?:Карта|Сч[её]т) ?{card}(?:(?: от)? {merchant})??(?: Баланс:? {balance})?(?: {time})?\.?$`
+	Register(RuleSet{Name: VTB, Templates: []Template{
+		{Type: models.Income, Pattern: MustCompile(vtbPrefix +
+			`(?:Поступление|Зачисление|Пополнение|Возврат) {amount} ?{currency}` + vtbTail)},
+		{Type: models.Expense, Pattern: MustCompile(vtbPrefix +
+			`(?:Оплата|Покупка|Списание|Снятие(?: наличных)?|Перевод|Платёж|Платеж) {amount} ?{currency}` + vtbTail)},
+	}})
+}
@@ -0,0 +1,139 @@
+package banksms
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	at := func(d, hh, mm int) time.Time {
+		return time.Date(2024, 3, d, hh, mm, 0, 0, time.UTC)
+	}
+	balance := func(s string) *money.Amount {
+		a := money.MustParse(s)
+		return &a
+	}
+
+	tests := []struct {
+		text string
+		bank string
+		want *Match
+	}{
+		{
+			text: "ECMC1234 14:32 Покупка 1 250р MAGNIT Баланс: 10 000р",
+			want: &Match{Bank: Sber, Type: models.Expense, Amount: 125000, Currency: "RUB", Merchant: "MAGNIT", Balance: balance("10000"), Card: "ECMC1234", Date: at(9, 14, 32)},
+		},
+		{
+			text: "СЧЁТ1234 10:00 перевод 5000р от Иван И. Баланс: 15 000р",
+			want: &Match{Bank: Sber, Type: models.Income, Amount: 500000, Currency: "RUB", Merchant: "Иван И", Balance: balance("15000"), Card: "СЧЁТ1234", Date: at(10, 10, 0)},
+		},
+		{
+			text: "Иван Иванович И. перевёл(а) вам 1 000р.",
+			want: &Match{Bank: Sber, Type: models.Income, Amount: 100000, Currency: "RUB", Merchant: "Иван Иванович И"},
+		},
+		{
+			text: "СБЕР: MIR-1234 09.03.24 18:05\nОплата услуг 350,50 ₽ МТС Баланс 999.99 р",
+			want: &Match{Bank: Sber, Type: models.Expense, Amount: 35050, Currency: "RUB", Merchant: "МТС", Balance: balance("999.99"), Card: "MIR-1234", Date: at(9, 18, 5)},
+		},
+		{
+			text: "Покупка, карта *1234. 1250 RUB. MAGNIT. Доступно 10000 RUB",
+			want: &Match{Bank: TBank, Type: models.Expense, Amount: 125000, Currency: "RUB", Merchant: "MAGNIT", Balance: balance("10000"), Card: "*1234"},
+		},
+		{
+			text: "Пополнение, счет RUB. 5000 RUB. Иван И. Доступно 15000 RUB",
+			want: &Match{Bank: TBank, Type: models.Income, Amount: 500000, Currency: "RUB", Merchant: "Иван И", Balance: balance("15000")},
+		},
+		{
+			text: "Покупка 1250,00 RUR MAGNIT. Карта *1234. Остаток: 10000,00 RUR",
+			want: &Match{Bank: Alfa, Type: models.Expense, Amount: 125000, Currency: "RUB", Merchant: "MAGNIT", Balance: balance("10000"), Card: "*1234"},
+		},
+		{
+			text: "Поступление 5000,00 RUR на счет *1234 от Иван И. Остаток 15000,00 RUR",
+			want: &Match{Bank: Alfa, Type: models.Income, Amount: 500000, Currency: "RUB", Merchant: "Иван И", Balance: balance("15000"), Card: "*1234"},
+		},
+		{
+			text: "Оплата 1250р Карта*1234 MAGNIT Баланс 10000.00р 14:32",
+			want: &Match{Bank: VTB, Type: models.Expense, Amount: 125000, Currency: "RUB", Merchant: "MAGNIT", Balance: balance("10000"), Card: "*1234", Date: at(9, 14, 32)},
+		},
+		{
+			text: "Поступление 5000р Счет*1234 от ИВАН И. Баланс 15000р 10:00",
+			want: &Match{Bank: VTB, Type: models.Income, Amount: 500000, Currency: "RUB", Merchant: "ИВАН И", Balance: balance("15000"), Card: "*1234", Date: at(10, 10, 0)},
+		},
+		{
+			text: "Покупка 12.5 USD STARBUCKS",
+			bank: Sber,
+			want: &Match{Bank: Sber, Type: models.Expense, Amount: 1250, Currency: "USD", Merchant: "STARBUCKS"},
+		},
+		{text: "Иван Иванович И. перевёл(а) вам 1 000р.", bank: VTB},
+		{text: "Ваш код подтверждения 1234"},
+		{text: "ECMC1234 13:00 Покупка 0р MAGNIT"},
+	}
+	for _, tt := range tests {
+		got, ok := Parse(tt.text, tt.bank, now)
+		if ok != (tt.want != nil) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q, %q) = %+v, %v, want %+v", tt.text, tt.bank, got, ok, tt.want)
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	re, err := Compile(`Списано {amount} {currency} в {merchant}$`)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	m, ok := Template{Type: models.Expense, Pattern: re}.Match("списано 99,90 руб. в Кофейня", time.Now())
+	if !ok || m.Amount != 9990 || m.Currency != "RUB" || m.Merchant != "Кофейня" {
+		t.Errorf("Match() = %+v, %v", m, ok)
+	}
+
+	for _, pattern := range []string{`Списано {currency}`, `Списано (`} {
+		if _, err := Compile(pattern); err == nil {
+			t.Errorf("Compile(%q) error = nil, want error", pattern)
+		}
+	}
+}
+
+func TestCurrencyCode(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", ""},
+		{"р.", "RUB"},
+		{"₽", "RUB"},
+		{"рублей", "RUB"},
+		{"RUR", "RUB"},
+		{"$", "USD"},
+		{"€", "EUR"},
+		{"¥", "CNY"},
+		{"usd", "USD"},
+	}
+	for _, tt := range tests {
+		if got := CurrencyCode(tt.in); got != tt.want {
+			t.Errorf("CurrencyCode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMessageDate(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		date, clock string
+		want        time.Time
+	}{
+		{"", "", time.Time{}},
+		{"", "11:59", time.Date(2024, 3, 10, 11, 59, 0, 0, time.UTC)},
+		{"", "23:10", time.Date(2024, 3, 9, 23, 10, 0, 0, time.UTC)},
+		{"01.03", "", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"31.12.23", "08:00", time.Date(2023, 12, 31, 8, 0, 0, 0, time.UTC)},
+		{"15.03.2024", "25:00", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"32.01", "", time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := messageDate(tt.date, tt.clock, now); !got.Equal(tt.want) {
+			t.Errorf("messageDate(%q, %q) = %v, want %v", tt.date, tt.clock, got, tt.want)
+		}
+	}
+}
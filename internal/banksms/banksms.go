@@ -0,0 +1,246 @@
+// Package banksms разбирает тексты SMS и push-уведомлений банков.
+//
+// Каждый банк описывается разборщиком Parser. Встроенные наборы правил
+// (Сбер, Т-Банк, Альфа, ВТБ) — это списки шаблонов на регулярных выражениях,
+// они регистрируются через Register при инициализации пакета. Пользовательские
+// шаблоны из БД разбираются тем же кодом и проверяются раньше встроенных.
+package banksms
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+)
+
+// Match — распознанное уведомление.
+type Match struct {
+	Bank     string
+	Type     models.TransactionType
+	Amount   money.Amount
+	Currency string        // код валюты, пусто, если в тексте её нет
+	Merchant string        // магазин или отправитель перевода
+	Balance  *money.Amount // остаток по счёту после операции
+	Card     string        // номер или маска карты, например *1234
+	Date     time.Time     // нулевая, если в тексте нет ни даты, ни времени
+}
+
+// Parser распознаёт уведомления одного банка.
+type Parser interface {
+	Bank() string
+	Parse(text string, now time.Time) (*Match, bool)
+}
+
+var parsers []Parser
+
+// Register добавляет разборщик банка. Разборщики проверяются в порядке регистрации.
+func Register(p Parser) {
+	parsers = append(parsers, p)
+}
+
+// Banks возвращает коды зарегистрированных банков.
+func Banks() []string {
+	banks := make([]string, len(parsers))
+	for i, p := range parsers {
+		banks[i] = p.Bank()
+	}
+	return banks
+}
+
+// Parse распознаёт уведомление встроенными правилами. Если bank не пуст,
+// проверяются только правила этого банка. Тексты разных банков похожи,
+// поэтому из подошедших правил выбирается самое полное совпадение,
+// при равенстве — банк, зарегистрированный раньше.
+func Parse(text, bank string, now time.Time) (*Match, bool) {
+	text = Normalize(text)
+	var best *Match
+	for _, p := range parsers {
+		if bank != "" && p.Bank() != bank {
+			continue
+		}
+		if m, ok := p.Parse(text, now); ok && (best == nil || m.score() > best.score()) {
+			best = m
+		}
+	}
+	return best, best != nil
+}
+
+// score считает распознанные поля. Слишком общее правило обычно оставляет
+// их в названии магазина.
+func (m *Match) score() int {
+	score := 0
+	for _, filled := range []bool{m.Currency != "", m.Merchant != "", m.Balance != nil, m.Card != "", !m.Date.IsZero()} {
+		if filled {
+			score++
+		}
+	}
+	return score
+}
+
+// Normalize схлопывает пробелы и переводы строк, в том числе неразрывные.
+func Normalize(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// Template — шаблон уведомления одного вида.
+type Template struct {
+	Type    models.TransactionType
+	Pattern *regexp.Regexp
+}
+
+// RuleSet — разборщик банка на основе списка шаблонов.
+type RuleSet struct {
+	Name      string
+	Templates []Template
+}
+
+func (r RuleSet) Bank() string {
+	return r.Name
+}
+
+func (r RuleSet) Parse(text string, now time.Time) (*Match, bool) {
+	for _, t := range r.Templates {
+		if m, ok := t.Match(text, now); ok {
+			m.Bank = r.Name
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// Подстановки для шаблонов. Валюта остатка не захватывается, она совпадает
+// с валютой операции или счёта.
+const (
+	number   = `\d[\d ]*(?:[.,]\d{1,2})?`
+	currency = `руб(?:лей|ля|ль|\.)?|р\.?|₽|RUB|RUR|USD|\$|EUR|€|CNY|¥`
+)
+
+var placeholders = strings.NewReplacer(
+	"{amount}", `(?P<amount>`+number+`)`,
+	"{currency}", `(?P<currency>`+currency+`)`,
+	"{balance}", `(?P<balance>`+number+`) ?(?:`+currency+`)?`,
+	"{merchant}", `(?P<merchant>.+?)`,
+	"{card}", `(?P<card>(?:[A-ZА-ЯЁ]+[-*]?|\d?\*)\d{4})`,
+	"{date}", `(?P<date>\d{2}\.\d{2}(?:\.\d{2,4})?)`,
+	"{time}", `(?P<time>\d{1,2}:\d{2})`,
+)
+
+// Compile собирает регулярное выражение шаблона. В pattern можно использовать
+// подстановки {amount}, {currency}, {merchant}, {balance}, {card}, {date}
+// и {time} или собственные группы с теми же именами. Группа amount обязательна.
+// Регистр букв не учитывается, пробелы в тексте заранее схлопываются.
+func Compile(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("(?i)" + placeholders.Replace(pattern))
+	if err != nil {
+		return nil, fmt.Errorf("Неверное регулярное выражение: %w", err)
+	}
+	if re.SubexpIndex("amount") < 0 {
+		return nil, errors.New("В шаблоне нет суммы: добавьте {amount} или группу (?P<amount>...)")
+	}
+	return re, nil
+}
+
+// MustCompile работает как Compile, но паникует при ошибке. Для встроенных правил.
+func MustCompile(pattern string) *regexp.Regexp {
+	re, err := Compile(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return re
+}
+
+// Match применяет шаблон к нормализованному тексту.
+func (t Template) Match(text string, now time.Time) (*Match, bool) {
+	groups := t.Pattern.FindStringSubmatch(text)
+	if groups == nil {
+		return nil, false
+	}
+	// Группа с одним именем может встречаться в разных ветках выражения
+	group := func(name string) string {
+		for i, n := range t.Pattern.SubexpNames() {
+			if n == name && groups[i] != "" {
+				return strings.TrimSpace(groups[i])
+			}
+		}
+		return ""
+	}
+
+	amount, err := money.Parse(group("amount"))
+	if err != nil || amount <= 0 {
+		return nil, false
+	}
+	m := &Match{
+		Type:     t.Type,
+		Amount:   amount,
+		Currency: CurrencyCode(group("currency")),
+		Merchant: strings.Trim(group("merchant"), " .,;:"),
+		Card:     group("card"),
+		Date:     messageDate(group("date"), group("time"), now),
+	}
+	if raw := group("balance"); raw != "" {
+		if balance, err := money.Parse(raw); err == nil {
+			m.Balance = &balance
+		}
+	}
+	return m, true
+}
+
+// CurrencyCode приводит обозначение валюты из уведомления к коду ISO 4217.
+func CurrencyCode(s string) string {
+	s = strings.TrimSuffix(strings.ToLower(s), ".")
+	switch {
+	case s == "":
+		return ""
+	case s == "р" || s == "₽" || s == "rur" || strings.HasPrefix(s, "руб"):
+		return "RUB"
+	case s == "$":
+		return "USD"
+	case s == "€":
+		return "EUR"
+	case s == "¥":
+		return "CNY"
+	}
+	return strings.ToUpper(s)
+}
+
+// messageDate собирает дату операции из даты и времени в тексте. Без даты
+// берётся текущий день, а время из будущего относится к вчерашнему дню:
+// уведомление могли переслать после полуночи.
+func messageDate(date, clock string, now time.Time) time.Time {
+	if date == "" && clock == "" {
+		return time.Time{}
+	}
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if date != "" {
+		var parsed time.Time
+		var err error
+		for _, layout := range []string{"02.01.2006", "02.01.06", "02.01"} {
+			if parsed, err = time.ParseInLocation(layout, date, now.Location()); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return time.Time{}
+		}
+		if parsed.Year() == 0 {
+			parsed = parsed.AddDate(now.Year(), 0, 0)
+		}
+		day = parsed
+	}
+
+	if clock != "" {
+		var hour, minute int
+		if _, err := fmt.Sscanf(clock, "%d:%d", &hour, &minute); err == nil && hour < 24 && minute < 60 {
+			day = day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+		}
+	}
+	if date == "" && day.After(now) {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
@@ -0,0 +1,371 @@
+package banksms
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/httputil"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/Anabol1ks/pers-fin-m/internal/transactions"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	store repository.Store
+}
+
+func NewHandler(store repository.Store) *Handler {
+	return &Handler{store: store}
+}
+
+type ParseInput struct {
+	Text string `json:"text" binding:"required,max=2000"`
+	Bank string `json:"bank" enums:"sber,tbank,alfa,vtb"` // если не указан, банк определяется по тексту
+}
+
+// ParseResult — черновик транзакции по уведомлению. Он не сохраняется:
+// клиент показывает его пользователю и отправляет в POST /transactions.
+type ParseResult struct {
+	Bank        string                        `json:"bank,omitempty"`
+	Template    uint                          `json:"template,omitempty"` // ID пользовательского шаблона
+	Transaction transactions.TransactionInput `json:"transaction"`
+	Balance     *money.Amount                 `json:"balance,omitempty" swaggertype:"number"` // остаток по счёту из уведомления
+	Card        string                        `json:"card,omitempty"`
+}
+
+// @Security BearerAuth
+// ParseNotification godoc
+// @Summary Разобрать SMS или push-уведомление банка
+// @Description Распознаёт текст уведомления пользовательскими шаблонами и правилами банков (sber, tbank, alfa, vtb) и возвращает черновик транзакции: сумму, магазин в названии, тип, валюту и остаток.
+// @Description Счёт подбирается по валюте, категория — по прошлым операциям с тем же названием. Транзакция не создаётся.
+// @Tags Transactions
+// @Accept json
+// @Produce json
+// @Param input body ParseInput true "Текст уведомления"
+// @Success 200 {object} ParseResult
+// @Failure 400 {object} response.ErrorResponse "Текст не распознан"
+// @Failure 500 {object} response.ErrorResponse "Ошибка разбора"
+// @Router /transactions/parse [post]
+func (h *Handler) ParseNotification(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input ParseInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	var (
+		match    *Match
+		template *models.SMSTemplate
+	)
+	// Шаблоны пользователя точнее встроенных правил, но не относятся к конкретному банку
+	if input.Bank == "" {
+		templates, err := h.store.SMSTemplates().ListForUser(userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении шаблонов"})
+			return
+		}
+		text := Normalize(input.Text)
+		for i := range templates {
+			pattern, err := Compile(templates[i].Pattern)
+			if err != nil {
+				continue
+			}
+			if m, ok := (Template{Type: templates[i].Type, Pattern: pattern}).Match(text, now); ok {
+				match, template = m, &templates[i]
+				break
+			}
+		}
+	}
+	if match == nil {
+		var ok bool
+		if match, ok = Parse(input.Text, input.Bank, now); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Не удалось распознать уведомление"})
+			return
+		}
+	}
+
+	result, err := h.draft(userID, match, template, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при подготовке транзакции"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// draft превращает распознанное уведомление в черновик транзакции.
+func (h *Handler) draft(userID uint, m *Match, template *models.SMSTemplate, now time.Time) (*ParseResult, error) {
+	result := &ParseResult{
+		Bank:    m.Bank,
+		Balance: m.Balance,
+		Card:    m.Card,
+		Transaction: transactions.TransactionInput{
+			Amount:   m.Amount,
+			Currency: m.Currency,
+			Date:     m.Date,
+			Title:    m.Merchant,
+			Type:     string(m.Type),
+		},
+	}
+	draft := &result.Transaction
+	if draft.Date.IsZero() {
+		draft.Date = now
+	}
+	if draft.Title == "" {
+		draft.Title = "Списание"
+		if m.Type == models.Income {
+			draft.Title = "Поступление"
+		}
+	}
+
+	if template != nil {
+		result.Template = template.ID
+		if template.Category != nil {
+			draft.Category = *template.Category
+		}
+		if template.Account != nil {
+			if account, err := h.store.Accounts().GetOwned(*template.Account, userID); err == nil && !account.Archived {
+				draft.Account = account.ID
+			}
+		}
+	}
+
+	if draft.Account == 0 {
+		account, err := h.accountFor(userID, draft.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if account != nil {
+			draft.Account = account.ID
+			if draft.Currency == "" {
+				draft.Currency = account.Currency
+			}
+		}
+	}
+
+	if draft.Category == 0 && m.Merchant != "" {
+		page, err := h.store.Transactions().List(userID,
+			repository.TransactionFilter{Title: &m.Merchant, Types: []string{string(m.Type)}},
+			repository.ListOptions{Sort: repository.SortByDate, Desc: true, Limit: 1, SkipTotals: true})
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Items) > 0 {
+			draft.Category = page.Items[0].Category
+		}
+	}
+	return result, nil
+}
+
+// accountFor подбирает счёт по валюте: основной, если валюта совпадает или
+// не указана, иначе первый неархивный счёт в этой валюте.
+func (h *Handler) accountFor(userID uint, currency string) (*models.Account, error) {
+	primary, err := h.store.Accounts().Primary(userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" || primary.Currency == currency {
+		return primary, nil
+	}
+
+	accounts, err := h.store.Accounts().ListForUser(userID, false)
+	if err != nil {
+		return nil, err
+	}
+	for i := range accounts {
+		if accounts[i].Currency == currency {
+			return &accounts[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// TemplateInput — пользовательский шаблон уведомления.
+type TemplateInput struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Pattern  string `json:"pattern" binding:"required,max=1000"` // например: Списание {amount}{currency} {merchant} Остаток {balance}
+	Type     string `json:"type" binding:"required,oneof=income expense"`
+	Category *uint  `json:"category"`
+	Account  *uint  `json:"account"`
+	Sample   string `json:"sample"` // пример уведомления, который шаблон должен распознать
+}
+
+// template проверяет шаблон и превращает его в модель. Вторым значением возвращается текст ошибки.
+func (h *Handler) template(userID uint, in TemplateInput) (*models.SMSTemplate, string) {
+	pattern, err := Compile(in.Pattern)
+	if err != nil {
+		return nil, err.Error()
+	}
+	if in.Sample != "" {
+		if _, ok := (Template{Pattern: pattern}).Match(Normalize(in.Sample), time.Now()); !ok {
+			return nil, "Шаблон не распознаёт пример уведомления"
+		}
+	}
+	if in.Category != nil {
+		if _, err := h.store.Categories().GetAvailable(*in.Category, userID); err != nil {
+			return nil, "Указана неверная категория"
+		}
+	}
+	if in.Account != nil {
+		if _, err := h.store.Accounts().GetOwned(*in.Account, userID); err != nil {
+			return nil, "Указан неверный счёт"
+		}
+	}
+
+	return &models.SMSTemplate{
+		UserID:   userID,
+		Name:     in.Name,
+		Pattern:  in.Pattern,
+		Type:     models.TransactionType(in.Type),
+		Category: in.Category,
+		Account:  in.Account,
+	}, ""
+}
+
+// @Security BearerAuth
+// ListTemplates godoc
+// @Summary Получить шаблоны уведомлений
+// @Description Получить собственные шаблоны разбора SMS и push-уведомлений банков
+// @Tags Transactions
+// @Produce json
+// @Success 200 {array} models.SMSTemplate
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении шаблонов"
+// @Router /transactions/parse/templates [get]
+func (h *Handler) ListTemplates(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	templates, err := h.store.SMSTemplates().ListForUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении шаблонов"})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// @Security BearerAuth
+// CreateTemplate godoc
+// @Summary Создать шаблон уведомления
+// @Description Шаблон — регулярное выражение с подстановками {amount}, {currency}, {merchant}, {balance}, {card}, {date}, {time} или группами (?P<amount>...) с теми же именами. Сумма обязательна, регистр не учитывается.
+// @Tags Transactions
+// @Accept json
+// @Produce json
+// @Param input body TemplateInput true "Шаблон"
+// @Success 201 {object} models.SMSTemplate
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 500 {object} response.ErrorResponse "Ошибка сохранения шаблона"
+// @Router /transactions/parse/templates [post]
+func (h *Handler) CreateTemplate(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input TemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, errMsg := h.template(userID, input)
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	if err := h.store.SMSTemplates().Create(template); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении шаблона"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// @Security BearerAuth
+// UpdateTemplate godoc
+// @Summary Обновить шаблон уведомления
+// @Description Заменяет шаблон целиком
+// @Tags Transactions
+// @Accept json
+// @Produce json
+// @Param id path string true "ID шаблона"
+// @Param input body TemplateInput true "Шаблон"
+// @Success 200 {object} models.SMSTemplate
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 404 {object} response.ErrorResponse "Шаблон не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка сохранения шаблона"
+// @Router /transactions/parse/templates/{id} [put]
+func (h *Handler) UpdateTemplate(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input TemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	existing, ok := h.ownedTemplate(c)
+	if !ok {
+		return
+	}
+
+	template, errMsg := h.template(userID, input)
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+	template.Model = existing.Model
+
+	if err := h.store.SMSTemplates().Save(template); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении шаблона"})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// @Security BearerAuth
+// DeleteTemplate godoc
+// @Summary Удалить шаблон уведомления
+// @Tags Transactions
+// @Produce json
+// @Param id path string true "ID шаблона"
+// @Success 200 {object} response.SuccessResponse "Шаблон удалён"
+// @Failure 404 {object} response.ErrorResponse "Шаблон не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка удаления шаблона"
+// @Router /transactions/parse/templates/{id} [delete]
+func (h *Handler) DeleteTemplate(c *gin.Context) {
+	template, ok := h.ownedTemplate(c)
+	if !ok {
+		return
+	}
+
+	if err := h.store.SMSTemplates().Delete(template); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении шаблона"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Шаблон удалён"})
+}
+
+func (h *Handler) ownedTemplate(c *gin.Context) (*models.SMSTemplate, bool) {
+	userID := c.GetUint("userID")
+
+	templateID, err := httputil.ParamID(c, "id")
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Шаблон не найден"})
+		return nil, false
+	}
+
+	template, err := h.store.SMSTemplates().GetOwned(templateID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Шаблон не найден"})
+		return nil, false
+	}
+	return template, true
+}
@@ -0,0 +1,143 @@
+package budgets
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	email "github.com/Anabol1ks/pers-fin-m/internal/emails"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+// Thresholds — пороги расходования бюджета в процентах, о которых
+// пользователь получает письмо, по возрастанию.
+var Thresholds = []int{80, 100}
+
+// Notify в фоне проверяет пороги бюджетов после сохранения расходов
+// пользователя, чтобы отправка письма не задерживала ответ. Ошибки
+// только пишутся в лог.
+func Notify(store repository.Store, userID uint, transactions ...models.Transaction) {
+	if len(transactions) == 0 {
+		return
+	}
+	go func() {
+		if err := CheckAlerts(store, userID, transactions, time.Now()); err != nil {
+			log.Println("Уведомления о бюджетах:", err)
+		}
+	}()
+}
+
+// CheckAlerts проверяет бюджеты, в которые попадают расходы пользователя
+// из transactions, и отправляет письмо о достигнутом пороге. Каждый бюджет
+// проверяется один раз на всю пачку. Учитывается только текущий период
+// бюджета, каждый порог отправляется один раз за период. Если сразу пройдено
+// несколько порогов, письмо приходит только о старшем. Письма получают
+// пользователи с подтверждённой почтой, не отключившие уведомления.
+func CheckAlerts(store repository.Store, userID uint, transactions []models.Transaction, now time.Time) error {
+	var expenses []models.Transaction
+	for _, t := range transactions {
+		if t.Type == models.Expense && t.UserID == userID {
+			expenses = append(expenses, t)
+		}
+	}
+	if len(expenses) == 0 {
+		return nil
+	}
+	user, err := store.Users().GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.Verified || !user.BudgetAlerts {
+		return nil
+	}
+
+	budgets, err := store.Budgets().ListForUser(userID)
+	if err != nil {
+		return err
+	}
+	for i := range budgets {
+		budget := &budgets[i]
+		affected := slices.ContainsFunc(expenses, func(t models.Transaction) bool {
+			return affects(budget, t, now)
+		})
+		if !affected {
+			continue
+		}
+		if err := checkBudget(store, user, budget, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// affects сообщает, попадает ли расход t в текущий период бюджета.
+func affects(budget *models.Budget, t models.Transaction, now time.Time) bool {
+	if budget.Currency != t.Currency || (len(budget.Categories) > 0 && !budget.Categories.Contains(t.Category)) {
+		return false
+	}
+	return PeriodStart(budget.Period, t.Date.In(now.Location())).Equal(PeriodStart(budget.Period, now))
+}
+
+func checkBudget(store repository.Store, user *models.User, budget *models.Budget, now time.Time) error {
+	progress, err := Compute(store, budget, now, now)
+	if err != nil {
+		return err
+	}
+
+	// Запоминаем все пройденные пороги, чтобы младшие не пришли позже старшего
+	var recorded []int
+	for _, threshold := range Thresholds {
+		if progress.Percent < float64(threshold) {
+			break
+		}
+		created, err := store.Budgets().RecordAlert(budget.ID, progress.PeriodStart, threshold)
+		if err != nil {
+			return err
+		}
+		if created {
+			recorded = append(recorded, threshold)
+		}
+	}
+	if len(recorded) == 0 {
+		return nil
+	}
+
+	threshold := recorded[len(recorded)-1]
+	err = email.SendBudgetAlert(user.Email, email.BudgetAlertData{
+		Username:  user.Username,
+		Budget:    budget.Name,
+		Threshold: threshold,
+		Over:      threshold >= 100,
+		Period:    periodName(budget.Period, progress.PeriodStart),
+		Spent:     progress.Spent.String(),
+		Available: progress.Available.String(),
+		Remaining: progress.Remaining.Abs().String(),
+		Currency:  budget.Currency,
+	})
+	if err != nil {
+		// Письмо не ушло: пороги будут проверены снова при следующем расходе
+		for _, threshold := range recorded {
+			if err := store.Budgets().DeleteAlert(budget.ID, progress.PeriodStart, threshold); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+var monthNames = []string{"январь", "февраль", "март", "апрель", "май", "июнь", "июль", "август", "сентябрь", "октябрь", "ноябрь", "декабрь"}
+
+// periodName возвращает название периода для письма: «октябрь 2026», «2026 год»
+// или «неделю с 12.10.2026».
+func periodName(frequency models.Frequency, start time.Time) string {
+	switch frequency {
+	case models.Weekly:
+		return "неделю с " + start.Format("02.01.2006")
+	case models.Yearly:
+		return fmt.Sprintf("%d год", start.Year())
+	}
+	return fmt.Sprintf("%s %d", monthNames[start.Month()-1], start.Year())
+}
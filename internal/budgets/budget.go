@@ -0,0 +1,133 @@
+// Package budgets ведёт бюджеты расходов по категориям и считает их
+// исполнение: сколько потрачено за период, сколько осталось и сколько
+// будет потрачено к концу периода при текущем темпе.
+package budgets
+
+import (
+	"math"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/period"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+// Progress — исполнение бюджета за один период.
+type Progress struct {
+	Budget      uint         `json:"budget"`
+	Name        string       `json:"name"`
+	Currency    string       `json:"currency"`
+	PeriodStart time.Time    `json:"periodStart"`
+	PeriodEnd   time.Time    `json:"periodEnd"` // не включительно
+	Limit       money.Amount `json:"limit" swaggertype:"number"`
+	Carried     money.Amount `json:"carried" swaggertype:"number"`   // остаток прошлых периодов, при перерасходе отрицательный
+	Available   money.Amount `json:"available" swaggertype:"number"` // лимит вместе с переносом
+	Spent       money.Amount `json:"spent" swaggertype:"number"`
+	Remaining   money.Amount `json:"remaining" swaggertype:"number"`
+	Projected   money.Amount `json:"projected" swaggertype:"number"` // прогноз расходов к концу периода
+	Percent     float64      `json:"percent"`                        // доля потраченного от доступного, %
+	Over        bool         `json:"over"`                           // потрачено больше доступного
+	// ProjectedOver — при текущем темпе к концу периода доступное будет превышено.
+	ProjectedOver bool `json:"projectedOver"`
+}
+
+// PeriodStart возвращает начало периода бюджета, в который попадает t.
+func PeriodStart(frequency models.Frequency, t time.Time) time.Time {
+	switch frequency {
+	case models.Weekly:
+		return period.StartOfWeek(t)
+	case models.Yearly:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	}
+	return period.StartOfMonth(t)
+}
+
+// nextPeriod возвращает начало периода, следующего за периодом с началом start.
+func nextPeriod(frequency models.Frequency, start time.Time) time.Time {
+	switch frequency {
+	case models.Weekly:
+		return start.AddDate(0, 0, 7)
+	case models.Yearly:
+		return start.AddDate(1, 0, 0)
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+// Compute считает исполнение бюджета за период, в который попадает at.
+// Прогноз строится по темпу расходов от начала периода до now.
+// При переносе остатка учитываются все периоды с начала бюджета.
+func Compute(store repository.Store, budget *models.Budget, at, now time.Time) (*Progress, error) {
+	start := PeriodStart(budget.Period, at)
+	end := nextPeriod(budget.Period, start)
+
+	// Начала периодов от первого учитываемого до текущего
+	first := start
+	if budget.Rollover {
+		if budgetStart := PeriodStart(budget.Period, budget.Start.In(at.Location())); budgetStart.Before(start) {
+			first = budgetStart
+		}
+	}
+	var starts []time.Time
+	for s := first; s.Before(end); s = nextPeriod(budget.Period, s) {
+		starts = append(starts, s)
+	}
+
+	hits, err := store.Transactions().Search(budget.UserID, repository.TransactionFilter{
+		DateFrom:   &first,
+		DateTo:     &end,
+		Categories: budget.Categories,
+		Currencies: []string{budget.Currency},
+		Types:      []string{string(models.Expense)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	spent := make([]money.Amount, len(starts))
+	for _, hit := range hits {
+		i := len(starts) - 1
+		for i > 0 && hit.Date.Before(starts[i]) {
+			i--
+		}
+		spent[i] += hit.Amount
+	}
+
+	progress := &Progress{
+		Budget:      budget.ID,
+		Name:        budget.Name,
+		Currency:    budget.Currency,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Limit:       budget.Limit,
+		Spent:       spent[len(spent)-1],
+	}
+	for _, s := range spent[:len(spent)-1] {
+		progress.Carried += budget.Limit - s
+	}
+	progress.Available = progress.Limit + progress.Carried
+	progress.Remaining = progress.Available - progress.Spent
+	progress.Over = progress.Spent > progress.Available
+
+	progress.Projected = progress.Spent
+	if !now.Before(start) && now.Before(end) {
+		// В первый день периода темп считается хотя бы за сутки, иначе прогноз по первой покупке завышен
+		elapsed := now.Sub(start)
+		if elapsed < 24*time.Hour {
+			elapsed = 24 * time.Hour
+		}
+		projected, err := progress.Spent.MulRatio(int64(end.Sub(start)/time.Minute), int64(elapsed/time.Minute))
+		if err != nil {
+			return nil, err
+		}
+		progress.Projected = projected
+	}
+	progress.ProjectedOver = progress.Projected > progress.Available
+
+	switch {
+	case progress.Available > 0:
+		progress.Percent = math.Round(float64(progress.Spent)/float64(progress.Available)*1000) / 10
+	case progress.Spent > 0:
+		progress.Percent = 100
+	}
+	return progress, nil
+}
@@ -0,0 +1,166 @@
+package budgets
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository/memory"
+)
+
+func TestPeriodStart(t *testing.T) {
+	at := time.Date(2024, 2, 15, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		frequency models.Frequency
+		want      time.Time
+	}{
+		{models.Weekly, time.Date(2024, 2, 12, 0, 0, 0, 0, time.UTC)},
+		{models.Monthly, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{models.Yearly, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := PeriodStart(tt.frequency, at); !got.Equal(tt.want) {
+			t.Errorf("PeriodStart(%s, %v) = %v, want %v", tt.frequency, at, got, tt.want)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	day := func(m time.Month, d, hh int) time.Time {
+		return time.Date(2024, m, d, hh, 0, 0, 0, time.UTC)
+	}
+
+	store := memory.NewStore()
+	for _, tr := range []models.Transaction{
+		{UserID: 1, Type: models.Expense, Amount: 30000, Currency: "RUB", Category: 1, Date: day(1, 10, 10)},
+		{UserID: 1, Type: models.Expense, Amount: 40000, Currency: "RUB", Category: 1, Date: day(2, 5, 10)},
+		{UserID: 1, Type: models.Expense, Amount: 10000, Currency: "RUB", Category: 2, Date: day(2, 10, 10)},
+		{UserID: 1, Type: models.Expense, Amount: 5000, Currency: "USD", Category: 1, Date: day(2, 11, 10)},
+		{UserID: 1, Type: models.Income, Amount: 50000, Currency: "RUB", Category: 1, Date: day(2, 12, 10)},
+		{UserID: 2, Type: models.Expense, Amount: 70000, Currency: "RUB", Category: 1, Date: day(2, 6, 10)},
+		{UserID: 1, Type: models.Expense, Amount: 20000, Currency: "RUB", Category: 1, Date: day(3, 1, 0)},
+	} {
+		if err := store.Transactions().Create(&tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	monthly := models.Budget{
+		UserID:     1,
+		Name:       "Продукты",
+		Categories: models.IDList{1},
+		Period:     models.Monthly,
+		Limit:      100000,
+		Currency:   "RUB",
+		Start:      day(1, 1, 0),
+	}
+	february := func(p Progress) *Progress {
+		p.Name, p.Currency = "Продукты", "RUB"
+		p.PeriodStart, p.PeriodEnd = day(2, 1, 0), day(3, 1, 0)
+		return &p
+	}
+
+	tests := []struct {
+		name    string
+		budget  func(b *models.Budget)
+		at, now time.Time
+		want    *Progress
+	}{
+		{
+			name: "середина месяца",
+			at:   day(2, 15, 12),
+			now:  day(2, 15, 12),
+			want: february(Progress{Limit: 100000, Available: 100000, Spent: 40000, Remaining: 60000, Projected: 80000, Percent: 40}),
+		},
+		{
+			name:   "несколько категорий",
+			budget: func(b *models.Budget) { b.Categories = models.IDList{1, 2} },
+			at:     day(2, 15, 12),
+			now:    day(2, 15, 12),
+			want:   february(Progress{Limit: 100000, Available: 100000, Spent: 50000, Remaining: 50000, Projected: 100000, Percent: 50}),
+		},
+		{
+			name:   "перенос остатка",
+			budget: func(b *models.Budget) { b.Rollover = true },
+			at:     day(2, 15, 12),
+			now:    day(2, 15, 12),
+			want:   february(Progress{Limit: 100000, Carried: 70000, Available: 170000, Spent: 40000, Remaining: 130000, Projected: 80000, Percent: 23.5}),
+		},
+		{
+			name:   "перенос перерасхода",
+			budget: func(b *models.Budget) { b.Rollover, b.Limit = true, 25000 },
+			at:     day(2, 15, 12),
+			now:    day(2, 15, 12),
+			want:   february(Progress{Limit: 25000, Carried: -5000, Available: 20000, Spent: 40000, Remaining: -20000, Projected: 80000, Percent: 200, Over: true, ProjectedOver: true}),
+		},
+		{
+			name:   "перенос с начала бюджета",
+			budget: func(b *models.Budget) { b.Rollover, b.Start = true, day(2, 10, 0) },
+			at:     day(2, 15, 12),
+			now:    day(2, 15, 12),
+			want:   february(Progress{Limit: 100000, Available: 100000, Spent: 40000, Remaining: 60000, Projected: 80000, Percent: 40}),
+		},
+		{
+			name:   "нулевой лимит",
+			budget: func(b *models.Budget) { b.Limit = 0 },
+			at:     day(2, 15, 12),
+			now:    day(3, 10, 0),
+			want:   february(Progress{Spent: 40000, Remaining: -40000, Projected: 40000, Percent: 100, Over: true, ProjectedOver: true}),
+		},
+		{
+			name: "прошедший период без прогноза",
+			at:   day(1, 20, 0),
+			now:  day(2, 15, 12),
+			want: &Progress{Name: "Продукты", Currency: "RUB", PeriodStart: day(1, 1, 0), PeriodEnd: day(2, 1, 0), Limit: 100000, Available: 100000, Spent: 30000, Remaining: 70000, Projected: 30000, Percent: 30},
+		},
+		{
+			name: "первый день: темп не меньше чем за сутки",
+			at:   day(3, 1, 6),
+			now:  day(3, 1, 6),
+			want: &Progress{Name: "Продукты", Currency: "RUB", PeriodStart: day(3, 1, 0), PeriodEnd: day(4, 1, 0), Limit: 100000, Available: 100000, Spent: 20000, Remaining: 80000, Projected: 620000, Percent: 20, ProjectedOver: true},
+		},
+		{
+			name:   "неделя",
+			budget: func(b *models.Budget) { b.Period = models.Weekly },
+			at:     day(2, 7, 0),
+			now:    day(2, 7, 0),
+			want:   &Progress{Name: "Продукты", Currency: "RUB", PeriodStart: day(2, 5, 0), PeriodEnd: day(2, 12, 0), Limit: 100000, Available: 100000, Spent: 40000, Remaining: 60000, Projected: 140000, Percent: 40, ProjectedOver: true},
+		},
+		{
+			name:   "другая валюта",
+			budget: func(b *models.Budget) { b.Currency = "USD" },
+			at:     day(2, 15, 12),
+			now:    day(3, 10, 0),
+			want:   &Progress{Name: "Продукты", Currency: "USD", PeriodStart: day(2, 1, 0), PeriodEnd: day(3, 1, 0), Limit: 100000, Available: 100000, Spent: 5000, Remaining: 95000, Projected: 5000, Percent: 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := monthly
+			if tt.budget != nil {
+				tt.budget(&budget)
+			}
+			got, err := Compute(store, &budget, tt.at, tt.now)
+			if err != nil {
+				t.Fatalf("Compute() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compute() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeOverflow(t *testing.T) {
+	store := memory.NewStore()
+	at := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	if err := store.Transactions().Create(&models.Transaction{UserID: 1, Type: models.Expense, Amount: money.Amount(1 << 60), Currency: "RUB", Category: 1, Date: at}); err != nil {
+		t.Fatal(err)
+	}
+	budget := &models.Budget{UserID: 1, Categories: models.IDList{1}, Period: models.Yearly, Limit: 100000, Currency: "RUB"}
+	if got, err := Compute(store, budget, at, at); err == nil {
+		t.Errorf("Compute() = %+v, want error", got)
+	}
+}
@@ -0,0 +1,314 @@
+package budgets
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/httputil"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	store repository.Store
+}
+
+func NewHandler(store repository.Store) *Handler {
+	return &Handler{store: store}
+}
+
+type BudgetInput struct {
+	Name       string       `json:"name" binding:"required,max=100"`
+	Categories []uint       `json:"categories"`                                             // пустой список — все расходы
+	Period     string       `json:"period" binding:"omitempty,oneof=weekly monthly yearly"` // по умолчанию monthly
+	Limit      money.Amount `json:"limit" binding:"required" swaggertype:"number"`
+	Currency   string       `json:"currency" binding:"omitempty,len=3,alpha"` // по умолчанию валюта основного счёта
+	Rollover   bool         `json:"rollover"`
+	Start      *time.Time   `json:"start"` // с какого периода действует бюджет, по умолчанию с текущего
+}
+
+// @Security BearerAuth
+// ListBudgets godoc
+// @Summary Список бюджетов
+// @Tags Budgets
+// @Produce json
+// @Success 200 {array} models.Budget
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении бюджетов"
+// @Router /budgets [get]
+func (h *Handler) ListBudgets(c *gin.Context) {
+	budgets, err := h.store.Budgets().ListForUser(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении бюджетов"})
+		return
+	}
+	c.JSON(http.StatusOK, budgets)
+}
+
+// @Security BearerAuth
+// GetBudget godoc
+// @Summary Получить бюджет
+// @Tags Budgets
+// @Produce json
+// @Param id path string true "ID бюджета"
+// @Success 200 {object} models.Budget
+// @Failure 404 {object} response.ErrorResponse "Бюджет не найден"
+// @Router /budgets/{id} [get]
+func (h *Handler) GetBudget(c *gin.Context) {
+	budget, ok := h.ownedBudget(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, budget)
+}
+
+// @Security BearerAuth
+// CreateBudget godoc
+// @Summary Создать бюджет
+// @Description Создаёт лимит расходов на неделю, месяц или год по категории или группе категорий.
+// @Description Без категорий бюджет охватывает все расходы. Учитываются только расходы в валюте бюджета.
+// @Description При rollover=true остаток периода, в том числе отрицательный, переносится на следующий.
+// @Description При расходовании 80% и 100% доступной суммы пользователь получает письмо, если не отключил уведомления.
+// @Tags Budgets
+// @Accept json
+// @Produce json
+// @Param input body BudgetInput true "Бюджет"
+// @Success 201 {object} models.Budget
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 500 {object} response.ErrorResponse "Ошибка сохранения бюджета"
+// @Router /budgets [post]
+func (h *Handler) CreateBudget(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input BudgetInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	budget, errMsg := h.budget(userID, input)
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+	if input.Start == nil {
+		budget.Start = PeriodStart(budget.Period, time.Now())
+	}
+
+	if err := h.store.Budgets().Create(budget); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении бюджета"})
+		return
+	}
+	c.JSON(http.StatusCreated, budget)
+}
+
+// @Security BearerAuth
+// UpdateBudget godoc
+// @Summary Изменить бюджет
+// @Description Заменяет бюджет целиком. Если start не указан, сохраняется прежний.
+// @Tags Budgets
+// @Accept json
+// @Produce json
+// @Param id path string true "ID бюджета"
+// @Param input body BudgetInput true "Бюджет"
+// @Success 200 {object} models.Budget
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 404 {object} response.ErrorResponse "Бюджет не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка сохранения бюджета"
+// @Router /budgets/{id} [put]
+func (h *Handler) UpdateBudget(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input BudgetInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	existing, ok := h.ownedBudget(c)
+	if !ok {
+		return
+	}
+
+	budget, errMsg := h.budget(userID, input)
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+	budget.Model = existing.Model
+	if input.Start == nil {
+		budget.Start = PeriodStart(budget.Period, existing.Start)
+	}
+
+	if err := h.store.Budgets().Save(budget); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении бюджета"})
+		return
+	}
+	c.JSON(http.StatusOK, budget)
+}
+
+// @Security BearerAuth
+// DeleteBudget godoc
+// @Summary Удалить бюджет
+// @Tags Budgets
+// @Produce json
+// @Param id path string true "ID бюджета"
+// @Success 200 {object} response.SuccessResponse "Бюджет удалён"
+// @Failure 404 {object} response.ErrorResponse "Бюджет не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка удаления бюджета"
+// @Router /budgets/{id} [delete]
+func (h *Handler) DeleteBudget(c *gin.Context) {
+	budget, ok := h.ownedBudget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.store.Budgets().Delete(budget); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении бюджета"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Бюджет удалён"})
+}
+
+// @Security BearerAuth
+// GetProgress godoc
+// @Summary Исполнение бюджета
+// @Description Возвращает расходы за период бюджета, остаток и прогноз расходов к концу периода при текущем темпе.
+// @Description По умолчанию — текущий период, параметр date выбирает период, в который попадает дата.
+// @Tags Budgets
+// @Produce json
+// @Param id path string true "ID бюджета"
+// @Param date query string false "Дата внутри периода, YYYY-MM-DD"
+// @Success 200 {object} Progress
+// @Failure 400 {object} response.ErrorResponse "Неверная дата"
+// @Failure 404 {object} response.ErrorResponse "Бюджет не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при расчёте бюджета"
+// @Router /budgets/{id}/progress [get]
+func (h *Handler) GetProgress(c *gin.Context) {
+	at, ok := progressDate(c)
+	if !ok {
+		return
+	}
+	budget, ok := h.ownedBudget(c)
+	if !ok {
+		return
+	}
+
+	progress, err := Compute(h.store, budget, at, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при расчёте бюджета"})
+		return
+	}
+	c.JSON(http.StatusOK, progress)
+}
+
+// @Security BearerAuth
+// ListProgress godoc
+// @Summary Исполнение всех бюджетов
+// @Description Возвращает исполнение каждого бюджета пользователя за текущий период или период, в который попадает date.
+// @Tags Budgets
+// @Produce json
+// @Param date query string false "Дата внутри периода, YYYY-MM-DD"
+// @Success 200 {array} Progress
+// @Failure 400 {object} response.ErrorResponse "Неверная дата"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при расчёте бюджетов"
+// @Router /budgets/progress [get]
+func (h *Handler) ListProgress(c *gin.Context) {
+	at, ok := progressDate(c)
+	if !ok {
+		return
+	}
+
+	budgets, err := h.store.Budgets().ListForUser(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении бюджетов"})
+		return
+	}
+
+	now := time.Now()
+	result := make([]Progress, 0, len(budgets))
+	for i := range budgets {
+		progress, err := Compute(h.store, &budgets[i], at, now)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при расчёте бюджетов"})
+			return
+		}
+		result = append(result, *progress)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// budget проверяет ввод и собирает бюджет. Вторым значением возвращается текст ошибки.
+func (h *Handler) budget(userID uint, input BudgetInput) (*models.Budget, string) {
+	if input.Limit <= 0 {
+		return nil, "Лимит должен быть больше 0"
+	}
+	if input.Period == "" {
+		input.Period = string(models.Monthly)
+	}
+
+	categories := models.IDList{}
+	for _, id := range input.Categories {
+		if categories.Contains(id) {
+			continue
+		}
+		if _, err := h.store.Categories().GetAvailable(id, userID); err != nil {
+			return nil, "Указана неверная категория"
+		}
+		categories = append(categories, id)
+	}
+
+	currency := strings.ToUpper(input.Currency)
+	if currency == "" {
+		currency = "RUB"
+		if account, err := h.store.Accounts().Primary(userID); err == nil {
+			currency = account.Currency
+		}
+	}
+
+	budget := &models.Budget{
+		UserID:     userID,
+		Name:       input.Name,
+		Categories: categories,
+		Period:     models.Frequency(input.Period),
+		Limit:      input.Limit,
+		Currency:   currency,
+		Rollover:   input.Rollover,
+	}
+	if input.Start != nil {
+		budget.Start = PeriodStart(budget.Period, *input.Start)
+	}
+	return budget, ""
+}
+
+func (h *Handler) ownedBudget(c *gin.Context) (*models.Budget, bool) {
+	userID := c.GetUint("userID")
+
+	budgetID, err := httputil.ParamID(c, "id")
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Бюджет не найден"})
+		return nil, false
+	}
+	budget, err := h.store.Budgets().GetOwned(budgetID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Бюджет не найден"})
+		return nil, false
+	}
+	return budget, true
+}
+
+// progressDate читает дату периода из параметра date, по умолчанию текущий момент.
+func progressDate(c *gin.Context) (time.Time, bool) {
+	raw := c.Query("date")
+	if raw == "" {
+		return time.Now(), true
+	}
+	date, err := time.ParseInLocation("2006-01-02", raw, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверная дата, ожидается формат YYYY-MM-DD"})
+		return time.Time{}, false
+	}
+	return date, true
+}
@@ -0,0 +1,131 @@
+package сategory
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/httputil"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// @Security BearerAuth
+// ListDefaultCategories godoc
+// @Summary Категории по умолчанию
+// @Description Список всех категорий по умолчанию, включая выведенные из оборота. Только для администраторов
+// @Tags Admin
+// @Produce json
+// @Success 200 {array} models.Category
+// @Failure 403 {object} response.ErrorResponse "Недостаточно прав"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении категорий"
+// @Router /admin/categories [get]
+func (h *Handler) ListDefaultCategories(c *gin.Context) {
+	categories, err := h.store.Categories().ListDefaults()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении категорий"})
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+// @Security BearerAuth
+// CreateDefaultCategory godoc
+// @Summary Добавить категорию по умолчанию
+// @Description Добавляет категорию, доступную всем пользователям. Только для администраторов
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param input body DefaultCategory true "Категория по умолчанию"
+// @Success 201 {object} models.Category
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} response.ErrorResponse "Недостаточно прав"
+// @Failure 409 {object} response.ErrorResponse "Категория с таким кодом уже существует"
+// @Failure 500 {object} response.ErrorResponse "Ошибка создания категории"
+// @Router /admin/categories [post]
+func (h *Handler) CreateDefaultCategory(c *gin.Context) {
+	var input DefaultCategory
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	_, err := h.store.Categories().FindDefaultBySlug(input.Slug)
+	if err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Категория с таким кодом уже существует"})
+		return
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании категории"})
+		return
+	}
+
+	category := newDefaultCategory(input)
+	if err := h.store.Categories().Create(&category); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании категории"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// @Security BearerAuth
+// RetireDefaultCategory godoc
+// @Summary Вывести категорию по умолчанию из оборота
+// @Description Скрывает категорию по умолчанию для новых транзакций. Существующие транзакции сохраняют категорию. Только для администраторов
+// @Tags Admin
+// @Produce json
+// @Param id path string true "ID категории"
+// @Success 200 {object} models.Category
+// @Failure 400 {object} response.ErrorResponse "Категорию нельзя вывести из оборота"
+// @Failure 403 {object} response.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} response.ErrorResponse "Категория не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка обновления категории"
+// @Router /admin/categories/{id} [delete]
+func (h *Handler) RetireDefaultCategory(c *gin.Context) {
+	h.setRetired(c, true)
+}
+
+// @Security BearerAuth
+// RestoreDefaultCategory godoc
+// @Summary Вернуть категорию по умолчанию
+// @Description Возвращает ранее выведенную из оборота категорию по умолчанию. Только для администраторов
+// @Tags Admin
+// @Produce json
+// @Param id path string true "ID категории"
+// @Success 200 {object} models.Category
+// @Failure 403 {object} response.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} response.ErrorResponse "Категория не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка обновления категории"
+// @Router /admin/categories/{id}/restore [post]
+func (h *Handler) RestoreDefaultCategory(c *gin.Context) {
+	h.setRetired(c, false)
+}
+
+func (h *Handler) setRetired(c *gin.Context, retired bool) {
+	categoryID, err := httputil.ParamID(c, "id")
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Категория не найдена"})
+		return
+	}
+
+	category, err := h.store.Categories().GetDefault(categoryID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Категория не найдена"})
+		return
+	}
+
+	if retired && category.Slug != nil && *category.Slug == models.UncategorizedSlug {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Категорию «Без категории» нельзя вывести из оборота"})
+		return
+	}
+
+	category.Retired = retired
+	if err := h.store.Categories().Save(category); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении категории"})
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
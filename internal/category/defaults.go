@@ -0,0 +1,124 @@
+package сategory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+// DefaultCategory описывает встроенную категорию, доступную всем пользователям.
+type DefaultCategory struct {
+	Slug         string            `json:"slug" binding:"required"`
+	Name         string            `json:"name" binding:"required"`
+	Color        string            `json:"color"`
+	Translations map[string]string `json:"translations"`
+}
+
+// BuiltinDefaults используются, если DEFAULT_CATEGORIES_FILE не задан.
+var BuiltinDefaults = []DefaultCategory{
+	{Slug: models.UncategorizedSlug, Name: "Без категории", Color: "#71717a", Translations: map[string]string{"en": "Uncategorized"}},
+	{Slug: "groceries", Name: "Продукты", Color: "#16a34a", Translations: map[string]string{"en": "Groceries"}},
+	{Slug: "restaurants", Name: "Кафе и рестораны", Color: "#ea580c", Translations: map[string]string{"en": "Restaurants"}},
+	{Slug: "transport", Name: "Транспорт", Color: "#2563eb", Translations: map[string]string{"en": "Transport"}},
+	{Slug: "housing", Name: "Жильё", Color: "#7c3aed", Translations: map[string]string{"en": "Housing"}},
+	{Slug: "utilities", Name: "Коммунальные услуги", Color: "#0891b2", Translations: map[string]string{"en": "Utilities"}},
+	{Slug: "communication", Name: "Связь и интернет", Color: "#4f46e5", Translations: map[string]string{"en": "Phone and internet"}},
+	{Slug: "health", Name: "Здоровье", Color: "#dc2626", Translations: map[string]string{"en": "Health"}},
+	{Slug: "clothing", Name: "Одежда", Color: "#db2777", Translations: map[string]string{"en": "Clothing"}},
+	{Slug: "entertainment", Name: "Развлечения", Color: "#ca8a04", Translations: map[string]string{"en": "Entertainment"}},
+	{Slug: "education", Name: "Образование", Color: "#0d9488", Translations: map[string]string{"en": "Education"}},
+	{Slug: "gifts", Name: "Подарки", Color: "#e11d48", Translations: map[string]string{"en": "Gifts"}},
+	{Slug: "salary", Name: "Зарплата", Color: "#15803d", Translations: map[string]string{"en": "Salary"}},
+	{Slug: "other_income", Name: "Прочие доходы", Color: "#65a30d", Translations: map[string]string{"en": "Other income"}},
+}
+
+// LoadDefaults читает список категорий по умолчанию из JSON-файла.
+// При пустом пути возвращается BuiltinDefaults.
+func LoadDefaults(path string) ([]DefaultCategory, error) {
+	if path == "" {
+		return BuiltinDefaults, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var defaults []DefaultCategory
+	if err := json.Unmarshal(data, &defaults); err != nil {
+		return nil, fmt.Errorf("неверный формат %s: %w", path, err)
+	}
+	for _, d := range defaults {
+		if d.Slug == "" || d.Name == "" {
+			return nil, fmt.Errorf("в %s у категории не указан slug или name", path)
+		}
+	}
+	return defaults, nil
+}
+
+// Seed создаёт недостающие категории по умолчанию и возвращает количество созданных.
+// Существующие категории (в том числе выведенные из оборота) не изменяются,
+// поэтому повторный запуск безопасен. Категория «Без категории» создаётся всегда.
+func Seed(store repository.Store, defaults []DefaultCategory) (int, error) {
+	hasUncategorized := false
+	for _, d := range defaults {
+		if d.Slug == models.UncategorizedSlug {
+			hasUncategorized = true
+			break
+		}
+	}
+	if !hasUncategorized {
+		defaults = append([]DefaultCategory{BuiltinDefaults[0]}, defaults...)
+	}
+
+	created := 0
+	err := store.Atomic(func(s repository.Store) error {
+		for _, d := range defaults {
+			_, err := s.Categories().FindDefaultBySlug(d.Slug)
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, repository.ErrNotFound) {
+				return err
+			}
+
+			category := newDefaultCategory(d)
+			if err := s.Categories().Create(&category); err != nil {
+				return fmt.Errorf("категория %s: %w", d.Slug, err)
+			}
+			created++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if created > 0 {
+		log.Printf("Создано категорий по умолчанию: %d", created)
+	}
+	return created, nil
+}
+
+func newDefaultCategory(d DefaultCategory) models.Category {
+	slug := d.Slug
+	category := models.Category{
+		Name:         d.Name,
+		Color:        d.Color,
+		IsDefault:    true,
+		Slug:         &slug,
+		Translations: models.Translations(d.Translations),
+	}
+	if category.Color == "" {
+		category.Color = "#16a34a"
+	}
+	if category.Translations == nil {
+		category.Translations = models.Translations{}
+	}
+	return category
+}
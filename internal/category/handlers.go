@@ -4,29 +4,45 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/Anabol1ks/pers-fin-m/internal/httputil"
 	"github.com/Anabol1ks/pers-fin-m/internal/models"
-	"github.com/Anabol1ks/pers-fin-m/internal/storage"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
 	"github.com/gin-gonic/gin"
 )
 
+type Handler struct {
+	store repository.Store
+}
+
+func NewHandler(store repository.Store) *Handler {
+	return &Handler{store: store}
+}
+
 // @Security BearerAuth
 // GetAllCategories godoc
 // @Summary Получить категории
-// @Description Получить все категории пользователя или категории по умолчанию
+// @Description Получить все категории пользователя или категории по умолчанию.
+// @Description Названия категорий по умолчанию переводятся по параметру lang или заголовку Accept-Language
 // @Tags Categories
 // @Produce json
+// @Param lang query string false "Язык названий категорий по умолчанию (ru, en)"
 // @Success 200 {array} models.Category
 // @Failure 500 {object} response.ErrorResponse "Ошибка при получении категорий"
 // @Router /categories [get]
-func GetAllCategories(c *gin.Context) {
+func (h *Handler) GetAllCategories(c *gin.Context) {
 	userID := c.GetUint("userID")
 
-	var categories []models.Category
-	if err := storage.DB.Where("user_id IS NULL OR user_id = ?", userID).Find(&categories).Error; err != nil {
+	categories, err := h.store.Categories().ListForUser(userID)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении категорий"})
 		return
 	}
 
+	lang := httputil.RequestLang(c)
+	for i := range categories {
+		categories[i].Name = categories[i].LocalizedName(lang)
+	}
+
 	c.JSON(http.StatusOK, categories)
 }
 
@@ -47,7 +63,7 @@ type CreateCategoryInput struct {
 // @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
 // @Failure 500 {object} response.ErrorResponse "Ошибка создания категории"
 // @Router /categories [post]
-func CreateCategory(c *gin.Context) {
+func (h *Handler) CreateCategory(c *gin.Context) {
 	userID := c.GetUint("userID")
 	log.Println(userID)
 
@@ -57,7 +73,7 @@ func CreateCategory(c *gin.Context) {
 		return
 	}
 
-	if err := storage.DB.Where("name = ? AND user_id = ?", input.Name, userID).First(&models.Category{}).Error; err == nil {
+	if _, err := h.store.Categories().FindOwnedByName(userID, input.Name); err == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Категория с таким названием уже существует"})
 		return
 	}
@@ -68,7 +84,7 @@ func CreateCategory(c *gin.Context) {
 		Color:  input.Color,
 	}
 
-	if err := storage.DB.Create(&category).Error; err != nil {
+	if err := h.store.Categories().Create(&category); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании категории"})
 		return
 	}
@@ -87,28 +103,32 @@ func CreateCategory(c *gin.Context) {
 // @Failure 404 {object} response.ErrorResponse "Категория не найдена или не принадлежит пользователю"
 // @Failure 500 {object} response.ErrorResponse "Ошибка удаления категории"
 // @Router /categories/{id} [delete]
-func DelCategory(c *gin.Context) {
+func (h *Handler) DelCategory(c *gin.Context) {
 	userID := c.GetUint("userID")
-	categoryID := c.Param("id")
+	categoryID, err := httputil.ParamID(c, "id")
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Категория не найдена или не принадлежит пользователю"})
+		return
+	}
 
-	var uncategorized models.Category
-	if err := storage.DB.Where("name = ? AND user_id IS NULL", "Без категории").First(&uncategorized).Error; err != nil {
+	uncategorized, err := h.store.Categories().FindDefaultBySlug(models.UncategorizedSlug)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении категории 'Без категории'"})
 		return
 	}
 
-	var category models.Category
-	if err := storage.DB.Where("id = ? AND user_id = ?", categoryID, userID).First(&category).Error; err != nil {
+	category, err := h.store.Categories().GetOwned(categoryID, userID)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Категория не найдена или не принадлежит пользователю"})
 		return
 	}
 
-	if err := storage.DB.Model(&models.Transaction{}).Where("category = ? AND user_id = ?", categoryID, userID).Update("category", uncategorized.ID).Error; err != nil {
+	if err := h.store.Transactions().ReassignCategory(userID, categoryID, uncategorized.ID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении транзакций"})
 		return
 	}
 
-	if err := storage.DB.Delete(category).Error; err != nil {
+	if err := h.store.Categories().Delete(category); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении категории"})
 		return
 	}
@@ -135,9 +155,13 @@ type UpdateCategoryInput struct {
 // @Failure 404 {object} response.ErrorResponse "Категория не найдена"
 // @Failure 500 {object} response.ErrorResponse "Ошибка обновления категории"
 // @Router /categories/{id} [put]
-func UpdateCategory(c *gin.Context) {
+func (h *Handler) UpdateCategory(c *gin.Context) {
 	userID := c.GetUint("userID")
-	categoryID := c.Param("id")
+	categoryID, err := httputil.ParamID(c, "id")
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Категория не найдена"})
+		return
+	}
 
 	var input UpdateCategoryInput
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -145,8 +169,8 @@ func UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	var category models.Category
-	if err := storage.DB.Where("id = ? AND user_id = ?", categoryID, userID).First(&category).Error; err != nil {
+	category, err := h.store.Categories().GetOwned(categoryID, userID)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Категория не найдена"})
 		return
 	}
@@ -156,7 +180,7 @@ func UpdateCategory(c *gin.Context) {
 	if input.Color != "" {
 		category.Color = input.Color
 	}
-	if err := storage.DB.Save(&category).Error; err != nil {
+	if err := h.store.Categories().Save(category); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении категории"})
 		return
 	}
@@ -0,0 +1,221 @@
+package сategory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository/memory"
+	"github.com/gin-gonic/gin"
+)
+
+// newTestRouter поднимает маршруты категорий поверх хранилища в памяти.
+// Пользователь запроса берётся из заголовка X-User-ID вместо токена.
+func newTestRouter(t *testing.T) (*memory.Store, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	store := memory.NewStore()
+	if _, err := Seed(store, BuiltinDefaults[:3]); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewHandler(store)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		id, _ := strconv.Atoi(c.GetHeader("X-User-ID"))
+		c.Set("userID", uint(id))
+	})
+	r.GET("/categories", h.GetAllCategories)
+	r.POST("/categories", h.CreateCategory)
+	r.PUT("/categories/:id", h.UpdateCategory)
+	r.DELETE("/categories/:id", h.DelCategory)
+	r.GET("/admin/categories", h.ListDefaultCategories)
+	r.POST("/admin/categories", h.CreateDefaultCategory)
+	r.DELETE("/admin/categories/:id", h.RetireDefaultCategory)
+	r.POST("/admin/categories/:id/restore", h.RestoreDefaultCategory)
+	return store, r
+}
+
+func call(t *testing.T, r *gin.Engine, userID uint, method, path string, body, out any) int {
+	t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", strconv.Itoa(int(userID)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if out != nil && w.Code < 300 {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: %v: %s", method, path, err, w.Body)
+		}
+	}
+	return w.Code
+}
+
+// names возвращает названия категорий, видимых пользователю.
+func names(t *testing.T, r *gin.Engine, userID uint, query string) []string {
+	t.Helper()
+	var categories []models.Category
+	if code := call(t, r, userID, "GET", "/categories"+query, nil, &categories); code != http.StatusOK {
+		t.Fatalf("list: status %d", code)
+	}
+	result := []string{}
+	for _, category := range categories {
+		result = append(result, category.Name)
+	}
+	return result
+}
+
+func TestSeed(t *testing.T) {
+	store := memory.NewStore()
+	tests := []struct {
+		name     string
+		defaults []DefaultCategory
+		want     int
+	}{
+		{"«Без категории» добавляется всегда", []DefaultCategory{{Slug: "pets", Name: "Питомцы"}}, 2},
+		{"повторный запуск", []DefaultCategory{{Slug: "pets", Name: "Питомцы"}}, 0},
+		{"только новые", BuiltinDefaults[:3], 2},
+	}
+	for _, tt := range tests {
+		if got, err := Seed(store, tt.defaults); err != nil || got != tt.want {
+			t.Errorf("%s: Seed() = %d, %v, want %d", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestCategories(t *testing.T) {
+	store, r := newTestRouter(t)
+	uncategorized, err := store.Categories().FindDefaultBySlug(models.UncategorizedSlug)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var own models.Category
+	if code := call(t, r, 1, "POST", "/categories", CreateCategoryInput{Name: "Хобби", Color: "#000000"}, &own); code != http.StatusCreated {
+		t.Fatalf("create: status %d", code)
+	}
+	if code := call(t, r, 2, "POST", "/categories", CreateCategoryInput{Name: "Хобби"}, nil); code != http.StatusCreated {
+		t.Fatalf("create у другого пользователя: status %d", code)
+	}
+
+	ownPath := fmt.Sprintf("/categories/%d", own.ID)
+	steps := []struct {
+		name   string
+		userID uint
+		method string
+		path   string
+		body   any
+		want   int
+	}{
+		{"повтор названия", 1, "POST", "/categories", CreateCategoryInput{Name: "Хобби"}, http.StatusBadRequest},
+		{"без названия", 1, "POST", "/categories", CreateCategoryInput{}, http.StatusBadRequest},
+		{"переименование", 1, "PUT", ownPath, UpdateCategoryInput{Name: "Увлечения"}, http.StatusOK},
+		{"чужая категория", 2, "PUT", ownPath, UpdateCategoryInput{Name: "Чужое"}, http.StatusNotFound},
+		{"категория по умолчанию", 1, "PUT", fmt.Sprintf("/categories/%d", uncategorized.ID), UpdateCategoryInput{Name: "Прочее"}, http.StatusNotFound},
+		{"неверный id", 1, "PUT", "/categories/abc", UpdateCategoryInput{Name: "Прочее"}, http.StatusNotFound},
+		{"удаление чужой", 2, "DELETE", ownPath, nil, http.StatusNotFound},
+		{"удаление по умолчанию", 1, "DELETE", fmt.Sprintf("/categories/%d", uncategorized.ID), nil, http.StatusNotFound},
+	}
+	for _, step := range steps {
+		if code := call(t, r, step.userID, step.method, step.path, step.body, nil); code != step.want {
+			t.Errorf("%s: status %d, want %d", step.name, code, step.want)
+		}
+	}
+
+	tests := []struct {
+		userID uint
+		query  string
+		want   []string
+	}{
+		{1, "", []string{"Без категории", "Продукты", "Кафе и рестораны", "Увлечения"}},
+		{1, "?lang=en", []string{"Uncategorized", "Groceries", "Restaurants", "Увлечения"}},
+		{2, "", []string{"Без категории", "Продукты", "Кафе и рестораны", "Хобби"}},
+	}
+	for _, tt := range tests {
+		if got := names(t, r, tt.userID, tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("категории пользователя %d%s = %v, want %v", tt.userID, tt.query, got, tt.want)
+		}
+	}
+
+	// Транзакции удалённой категории переходят в «Без категории»
+	transaction := models.Transaction{UserID: 1, AccountID: 1, Amount: 100, Date: time.Now(), Title: "Краски", Category: own.ID, Type: models.Expense}
+	if err := store.Transactions().Create(&transaction); err != nil {
+		t.Fatal(err)
+	}
+	if code := call(t, r, 1, "DELETE", ownPath, nil, nil); code != http.StatusOK {
+		t.Fatalf("delete: status %d", code)
+	}
+	moved, err := store.Transactions().GetOwned(transaction.ID, 1)
+	if err != nil || moved.Category != uncategorized.ID {
+		t.Errorf("категория транзакции = %+v, %v, want %d", moved, err, uncategorized.ID)
+	}
+	if code := call(t, r, 1, "DELETE", ownPath, nil, nil); code != http.StatusNotFound {
+		t.Errorf("повторное удаление: status %d, want 404", code)
+	}
+}
+
+func TestDefaultCategories(t *testing.T) {
+	store, r := newTestRouter(t)
+	uncategorized, err := store.Categories().FindDefaultBySlug(models.UncategorizedSlug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groceries, err := store.Categories().FindDefaultBySlug("groceries")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		want   int
+		// Категории, видимые пользователю после шага
+		visible []string
+	}{
+		{"новая категория", "POST", "/admin/categories", DefaultCategory{Slug: "pets", Name: "Питомцы", Translations: map[string]string{"en": "Pets"}}, http.StatusCreated,
+			[]string{"Без категории", "Продукты", "Кафе и рестораны", "Питомцы"}},
+		{"повтор кода", "POST", "/admin/categories", DefaultCategory{Slug: "pets", Name: "Животные"}, http.StatusConflict,
+			[]string{"Без категории", "Продукты", "Кафе и рестораны", "Питомцы"}},
+		{"без кода", "POST", "/admin/categories", DefaultCategory{Name: "Животные"}, http.StatusBadRequest,
+			[]string{"Без категории", "Продукты", "Кафе и рестораны", "Питомцы"}},
+		{"вывод из оборота", "DELETE", fmt.Sprintf("/admin/categories/%d", groceries.ID), nil, http.StatusOK,
+			[]string{"Без категории", "Кафе и рестораны", "Питомцы"}},
+		{"«Без категории» не выводится", "DELETE", fmt.Sprintf("/admin/categories/%d", uncategorized.ID), nil, http.StatusBadRequest,
+			[]string{"Без категории", "Кафе и рестораны", "Питомцы"}},
+		{"возврат", "POST", fmt.Sprintf("/admin/categories/%d/restore", groceries.ID), nil, http.StatusOK,
+			[]string{"Без категории", "Продукты", "Кафе и рестораны", "Питомцы"}},
+		{"неизвестная категория", "DELETE", "/admin/categories/999", nil, http.StatusNotFound,
+			[]string{"Без категории", "Продукты", "Кафе и рестораны", "Питомцы"}},
+	}
+	for _, step := range steps {
+		if code := call(t, r, 1, step.method, step.path, step.body, nil); code != step.want {
+			t.Errorf("%s: status %d, want %d", step.name, code, step.want)
+		}
+		if got := names(t, r, 1, ""); !reflect.DeepEqual(got, step.visible) {
+			t.Errorf("%s: категории = %v, want %v", step.name, got, step.visible)
+		}
+	}
+
+	// Администратор видит и выведенные из оборота категории
+	if code := call(t, r, 1, "DELETE", fmt.Sprintf("/admin/categories/%d", groceries.ID), nil, nil); code != http.StatusOK {
+		t.Fatalf("retire: status %d", code)
+	}
+	var all []models.Category
+	if code := call(t, r, 1, "GET", "/admin/categories", nil, &all); code != http.StatusOK || len(all) != 4 {
+		t.Errorf("admin list: status %d, %d категорий, want 4", code, len(all))
+	}
+}
@@ -0,0 +1,166 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+)
+
+// Структура для передачи данных в шаблон уведомления о бюджете
+type BudgetAlertData struct {
+	Username  string
+	Budget    string
+	Threshold int  // достигнутый порог, %
+	Over      bool // бюджет превышен
+	Period    string
+	Spent     string
+	Available string
+	Remaining string
+	Currency  string
+}
+
+// HTML-шаблон уведомления о расходовании бюджета, оформлен как письмо с кодом подтверждения
+var budgetAlertTemplate = `<!DOCTYPE html>
+<html lang="ru">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <meta name="color-scheme" content="dark light">
+  <title>Бюджет {{.Budget}}</title>
+  <style>
+    @import url('https://fonts.googleapis.com/css2?family=Inter:wght@400;500;600;700&display=swap');
+
+    html, body {
+      height: 100%;
+      margin: 0;
+      padding: 0;
+      font-size: 18px;
+    }
+
+    body {
+      display: flex;
+      align-items: center;
+      justify-content: center;
+      background-color: #0a0a0a;
+      font-family: 'Inter', -apple-system, BlinkMacSystemFont, sans-serif;
+      color: #ffffff;
+      line-height: 1.5;
+    }
+
+    .container {
+      width: 100%;
+      max-width: 600px;
+      padding: 32px;
+      background-color: hsl(240, 10%, 4%);
+      border: 1px solid hsl(240, 5%, 26%);
+      border-radius: 12px;
+      box-sizing: border-box;
+    }
+
+    .header {
+      text-align: center;
+      padding-bottom: 24px;
+      margin-bottom: 24px;
+      border-bottom: 1px solid hsl(240, 5%, 26%);
+    }
+
+    .header h1 {
+      font-size: 24px;
+      font-weight: 700;
+      margin: 0;
+      color: #ffffff;
+      letter-spacing: -0.5px;
+    }
+
+    .content {
+      font-size: 18px;
+      color: #ffffff;
+      margin-bottom: 32px;
+    }
+
+    .content p {
+      margin: 16px 0;
+    }
+
+    .code {
+      display: inline-block;
+      padding: 16px 32px;
+      font-size: 24px;
+      font-weight: 700;
+      background-color: hsl(240, 5%, 12%);
+      border: 1px solid hsl(240, 5%, 26%);
+      border-radius: 8px;
+      margin: 24px 0;
+      color: #ffffff;
+      letter-spacing: 2px;
+      box-shadow: 0 2px 4px rgba(0, 0, 0, 0.1);
+    }
+
+    .over {
+      border-color: hsl(0, 72%, 51%);
+    }
+
+    .footer {
+      font-size: 14px;
+      color: #ffffff;
+      text-align: center;
+      padding-top: 24px;
+      margin-top: 32px;
+      border-top: 1px solid hsl(240, 5%, 26%);
+    }
+  </style>
+</head>
+<body>
+  <div class="container">
+    <div class="header">
+      <h1>{{if .Over}}🚨 Бюджет превышен{{else}}⚠️ Бюджет почти израсходован{{end}}</h1>
+    </div>
+
+    <div class="content">
+      <p>Здравствуйте, {{.Username}}</p>
+      <p>Расходы по бюджету «{{.Budget}}» за {{.Period}} достигли {{.Threshold}}% от доступной суммы.</p>
+
+      <div class="code{{if .Over}} over{{end}}">{{.Spent}} из {{.Available}} {{.Currency}}</div>
+
+      {{if .Over}}
+      <p>Перерасход составляет {{.Remaining}} {{.Currency}}.</p>
+      {{else}}
+      <p>До конца периода осталось {{.Remaining}} {{.Currency}}.</p>
+      {{end}}
+      <p>Отключить эти письма можно в настройках уведомлений.</p>
+    </div>
+
+    <div class="footer">
+      <p>С уважением,<br>Команда PFM</p>
+      <p style="margin-top: 8px;">© 2025 PFM. Все права защищены</p>
+    </div>
+  </div>
+</body>
+</html>`
+
+func SendBudgetAlert(email string, data BudgetAlertData) error {
+	tmpl, err := template.New("budgetAlert").Parse(budgetAlertTemplate)
+	if err != nil {
+		log.Println("Ошибка парсинга шаблона:", err)
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		log.Println("Ошибка выполнения шаблона:", err)
+		return err
+	}
+
+	subject := fmt.Sprintf("Бюджет «%s»: израсходовано %d%%", data.Budget, data.Threshold)
+	if data.Over {
+		subject = fmt.Sprintf("Бюджет «%s» превышен", data.Budget)
+	}
+	if err := SendEmail(email, subject, buf.String()); err != nil {
+		log.Println("Ошибка отправки письма:", err)
+		return err
+	}
+
+	log.Println("Письмо отправлено")
+	return nil
+}
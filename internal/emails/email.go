@@ -5,14 +5,8 @@ import (
 	"fmt"
 	"html/template"
 	"log"
-	"net/http"
 	"net/smtp"
 	"os"
-
-	"github.com/Anabol1ks/pers-fin-m/internal/auth"
-	"github.com/Anabol1ks/pers-fin-m/internal/storage"
-	"github.com/Anabol1ks/pers-fin-m/internal/users"
-	"github.com/gin-gonic/gin"
 )
 
 func SendEmail(to, subject, body string) error {
@@ -40,6 +34,7 @@ func SendEmail(to, subject, body string) error {
 type VerifyData struct {
 	Username string
 	Code     string
+	TTL      string // срок действия кода, например «15 минут»
 }
 
 // HTML-шаблон для письма.
@@ -198,6 +193,8 @@ var emailTemplate = `<!DOCTYPE html>
 
       <div class="code">{{.Code}}</div>
 
+      <p>Код действует {{.TTL}}. Никому не сообщайте его.</p>
+
       <p>Если вы не запрашивали подтверждение, проигнорируйте это письмо.</p>
     </div>
 
@@ -209,11 +206,12 @@ var emailTemplate = `<!DOCTYPE html>
 </body>
 </html>`
 
-func SendVerifyCode(username, email, code string) error {
+func SendVerifyCode(username, email, code, ttl string) error {
 	// Подготовка данных для шаблона
 	data := VerifyData{
 		Username: username,
 		Code:     code,
+		TTL:      ttl,
 	}
 
 	// Создаём новый шаблон и парсим его
@@ -242,47 +240,14 @@ func SendVerifyCode(username, email, code string) error {
 	return nil
 }
 
-// @Security BearerAuth
-// SendNewVerify godoc
-// @Summary Запрос на новое письмо с кодом
-// @Description Запрос пользователя на новое письмо с кодом подтверждения
-// @Tags auth
-// @Accept json
-// @Produce json
-// @Success 200 {object} response.SuccessResponse "Письмо отправлено"
-// @Failure 400 {object} response.ErrorResponse "Неверный код подтверждения"
-// @Failure 500 {object} response.ErrorResponse "Пользователь не существует"
-// @Failure 409 {object} response.ErrorResponse "Аккаунт уже подтверждён"
-// @Router /auth/newVerify [post]
-func SendNewVerify(c *gin.Context) {
-	userID := c.GetUint("userID")
-
-	var user users.User
-	if err := storage.DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
-	}
-
-	if user.Verified {
-		c.JSON(http.StatusConflict, gin.H{"error": "Аккаунт уже подтверждён"})
-		return
-	}
-
-	verifCode, err := auth.GenerateVerificationCode()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка генерации кода подтверждения"})
-		return
-	}
-
-	user.VerificationCode = verifCode
+// Mailer отправляет письма пакета auth. Пакет auth не импортирует email,
+// поэтому получает отправителя при создании обработчика.
+type Mailer struct{}
 
-	if err := storage.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось обносить код подтверждения"})
-		return
-	}
+func (Mailer) SendVerifyCode(username, email, code, ttl string) error {
+	return SendVerifyCode(username, email, code, ttl)
+}
 
-	if err := SendVerifyCode(user.Username, user.Email, verifCode); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка отправки письма"})
-		return
-	}
-	c.JSON(http.StatusOK, gin.H{"message": "Письмо отправлено"})
+func (Mailer) SendPasswordReset(username, email, link, ttl string) error {
+	return SendPasswordReset(email, PasswordResetData{Username: username, Link: link, TTL: ttl})
 }
@@ -0,0 +1,172 @@
+package email
+
+import (
+	"bytes"
+	"html/template"
+	"log"
+)
+
+// Структура для передачи данных в шаблон письма сброса пароля
+type PasswordResetData struct {
+	Username string
+	Link     string
+	TTL      string // срок действия ссылки, например «1 час»
+}
+
+// HTML-шаблон письма со ссылкой для сброса пароля, оформлен как письмо с кодом подтверждения
+var passwordResetTemplate = `<!DOCTYPE html>
+<html lang="ru">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <meta name="color-scheme" content="dark light">
+  <title>Сброс пароля</title>
+  <style>
+    @import url('https://fonts.googleapis.com/css2?family=Inter:wght@400;500;600;700&display=swap');
+
+    html, body {
+      height: 100%;
+      margin: 0;
+      padding: 0;
+      font-size: 18px;
+    }
+
+    body {
+      display: flex;
+      align-items: center;
+      justify-content: center;
+      background-color: #0a0a0a;
+      font-family: 'Inter', -apple-system, BlinkMacSystemFont, sans-serif;
+      color: #ffffff;
+      line-height: 1.5;
+    }
+
+    .container {
+      width: 100%;
+      max-width: 600px;
+      padding: 32px;
+      background-color: hsl(240, 10%, 4%);
+      border: 1px solid hsl(240, 5%, 26%);
+      border-radius: 12px;
+      box-sizing: border-box;
+    }
+
+    .header {
+      text-align: center;
+      padding-bottom: 24px;
+      margin-bottom: 24px;
+      border-bottom: 1px solid hsl(240, 5%, 26%);
+    }
+
+    .header h1 {
+      font-size: 24px;
+      font-weight: 700;
+      margin: 0;
+      color: #ffffff;
+      letter-spacing: -0.5px;
+    }
+
+    .content {
+      font-size: 18px;
+      color: #ffffff;
+      margin-bottom: 32px;
+    }
+
+    .content p {
+      margin: 16px 0;
+    }
+
+    .code {
+      display: inline-block;
+      padding: 16px 32px;
+      font-size: 24px;
+      font-weight: 700;
+      background-color: hsl(240, 5%, 12%);
+      border: 1px solid hsl(240, 5%, 26%);
+      border-radius: 8px;
+      margin: 24px 0;
+      color: #ffffff;
+      letter-spacing: 2px;
+      box-shadow: 0 2px 4px rgba(0, 0, 0, 0.1);
+    }
+
+    a, a:link, a:visited, a:hover, a:active {
+      color: #0a0a0a !important;
+      text-decoration: none;
+    }
+
+    .button {
+      display: inline-block;
+      padding: 12px 24px;
+      background-color: hsl(240, 5%, 96%);
+      border-radius: 6px;
+      font-weight: 600;
+      border: 1px solid hsl(240, 5%, 84%);
+      margin: 24px 0;
+    }
+
+    .link {
+      font-size: 14px;
+      word-break: break-all;
+    }
+
+    .link a {
+      color: #ffffff !important;
+    }
+
+    .footer {
+      font-size: 14px;
+      color: #ffffff;
+      text-align: center;
+      padding-top: 24px;
+      margin-top: 32px;
+      border-top: 1px solid hsl(240, 5%, 26%);
+    }
+  </style>
+</head>
+<body>
+  <div class="container">
+    <div class="header">
+      <h1>🔑 Сброс пароля</h1>
+    </div>
+
+    <div class="content">
+      <p>Здравствуйте, {{.Username}}</p>
+      <p>Мы получили запрос на сброс пароля. Чтобы задать новый пароль, перейдите по ссылке:</p>
+
+      <a class="button" href="{{.Link}}">Задать новый пароль</a>
+
+      <p class="link">Если кнопка не работает, скопируйте ссылку в браузер:<br><a href="{{.Link}}">{{.Link}}</a></p>
+      <p>Ссылка действует {{.TTL}} и только один раз. После смены пароля все входы в аккаунт будут завершены.</p>
+      <p>Если вы не запрашивали сброс пароля, проигнорируйте это письмо — пароль останется прежним.</p>
+    </div>
+
+    <div class="footer">
+      <p>С уважением,<br>Команда PFM</p>
+      <p style="margin-top: 8px;">© 2025 PFM. Все права защищены</p>
+    </div>
+  </div>
+</body>
+</html>`
+
+func SendPasswordReset(email string, data PasswordResetData) error {
+	tmpl, err := template.New("passwordReset").Parse(passwordResetTemplate)
+	if err != nil {
+		log.Println("Ошибка парсинга шаблона:", err)
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		log.Println("Ошибка выполнения шаблона:", err)
+		return err
+	}
+
+	if err := SendEmail(email, "Сброс пароля", buf.String()); err != nil {
+		log.Println("Ошибка отправки письма:", err)
+		return err
+	}
+
+	log.Println("Письмо отправлено")
+	return nil
+}
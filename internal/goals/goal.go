@@ -0,0 +1,86 @@
+// Package goals ведёт цели накоплений: взносы и снятия оформляются
+// транзакциями, связанными с целью, а прогресс и прогноз считаются
+// по этим транзакциям.
+package goals
+
+import (
+	"math"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+)
+
+const (
+	monthDays = 365.25 / 12 // средняя длина месяца в днях
+	// maxProjectionDays ограничивает прогноз: при слишком медленном темпе дата не считается.
+	maxProjectionDays = 100 * 365
+)
+
+// Progress — состояние цели и прогноз её достижения.
+type Progress struct {
+	Goal      uint         `json:"goal"`
+	Name      string       `json:"name"`
+	Currency  string       `json:"currency"`
+	Target    money.Amount `json:"target" swaggertype:"number"`
+	Saved     money.Amount `json:"saved" swaggertype:"number"`
+	Remaining money.Amount `json:"remaining" swaggertype:"number"`
+	Percent   float64      `json:"percent"`
+	Completed bool         `json:"completed"`
+	Deadline  *time.Time   `json:"deadline"`
+	// RequiredMonthly — сколько вносить в месяц, чтобы успеть к сроку. После срока — весь остаток.
+	RequiredMonthly *money.Amount `json:"requiredMonthly" swaggertype:"number"`
+	// AverageMonthly — средний чистый взнос в месяц с первого взноса.
+	AverageMonthly money.Amount `json:"averageMonthly" swaggertype:"number"`
+	// ProjectedDate — когда цель будет достигнута при среднем темпе взносов.
+	// Пусто, если цель достигнута или накопления не растут.
+	ProjectedDate *time.Time `json:"projectedDate"`
+	// OnTrack — успевает ли цель к сроку при среднем темпе. Пусто, если срок не задан.
+	OnTrack *bool `json:"onTrack"`
+}
+
+// Compute считает прогресс цели по её транзакциям, отсортированным по дате.
+func Compute(goal *models.Goal, transactions []models.Transaction, now time.Time) *Progress {
+	progress := &Progress{
+		Goal:     goal.ID,
+		Name:     goal.Name,
+		Currency: goal.Currency,
+		Target:   goal.Target,
+		Deadline: goal.Deadline,
+	}
+	for _, t := range transactions {
+		progress.Saved += goal.Contribution(t)
+	}
+	progress.Remaining = progress.Target - progress.Saved
+	if progress.Remaining < 0 {
+		progress.Remaining = 0
+	}
+	progress.Completed = progress.Saved >= progress.Target
+	progress.Percent = math.Round(float64(progress.Saved)/float64(progress.Target)*1000) / 10
+
+	// Темп считается хотя бы за месяц, иначе первый взнос даёт завышенный прогноз
+	if len(transactions) > 0 {
+		months := math.Max(now.Sub(transactions[0].Date).Hours()/24/monthDays, 1)
+		progress.AverageMonthly = money.Amount(math.Round(float64(progress.Saved) / months))
+	}
+
+	if !progress.Completed && progress.AverageMonthly > 0 {
+		days := math.Ceil(float64(progress.Remaining) / float64(progress.AverageMonthly) * monthDays)
+		if days <= maxProjectionDays {
+			projected := now.AddDate(0, 0, int(days))
+			progress.ProjectedDate = &projected
+		}
+	}
+
+	if goal.Deadline != nil {
+		onTrack := progress.Completed || (progress.ProjectedDate != nil && !progress.ProjectedDate.After(*goal.Deadline))
+		progress.OnTrack = &onTrack
+
+		if !progress.Completed {
+			months := int64(math.Max(math.Ceil(goal.Deadline.Sub(now).Hours()/24/monthDays), 1))
+			required := progress.Remaining.Div(months)
+			progress.RequiredMonthly = &required
+		}
+	}
+	return progress
+}
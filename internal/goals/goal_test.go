@@ -0,0 +1,113 @@
+package goals
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+)
+
+func TestCompute(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	// Ровно три средних месяца после первого взноса
+	now := day(2024, 1, 1).Add(time.Duration(3 * monthDays * 24 * float64(time.Hour)))
+	after := func(days int) *time.Time {
+		d := now.AddDate(0, 0, days)
+		return &d
+	}
+	amount := func(a money.Amount) *money.Amount { return &a }
+	flag := func(b bool) *bool { return &b }
+
+	counter := uint(5)
+	history := []models.Transaction{
+		{AccountID: 5, Type: models.Income, Amount: 20000, Date: day(2024, 1, 1)},
+		{AccountID: 1, CounterAccountID: &counter, Type: models.Transfer, Amount: 30000, CounterAmount: 30000, Date: day(2024, 2, 1)},
+		{AccountID: 1, Type: models.Expense, Amount: 99000, Date: day(2024, 2, 10)},
+		{AccountID: 5, Type: models.Expense, Amount: 5000, Date: day(2024, 2, 15)},
+	}
+
+	tests := []struct {
+		name         string
+		target       money.Amount
+		deadline     *time.Time
+		transactions []models.Transaction
+		want         Progress
+	}{
+		{
+			name:         "без срока",
+			target:       100000,
+			transactions: history,
+			want:         Progress{Saved: 45000, Remaining: 55000, Percent: 45, AverageMonthly: 15000, ProjectedDate: after(112)},
+		},
+		{
+			name:         "успевает к сроку",
+			target:       100000,
+			deadline:     ptr(day(2024, 12, 31)),
+			transactions: history,
+			want:         Progress{Saved: 45000, Remaining: 55000, Percent: 45, AverageMonthly: 15000, ProjectedDate: after(112), OnTrack: flag(true), RequiredMonthly: amount(6111)},
+		},
+		{
+			name:         "не успевает к сроку",
+			target:       100000,
+			deadline:     ptr(day(2024, 6, 1)),
+			transactions: history,
+			want:         Progress{Saved: 45000, Remaining: 55000, Percent: 45, AverageMonthly: 15000, ProjectedDate: after(112), OnTrack: flag(false), RequiredMonthly: amount(27500)},
+		},
+		{
+			name:         "срок прошёл",
+			target:       100000,
+			deadline:     ptr(day(2024, 3, 1)),
+			transactions: history,
+			want:         Progress{Saved: 45000, Remaining: 55000, Percent: 45, AverageMonthly: 15000, ProjectedDate: after(112), OnTrack: flag(false), RequiredMonthly: amount(55000)},
+		},
+		{
+			name:         "цель достигнута",
+			target:       40000,
+			deadline:     ptr(day(2024, 3, 1)),
+			transactions: history,
+			want:         Progress{Saved: 45000, Percent: 112.5, Completed: true, AverageMonthly: 15000, OnTrack: flag(true)},
+		},
+		{
+			name:         "темп не меньше чем за месяц",
+			target:       100000,
+			transactions: []models.Transaction{{AccountID: 5, Type: models.Income, Amount: 10000, Date: now.AddDate(0, 0, -10)}},
+			want:         Progress{Saved: 10000, Remaining: 90000, Percent: 10, AverageMonthly: 10000, ProjectedDate: after(274)},
+		},
+		{
+			name:         "слишком медленно",
+			target:       100000000000,
+			transactions: history,
+			want:         Progress{Saved: 45000, Remaining: 99999955000, Percent: 0, AverageMonthly: 15000},
+		},
+		{
+			name:         "снятий больше взносов",
+			target:       100000,
+			transactions: []models.Transaction{{AccountID: 5, Type: models.Expense, Amount: 1000, Date: day(2024, 1, 1)}},
+			want:         Progress{Saved: -1000, Remaining: 101000, Percent: -1, AverageMonthly: -333},
+		},
+		{
+			name:   "нет транзакций",
+			target: 100000,
+			want:   Progress{Remaining: 100000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := &models.Goal{Name: "Отпуск", Currency: "RUB", AccountID: 5, Target: tt.target, Deadline: tt.deadline}
+			want := tt.want
+			want.Name, want.Currency, want.Target, want.Deadline = "Отпуск", "RUB", tt.target, tt.deadline
+
+			if got := Compute(goal, tt.transactions, now); !reflect.DeepEqual(got, &want) {
+				t.Errorf("Compute() =\n%+v\nwant\n%+v", got, &want)
+			}
+		})
+	}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,465 @@
+package goals
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/budgets"
+	"github.com/Anabol1ks/pers-fin-m/internal/httputil"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/Anabol1ks/pers-fin-m/internal/transactions"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	store repository.Store
+}
+
+func NewHandler(store repository.Store) *Handler {
+	return &Handler{store: store}
+}
+
+type GoalInput struct {
+	Name        string       `json:"name" binding:"required,max=100"`
+	Description string       `json:"description"`
+	Target      money.Amount `json:"target" binding:"required" swaggertype:"number"`
+	Account     uint         `json:"account" binding:"required"` // счёт, на котором копятся деньги
+	Currency    string       `json:"currency"`                   // должна совпадать с валютой счёта
+	Deadline    *time.Time   `json:"deadline"`
+}
+
+type ContributionInput struct {
+	Amount        money.Amount  `json:"amount" binding:"required" swaggertype:"number"`
+	Account       uint          `json:"account"`                            // счёт списания взноса или зачисления снятия
+	CounterAmount *money.Amount `json:"counterAmount" swaggertype:"number"` // сумма зачисления, если валюты счетов различаются
+	Category      uint          `json:"category"`                           // категория расхода при снятии без счёта
+	Date          time.Time     `json:"date"`                               // по умолчанию сейчас
+	Title         string        `json:"title" binding:"max=100"`            // по умолчанию название цели
+	Description   string        `json:"description"`
+}
+
+// @Security BearerAuth
+// ListGoals godoc
+// @Summary Список целей накоплений
+// @Tags Goals
+// @Produce json
+// @Success 200 {array} models.Goal
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении целей"
+// @Router /goals [get]
+func (h *Handler) ListGoals(c *gin.Context) {
+	goals, err := h.store.Goals().ListForUser(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении целей"})
+		return
+	}
+	c.JSON(http.StatusOK, goals)
+}
+
+// @Security BearerAuth
+// GetGoal godoc
+// @Summary Получить цель накоплений
+// @Tags Goals
+// @Produce json
+// @Param id path string true "ID цели"
+// @Success 200 {object} models.Goal
+// @Failure 404 {object} response.ErrorResponse "Цель не найдена"
+// @Router /goals/{id} [get]
+func (h *Handler) GetGoal(c *gin.Context) {
+	goal, ok := h.ownedGoal(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, goal)
+}
+
+// @Security BearerAuth
+// CreateGoal godoc
+// @Summary Создать цель накоплений
+// @Description Создаёт цель с целевой суммой, сроком и счётом, на котором копятся деньги. Валюта цели — валюта счёта.
+// @Description Накопления цели складываются из взносов и снятий, а не из баланса счёта, поэтому на одном счёте можно копить на несколько целей.
+// @Tags Goals
+// @Accept json
+// @Produce json
+// @Param input body GoalInput true "Цель"
+// @Success 201 {object} models.Goal
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 500 {object} response.ErrorResponse "Ошибка сохранения цели"
+// @Router /goals [post]
+func (h *Handler) CreateGoal(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input GoalInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	goal, errMsg := h.goal(userID, input)
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	if err := h.store.Goals().Create(goal); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении цели"})
+		return
+	}
+	c.JSON(http.StatusCreated, goal)
+}
+
+// @Security BearerAuth
+// UpdateGoal godoc
+// @Summary Изменить цель накоплений
+// @Description Заменяет цель целиком. Счёт цели нельзя сменить, если по ней уже были взносы.
+// @Tags Goals
+// @Accept json
+// @Produce json
+// @Param id path string true "ID цели"
+// @Param input body GoalInput true "Цель"
+// @Success 200 {object} models.Goal
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 404 {object} response.ErrorResponse "Цель не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка сохранения цели"
+// @Router /goals/{id} [put]
+func (h *Handler) UpdateGoal(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input GoalInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	existing, ok := h.ownedGoal(c)
+	if !ok {
+		return
+	}
+
+	goal, errMsg := h.goal(userID, input)
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+	if goal.AccountID != existing.AccountID {
+		// Накопления считаются по счёту цели, со сменой счёта старые взносы потеряются
+		linked, err := h.store.Transactions().ListForGoal(existing.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении цели"})
+			return
+		}
+		if len(linked) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Нельзя сменить счёт цели, по которой уже были взносы"})
+			return
+		}
+	}
+	goal.Model = existing.Model
+
+	if err := h.store.Goals().Save(goal); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении цели"})
+		return
+	}
+	c.JSON(http.StatusOK, goal)
+}
+
+// @Security BearerAuth
+// DeleteGoal godoc
+// @Summary Удалить цель накоплений
+// @Description Удаляет цель. Транзакции взносов и снятий остаются.
+// @Tags Goals
+// @Produce json
+// @Param id path string true "ID цели"
+// @Success 200 {object} response.SuccessResponse "Цель удалена"
+// @Failure 404 {object} response.ErrorResponse "Цель не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка удаления цели"
+// @Router /goals/{id} [delete]
+func (h *Handler) DeleteGoal(c *gin.Context) {
+	goal, ok := h.ownedGoal(c)
+	if !ok {
+		return
+	}
+
+	if err := h.store.Goals().Delete(goal); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении цели"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Цель удалена"})
+}
+
+// @Security BearerAuth
+// Contribute godoc
+// @Summary Внести деньги на цель
+// @Description Создаёт перевод со счёта account (по умолчанию основного) на счёт цели, связанный с целью.
+// @Description amount списывается в валюте счёта списания; если валюты различаются, укажите counterAmount в валюте цели.
+// @Tags Goals
+// @Accept json
+// @Produce json
+// @Param id path string true "ID цели"
+// @Param input body ContributionInput true "Взнос"
+// @Success 201 {object} models.Transaction
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 404 {object} response.ErrorResponse "Цель не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка создания транзакции"
+// @Router /goals/{id}/contribute [post]
+func (h *Handler) Contribute(c *gin.Context) {
+	h.move(c, true)
+}
+
+// @Security BearerAuth
+// Withdraw godoc
+// @Summary Снять деньги с цели
+// @Description С account создаёт перевод со счёта цели на этот счёт, без него — расход со счёта цели в категории category,
+// @Description например при покупке, на которую копили. amount указывается в валюте цели и не может превышать накопленное.
+// @Tags Goals
+// @Accept json
+// @Produce json
+// @Param id path string true "ID цели"
+// @Param input body ContributionInput true "Снятие"
+// @Success 201 {object} models.Transaction
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 404 {object} response.ErrorResponse "Цель не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка создания транзакции"
+// @Router /goals/{id}/withdraw [post]
+func (h *Handler) Withdraw(c *gin.Context) {
+	h.move(c, false)
+}
+
+// @Security BearerAuth
+// ListContributions godoc
+// @Summary Взносы и снятия цели
+// @Tags Goals
+// @Produce json
+// @Param id path string true "ID цели"
+// @Success 200 {array} models.Transaction
+// @Failure 404 {object} response.ErrorResponse "Цель не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении транзакций"
+// @Router /goals/{id}/transactions [get]
+func (h *Handler) ListContributions(c *gin.Context) {
+	goal, ok := h.ownedGoal(c)
+	if !ok {
+		return
+	}
+
+	linked, err := h.store.Transactions().ListForGoal(goal.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении транзакций"})
+		return
+	}
+	c.JSON(http.StatusOK, linked)
+}
+
+// @Security BearerAuth
+// GetProgress godoc
+// @Summary Прогресс цели накоплений
+// @Description Возвращает накопленную сумму и процент выполнения, ежемесячный взнос, нужный, чтобы успеть к сроку,
+// @Description и прогноз даты достижения цели по среднему темпу взносов с первого взноса.
+// @Tags Goals
+// @Produce json
+// @Param id path string true "ID цели"
+// @Success 200 {object} Progress
+// @Failure 404 {object} response.ErrorResponse "Цель не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при расчёте прогресса"
+// @Router /goals/{id}/progress [get]
+func (h *Handler) GetProgress(c *gin.Context) {
+	goal, ok := h.ownedGoal(c)
+	if !ok {
+		return
+	}
+
+	linked, err := h.store.Transactions().ListForGoal(goal.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при расчёте прогресса"})
+		return
+	}
+	c.JSON(http.StatusOK, Compute(goal, linked, time.Now()))
+}
+
+var errInsufficient = errors.New("недостаточно накоплений")
+
+// move создаёт взнос (deposit) или снятие и связывает транзакцию с целью.
+func (h *Handler) move(c *gin.Context, deposit bool) {
+	var input ContributionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	goal, ok := h.ownedGoal(c)
+	if !ok {
+		return
+	}
+
+	transaction, errMsg := h.transaction(goal, input, deposit)
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	err := h.store.Atomic(func(s repository.Store) error {
+		if !deposit {
+			if err := s.Goals().Lock(goal.ID); err != nil {
+				errMsg = "Ошибка создания транзакции"
+				return err
+			}
+			linked, err := s.Transactions().ListForGoal(goal.ID)
+			if err != nil {
+				errMsg = "Ошибка создания транзакции"
+				return err
+			}
+			var saved money.Amount
+			for _, t := range linked {
+				saved += goal.Contribution(t)
+			}
+			if transaction.Amount > saved {
+				errMsg = "Сумма больше накопленного: " + saved.String() + " " + goal.Currency
+				return errInsufficient
+			}
+		}
+		if err := s.Transactions().Create(transaction); err != nil {
+			errMsg = "Ошибка создания транзакции"
+			return err
+		}
+		if err := transactions.ApplyEffect(s, transaction, 1); err != nil {
+			errMsg = "Не удалось обновить баланс"
+			return err
+		}
+		return nil
+	})
+	if errors.Is(err, errInsufficient) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errMsg})
+		return
+	}
+
+	budgets.Notify(h.store, goal.UserID, *transaction)
+	c.JSON(http.StatusCreated, transaction)
+}
+
+// transaction проверяет ввод и собирает транзакцию взноса или снятия.
+// Вторым значением возвращается текст ошибки.
+func (h *Handler) transaction(goal *models.Goal, input ContributionInput, deposit bool) (*models.Transaction, string) {
+	if input.Amount <= 0 {
+		return nil, "Сумма должна быть больше 0"
+	}
+	goalAccount, errMsg := transactions.UserAccount(h.store, goal.UserID, goal.AccountID)
+	if errMsg != "" {
+		return nil, "Счёт цели: " + errMsg
+	}
+	if input.Date.IsZero() {
+		input.Date = time.Now()
+	}
+	if input.Title == "" {
+		input.Title = goal.Name
+	}
+
+	goalID := goal.ID
+	transaction := &models.Transaction{
+		UserID:      goal.UserID,
+		Amount:      input.Amount,
+		Date:        input.Date,
+		Title:       input.Title,
+		Description: input.Description,
+		GoalID:      &goalID,
+	}
+
+	// Снятие без счёта зачисления — расход со счёта цели
+	if !deposit && input.Account == 0 {
+		category := input.Category
+		if category == 0 {
+			uncategorized, err := h.store.Categories().FindDefaultBySlug(models.UncategorizedSlug)
+			if err != nil {
+				return nil, "Категория «Без категории» не найдена"
+			}
+			category = uncategorized.ID
+		} else if _, err := h.store.Categories().GetAvailable(category, goal.UserID); err != nil {
+			return nil, "Указана неверная категория"
+		}
+		transaction.Type = models.Expense
+		transaction.AccountID = goalAccount.ID
+		transaction.Currency = goalAccount.Currency
+		transaction.Category = category
+		return transaction, ""
+	}
+
+	other, errMsg := transactions.UserAccount(h.store, goal.UserID, input.Account)
+	if errMsg != "" {
+		return nil, errMsg
+	}
+	if other.ID == goalAccount.ID {
+		return nil, "Счёт должен отличаться от счёта цели"
+	}
+	from, to := other, goalAccount
+	if !deposit {
+		from, to = goalAccount, other
+	}
+
+	counter := input.Amount
+	if from.Currency != to.Currency {
+		if input.CounterAmount == nil {
+			return nil, "Для счетов в разных валютах укажите counterAmount"
+		}
+		if *input.CounterAmount <= 0 {
+			return nil, "Сумма зачисления должна быть больше 0"
+		}
+		counter = *input.CounterAmount
+	}
+	uncategorized, err := h.store.Categories().FindDefaultBySlug(models.UncategorizedSlug)
+	if err != nil {
+		return nil, "Категория «Без категории» не найдена"
+	}
+
+	toID := to.ID
+	transaction.Type = models.Transfer
+	transaction.AccountID = from.ID
+	transaction.Currency = from.Currency
+	transaction.CounterAccountID = &toID
+	transaction.CounterAmount = counter
+	transaction.Category = uncategorized.ID
+	return transaction, ""
+}
+
+// goal проверяет ввод и собирает цель. Вторым значением возвращается текст ошибки.
+func (h *Handler) goal(userID uint, input GoalInput) (*models.Goal, string) {
+	if input.Target <= 0 {
+		return nil, "Целевая сумма должна быть больше 0"
+	}
+	account, errMsg := transactions.UserAccount(h.store, userID, input.Account)
+	if errMsg != "" {
+		return nil, errMsg
+	}
+	if currency := strings.ToUpper(strings.TrimSpace(input.Currency)); currency != "" && currency != account.Currency {
+		return nil, "Валюта цели должна совпадать с валютой счёта " + account.Currency
+	}
+
+	return &models.Goal{
+		UserID:      userID,
+		Name:        input.Name,
+		Description: input.Description,
+		Target:      input.Target,
+		Currency:    account.Currency,
+		AccountID:   account.ID,
+		Deadline:    input.Deadline,
+	}, ""
+}
+
+func (h *Handler) ownedGoal(c *gin.Context) (*models.Goal, bool) {
+	userID := c.GetUint("userID")
+
+	goalID, err := httputil.ParamID(c, "id")
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Цель не найдена"})
+		return nil, false
+	}
+	goal, err := h.store.Goals().GetOwned(goalID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Цель не найдена"})
+		return nil, false
+	}
+	return goal, true
+}
@@ -0,0 +1,29 @@
+// Package httputil содержит общие помощники для HTTP-обработчиков.
+package httputil
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParamID читает числовой идентификатор из параметра пути.
+func ParamID(c *gin.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
+
+// RequestLang определяет язык из параметра lang или первого тега Accept-Language.
+func RequestLang(c *gin.Context) string {
+	if lang := c.Query("lang"); lang != "" {
+		return strings.ToLower(lang)
+	}
+	tag, _, _ := strings.Cut(c.GetHeader("Accept-Language"), ",")
+	tag, _, _ = strings.Cut(tag, ";")
+	tag, _, _ = strings.Cut(tag, "-")
+	return strings.ToLower(strings.TrimSpace(tag))
+}
@@ -0,0 +1,304 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// ValidateMapping проверяет, что описания формата достаточно для разбора файла.
+func ValidateMapping(m models.ImportMapping) error {
+	if m.DateColumn == "" || m.DateFormat == "" {
+		return errors.New("Укажите колонку и формат даты")
+	}
+	if m.TitleColumn == "" {
+		return errors.New("Укажите колонку с названием операции")
+	}
+	switch m.AmountSign {
+	case models.SignNegativeExpense, models.SignNegativeIncome:
+		if m.AmountColumn == "" {
+			return errors.New("Укажите колонку суммы")
+		}
+	case models.SignSeparateColumns:
+		if m.IncomeColumn == "" || m.ExpenseColumn == "" {
+			return errors.New("Укажите колонки прихода и расхода")
+		}
+	default:
+		return fmt.Errorf("Неизвестный способ определения знака суммы %q", m.AmountSign)
+	}
+	if m.DecimalSeparator != "." && m.DecimalSeparator != "," {
+		return errors.New("Десятичный разделитель должен быть точкой или запятой")
+	}
+	if _, err := delimiter(m.Delimiter); err != nil {
+		return err
+	}
+	if _, err := decode(strings.NewReader(""), m.Encoding); err != nil {
+		return err
+	}
+	if m.SkipRows < 0 {
+		return errors.New("Количество пропускаемых строк не может быть отрицательным")
+	}
+	return nil
+}
+
+// ParseCSV разбирает выписку по описанию формата. Ошибки отдельных строк
+// записываются в Record.Errors, ошибка возвращается, только если файл
+// нельзя прочитать целиком.
+func ParseCSV(r io.Reader, m models.ImportMapping) ([]Record, error) {
+	if err := ValidateMapping(m); err != nil {
+		return nil, err
+	}
+
+	decoded, err := decode(r, m.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	comma, _ := delimiter(m.Delimiter)
+
+	reader := csv.NewReader(decoded)
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	// При разделителе-табуляции TrimLeadingSpace склеил бы пустые колонки
+	reader.TrimLeadingSpace = !unicode.IsSpace(comma)
+
+	for i := 0; i < m.SkipRows; i++ {
+		if _, err := reader.Read(); err != nil {
+			return nil, fmt.Errorf("Не удалось пропустить строки перед заголовком: %w", err)
+		}
+	}
+
+	var header []string
+	if m.HasHeader {
+		if header, err = reader.Read(); err != nil {
+			return nil, fmt.Errorf("Не удалось прочитать заголовок: %w", err)
+		}
+	}
+	cols, err := resolveColumns(m, header)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := DateLayout(m.DateFormat)
+	var records []Record
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Ошибка чтения CSV: %w", err)
+		}
+		if blank(fields) {
+			continue
+		}
+		if len(records) == MaxRows {
+			return nil, fmt.Errorf("В файле больше %d операций", MaxRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		records = append(records, cols.record(line, fields, m, layout))
+	}
+	return records, nil
+}
+
+// columns хранит индексы колонок; -1 — колонка не используется.
+type columns struct {
+	date, amount, income, expense, title, description, category, currency int
+}
+
+func resolveColumns(m models.ImportMapping, header []string) (columns, error) {
+	var cols columns
+	var err error
+	resolve := func(name string) int {
+		if err != nil {
+			return -1
+		}
+		var index int
+		index, err = columnIndex(name, header)
+		return index
+	}
+
+	cols.date = resolve(m.DateColumn)
+	cols.amount = resolve(m.AmountColumn)
+	cols.income = resolve(m.IncomeColumn)
+	cols.expense = resolve(m.ExpenseColumn)
+	cols.title = resolve(m.TitleColumn)
+	cols.description = resolve(m.DescriptionColumn)
+	cols.category = resolve(m.CategoryColumn)
+	cols.currency = resolve(m.CurrencyColumn)
+	return cols, err
+}
+
+// columnIndex ищет колонку по номеру (с 1) или по названию из заголовка.
+func columnIndex(name string, header []string) (int, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return -1, nil
+	}
+	if n, err := strconv.Atoi(name); err == nil {
+		if n < 1 {
+			return -1, fmt.Errorf("Номер колонки должен быть больше 0: %d", n)
+		}
+		return n - 1, nil
+	}
+	for i, title := range header {
+		if strings.EqualFold(strings.TrimSpace(title), name) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("Колонка %q не найдена в заголовке", name)
+}
+
+func (c columns) record(line int, fields []string, m models.ImportMapping, layout string) Record {
+	value := func(index int) string {
+		if index < 0 || index >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[index])
+	}
+
+	record := Record{
+		Line:        line,
+		Title:       value(c.title),
+		Description: value(c.description),
+		Category:    value(c.category),
+		Currency:    strings.ToUpper(value(c.currency)),
+	}
+	if record.Title == "" {
+		record.Title = record.Description
+	}
+	if record.Title == "" {
+		record.fail("Пустое название операции")
+	}
+
+	date, err := time.ParseInLocation(layout, value(c.date), time.Local)
+	if err != nil {
+		record.fail(fmt.Sprintf("Неверная дата %q, ожидается формат %s", value(c.date), m.DateFormat))
+	}
+	record.Date = date
+
+	if m.AmountSign == models.SignSeparateColumns {
+		income, incomeErr := parseAmount(value(c.income), m.DecimalSeparator)
+		expense, expenseErr := parseAmount(value(c.expense), m.DecimalSeparator)
+		switch {
+		case incomeErr != nil && value(c.income) != "":
+			record.fail(fmt.Sprintf("Неверная сумма прихода %q", value(c.income)))
+		case expenseErr != nil && value(c.expense) != "":
+			record.fail(fmt.Sprintf("Неверная сумма расхода %q", value(c.expense)))
+		case income != 0 && expense != 0:
+			record.fail("Указаны и приход, и расход")
+		case income != 0:
+			record.Amount, record.Type = income.Abs(), models.Income
+		case expense != 0:
+			record.Amount, record.Type = expense.Abs(), models.Expense
+		default:
+			record.fail("Не указана сумма")
+		}
+		return record
+	}
+
+	amount, err := parseAmount(value(c.amount), m.DecimalSeparator)
+	switch {
+	case err != nil:
+		record.fail(fmt.Sprintf("Неверная сумма %q", value(c.amount)))
+	case amount == 0:
+		record.fail("Нулевая сумма")
+	default:
+		negative := amount < 0
+		if m.AmountSign == models.SignNegativeIncome {
+			negative = !negative
+		}
+		record.Amount, record.Type = amount.Abs(), models.Income
+		if negative {
+			record.Type = models.Expense
+		}
+	}
+	return record
+}
+
+// parseAmount разбирает сумму с указанным десятичным разделителем.
+// Второй разделитель считается разделителем разрядов, символы валют отбрасываются.
+func parseAmount(s, decimal string) (money.Amount, error) {
+	thousands := ","
+	if decimal == "," {
+		thousands = "."
+	}
+	s = strings.ReplaceAll(s, thousands, "")
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r == '-', r == '+', r == ',', r == '.':
+			return r
+		case r == '−': // знак минуса из выписок в PDF и Excel
+			return '-'
+		}
+		return -1
+	}, s)
+	if s == "" {
+		return 0, money.ErrInvalid
+	}
+	return money.Parse(s)
+}
+
+// DateLayout переводит формат вида DD.MM.YYYY HH:mm в шаблон time.Parse.
+// Формат, уже записанный шаблоном Go (с 2006), возвращается без изменений.
+func DateLayout(format string) string {
+	if strings.Contains(format, "2006") {
+		return format
+	}
+	return strings.NewReplacer(
+		"YYYY", "2006", "YY", "06",
+		"MM", "01", "DD", "02",
+		"HH", "15", "mm", "04", "ss", "05",
+	).Replace(format)
+}
+
+// decode возвращает поток UTF-8 без BOM.
+func decode(r io.Reader, encoding string) (io.Reader, error) {
+	switch strings.ToLower(encoding) {
+	case "", "utf-8", "utf8":
+		buffered := bufio.NewReader(r)
+		if bom, err := buffered.Peek(3); err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+			buffered.Discard(3)
+		}
+		return buffered, nil
+	case "cp1251", "windows-1251":
+		return charmap.Windows1251.NewDecoder().Reader(r), nil
+	}
+	return nil, fmt.Errorf("Неподдерживаемая кодировка %q, доступны utf-8 и cp1251", encoding)
+}
+
+func delimiter(s string) (rune, error) {
+	switch s {
+	case "":
+		return ',', nil
+	case `\t`, "tab":
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if size != len(s) || r == '"' || r == '\n' || r == '\r' {
+		return 0, fmt.Errorf("Неверный разделитель колонок %q", s)
+	}
+	return r, nil
+}
+
+func blank(fields []string) bool {
+	for _, field := range fields {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
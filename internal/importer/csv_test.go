@@ -0,0 +1,189 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+)
+
+func TestParseCSV(t *testing.T) {
+	base := models.ImportMapping{
+		HasHeader:        true,
+		DateColumn:       "Дата",
+		DateFormat:       "DD.MM.YYYY HH:mm",
+		AmountColumn:     "Сумма",
+		AmountSign:       models.SignNegativeExpense,
+		DecimalSeparator: ",",
+		Delimiter:        ";",
+		TitleColumn:      "Описание",
+		CategoryColumn:   "Категория",
+		CurrencyColumn:   "Валюта",
+	}
+	at := func(d, hh, mm int) time.Time {
+		return time.Date(2024, 1, d, hh, mm, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name    string
+		mapping func(m *models.ImportMapping)
+		file    string
+		want    []Record
+		wantErr bool
+	}{
+		{
+			name: "знак суммы и заголовок",
+			file: "\uFEFFДата;Сумма;Валюта;Описание;Категория\n" +
+				"15.01.2024 18:30;-1 250,50 ₽;rub;Пятёрочка;Продукты\n" +
+				";;;;\n" +
+				"16.01.2024 09:00;+5.000,00;RUB;\"Зарплата; январь\";\n" +
+				"17.01.2024 10:00;0;RUB;Ноль;\n" +
+				"32.01.2024 10:00;abc;RUB;;\n",
+			want: []Record{
+				{Line: 2, Date: at(15, 18, 30), Amount: 125050, Type: models.Expense, Title: "Пятёрочка", Category: "Продукты", Currency: "RUB"},
+				{Line: 4, Date: at(16, 9, 0), Amount: 500000, Type: models.Income, Title: "Зарплата; январь", Currency: "RUB"},
+				{Line: 5, Date: at(17, 10, 0), Title: "Ноль", Currency: "RUB", Errors: []string{"Нулевая сумма"}},
+				{Line: 6, Currency: "RUB", Errors: []string{
+					"Пустое название операции",
+					`Неверная дата "32.01.2024 10:00", ожидается формат DD.MM.YYYY HH:mm`,
+					`Неверная сумма "abc"`,
+				}},
+			},
+		},
+		{
+			name: "кредитная карта: отрицательная сумма — доход",
+			mapping: func(m *models.ImportMapping) {
+				m.AmountSign = models.SignNegativeIncome
+				m.DecimalSeparator = "."
+				m.Delimiter = ","
+				m.CategoryColumn, m.CurrencyColumn = "", ""
+			},
+			file: "Дата,Сумма,Описание\n15.01.2024 12:00,-100.5,Возврат\n15.01.2024 13:00,\"1,000\",Покупка\n",
+			want: []Record{
+				{Line: 2, Date: at(15, 12, 0), Amount: 10050, Type: models.Income, Title: "Возврат"},
+				{Line: 3, Date: at(15, 13, 0), Amount: 100000, Type: models.Expense, Title: "Покупка"},
+			},
+		},
+		{
+			name: "приход и расход в разных колонках по номерам",
+			mapping: func(m *models.ImportMapping) {
+				*m = models.ImportMapping{
+					Delimiter:        "tab",
+					SkipRows:         1,
+					DateColumn:       "1",
+					DateFormat:       "2006-01-02",
+					AmountSign:       models.SignSeparateColumns,
+					IncomeColumn:     "2",
+					ExpenseColumn:    "3",
+					DecimalSeparator: ".",
+					TitleColumn:      "4",
+				}
+			},
+			file: "Выписка за январь\n" +
+				"2024-01-10\t\t300\tКафе\n" +
+				"2024-01-11\t1000\t\tПеревод\n" +
+				"2024-01-12\t1\t2\tОба\n" +
+				"2024-01-13\t\t\tПусто\n",
+			want: []Record{
+				{Line: 2, Date: at(10, 0, 0), Amount: 30000, Type: models.Expense, Title: "Кафе"},
+				{Line: 3, Date: at(11, 0, 0), Amount: 100000, Type: models.Income, Title: "Перевод"},
+				{Line: 4, Date: at(12, 0, 0), Title: "Оба", Errors: []string{"Указаны и приход, и расход"}},
+				{Line: 5, Date: at(13, 0, 0), Title: "Пусто", Errors: []string{"Не указана сумма"}},
+			},
+		},
+		{
+			name:    "колонки нет в заголовке",
+			file:    "Date;Amount;Title\n",
+			wantErr: true,
+		},
+		{
+			name:    "неверное описание формата",
+			mapping: func(m *models.ImportMapping) { m.DecimalSeparator = "" },
+			file:    "Дата;Сумма;Валюта;Описание;Категория\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := base
+			if tt.mapping != nil {
+				tt.mapping(&m)
+			}
+			got, err := ParseCSV(strings.NewReader(tt.file), m)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseCSV() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCSV() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCSV() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in, decimal string
+		want        money.Amount
+		wantErr     bool
+	}{
+		{in: "1,250.50", decimal: ".", want: 125050},
+		{in: "1.250,50", decimal: ",", want: 125050},
+		{in: "−99,90 ₽", decimal: ",", want: -9990},
+		{in: "$ 12", decimal: ".", want: 1200},
+		{in: "", decimal: ".", wantErr: true},
+		{in: "RUB", decimal: ".", wantErr: true},
+		{in: "1.234", decimal: ".", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseAmount(tt.in, tt.decimal)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseAmount(%q, %q) = %s, want error", tt.in, tt.decimal, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseAmount(%q, %q) = %s, %v, want %s", tt.in, tt.decimal, got, err, tt.want)
+		}
+	}
+}
+
+func TestDateLayout(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"DD.MM.YYYY", "02.01.2006"},
+		{"DD.MM.YY HH:mm:ss", "02.01.06 15:04:05"},
+		{"YYYY-MM-DD", "2006-01-02"},
+		{"2006-01-02T15:04", "2006-01-02T15:04"},
+	}
+	for _, tt := range tests {
+		if got := DateLayout(tt.in); got != tt.want {
+			t.Errorf("DateLayout(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"statement.OFX", FormatOFX},
+		{"bank.qfx", FormatOFX},
+		{"export.qif", FormatQIF},
+		{"main.journal", FormatLedger},
+		{"book.bean", FormatBeancount},
+		{"выписка.csv", FormatCSV},
+		{"без расширения", FormatCSV},
+	}
+	for _, tt := range tests {
+		if got := DetectFormat(tt.in); got != tt.want {
+			t.Errorf("DetectFormat(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package importer
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/budgets"
+	"github.com/Anabol1ks/pers-fin-m/internal/httputil"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// maxFileSize ограничивает размер загружаемой выписки.
+const maxFileSize = 10 << 20
+
+type Handler struct {
+	store repository.Store
+}
+
+func NewHandler(store repository.Store) *Handler {
+	return &Handler{store: store}
+}
+
+// MappingInput описывает формат CSV-выписки. Колонки задаются названием
+// из заголовка или номером, начиная с 1.
+type MappingInput struct {
+	Name              string `json:"name" binding:"max=100"`
+	Delimiter         string `json:"delimiter"` // по умолчанию ","; для табуляции \t
+	Encoding          string `json:"encoding"`  // utf-8 (по умолчанию) или cp1251
+	SkipRows          int    `json:"skipRows"`  // строки перед заголовком
+	HasHeader         *bool  `json:"hasHeader"` // по умолчанию true
+	DateColumn        string `json:"dateColumn" binding:"required"`
+	DateFormat        string `json:"dateFormat" binding:"required"` // например DD.MM.YYYY
+	AmountColumn      string `json:"amountColumn"`
+	IncomeColumn      string `json:"incomeColumn"`                                                 // для amountSign=separate
+	ExpenseColumn     string `json:"expenseColumn"`                                                // для amountSign=separate
+	AmountSign        string `json:"amountSign" enums:"negative_expense,negative_income,separate"` // по умолчанию negative_expense
+	DecimalSeparator  string `json:"decimalSeparator"`                                             // по умолчанию "."
+	TitleColumn       string `json:"titleColumn" binding:"required"`
+	DescriptionColumn string `json:"descriptionColumn"`
+	CategoryColumn    string `json:"categoryColumn"`
+	CurrencyColumn    string `json:"currencyColumn"`
+}
+
+// mapping превращает описание формата в модель, подставляя значения по умолчанию.
+func (in MappingInput) mapping(userID uint) models.ImportMapping {
+	m := models.ImportMapping{
+		UserID:            userID,
+		Name:              in.Name,
+		Delimiter:         in.Delimiter,
+		Encoding:          in.Encoding,
+		SkipRows:          in.SkipRows,
+		HasHeader:         in.HasHeader == nil || *in.HasHeader,
+		DateColumn:        in.DateColumn,
+		DateFormat:        in.DateFormat,
+		AmountColumn:      in.AmountColumn,
+		IncomeColumn:      in.IncomeColumn,
+		ExpenseColumn:     in.ExpenseColumn,
+		AmountSign:        in.AmountSign,
+		DecimalSeparator:  in.DecimalSeparator,
+		TitleColumn:       in.TitleColumn,
+		DescriptionColumn: in.DescriptionColumn,
+		CategoryColumn:    in.CategoryColumn,
+		CurrencyColumn:    in.CurrencyColumn,
+	}
+	if m.Delimiter == "" {
+		m.Delimiter = ","
+	}
+	if m.Encoding == "" {
+		m.Encoding = "utf-8"
+	}
+	if m.AmountSign == "" {
+		m.AmountSign = models.SignNegativeExpense
+	}
+	if m.DecimalSeparator == "" {
+		m.DecimalSeparator = "."
+	}
+	return m
+}
+
+// @Security BearerAuth
+// ListMappings godoc
+// @Summary Получить сохранённые форматы выписок
+// @Description Получить описания форматов CSV-выписок, сохранённые пользователем
+// @Tags Import
+// @Produce json
+// @Success 200 {array} models.ImportMapping
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении форматов"
+// @Router /transactions/import/mappings [get]
+func (h *Handler) ListMappings(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	mappings, err := h.store.ImportMappings().ListForUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении форматов"})
+		return
+	}
+
+	c.JSON(http.StatusOK, mappings)
+}
+
+// @Security BearerAuth
+// CreateMapping godoc
+// @Summary Сохранить формат выписки
+// @Description Сохраняет описание формата CSV-выписки для повторного импорта
+// @Tags Import
+// @Accept json
+// @Produce json
+// @Param input body MappingInput true "Формат выписки"
+// @Success 201 {object} models.ImportMapping
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 500 {object} response.ErrorResponse "Ошибка сохранения формата"
+// @Router /transactions/import/mappings [post]
+func (h *Handler) CreateMapping(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input MappingInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Укажите название формата"})
+		return
+	}
+
+	mapping := input.mapping(userID)
+	if err := ValidateMapping(mapping); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.store.ImportMappings().Create(&mapping); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении формата"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, mapping)
+}
+
+// @Security BearerAuth
+// UpdateMapping godoc
+// @Summary Обновить формат выписки
+// @Description Заменяет сохранённое описание формата CSV-выписки целиком
+// @Tags Import
+// @Accept json
+// @Produce json
+// @Param id path string true "ID формата"
+// @Param input body MappingInput true "Формат выписки"
+// @Success 200 {object} models.ImportMapping
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 404 {object} response.ErrorResponse "Формат не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка сохранения формата"
+// @Router /transactions/import/mappings/{id} [put]
+func (h *Handler) UpdateMapping(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input MappingInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	existing, ok := h.ownedMapping(c)
+	if !ok {
+		return
+	}
+
+	mapping := input.mapping(userID)
+	mapping.Model = existing.Model
+	if mapping.Name == "" {
+		mapping.Name = existing.Name
+	}
+	if err := ValidateMapping(mapping); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.store.ImportMappings().Save(&mapping); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении формата"})
+		return
+	}
+
+	c.JSON(http.StatusOK, mapping)
+}
+
+// @Security BearerAuth
+// DeleteMapping godoc
+// @Summary Удалить формат выписки
+// @Tags Import
+// @Produce json
+// @Param id path string true "ID формата"
+// @Success 200 {object} response.SuccessResponse "Формат удалён"
+// @Failure 404 {object} response.ErrorResponse "Формат не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка удаления формата"
+// @Router /transactions/import/mappings/{id} [delete]
+func (h *Handler) DeleteMapping(c *gin.Context) {
+	mapping, ok := h.ownedMapping(c)
+	if !ok {
+		return
+	}
+
+	if err := h.store.ImportMappings().Delete(mapping); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении формата"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Формат удалён"})
+}
+
+// @Security BearerAuth
+// ImportTransactions godoc
+// @Summary Импорт выписки CSV, OFX, QIF или журнала ledger/beancount
+// @Description Разбирает выписку и возвращает предпросмотр с ошибками по строкам, ничего не сохраняя.
+// @Description С commit=true сохраняет все операции одной транзакцией и один раз обновляет баланс счёта.
+// @Description Если в файле есть строки с ошибками, импорт отклоняется, пока не передан skipInvalid=true.
+// @Description Операции OFX с уже импортированным на этот счёт FITID помечаются как дубли и пропускаются.
+// @Description Журналы ledger, hledger и beancount, в том числе выгруженные из /transactions/export: счета Assets и Liabilities ищутся по названию среди счетов пользователя,
+// @Description категории берутся из счетов Income и Expenses, проводки между двумя счетами становятся переводами, строки в BONUS — бонусами. Дубли ищутся по коду проводки или метаданным id.
+// @Description Для CSV формат колонок задаётся сохранённым mappingId или JSON-описанием в поле mapping (его можно сохранить, указав saveAs)
+// @Tags Import
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Файл выписки"
+// @Param format formData string false "Формат файла, по умолчанию по расширению" Enums(csv, ofx, qif, ledger, beancount)
+// @Param account formData int false "ID счёта, по умолчанию основной"
+// @Param mappingId formData int false "ID сохранённого формата CSV"
+// @Param mapping formData string false "Формат CSV в JSON, как в POST /transactions/import/mappings"
+// @Param saveAs formData string false "Сохранить формат из mapping под этим названием"
+// @Param encoding formData string false "Кодировка QIF: utf-8 (по умолчанию) или cp1251"
+// @Param dateFormat formData string false "Формат дат QIF, по умолчанию MM/DD/YYYY"
+// @Param commit formData bool false "Сохранить операции"
+// @Param skipInvalid formData bool false "Пропустить строки с ошибками"
+// @Success 200 {object} Preview "Предпросмотр"
+// @Success 201 {object} Preview "Операции импортированы"
+// @Failure 400 {object} response.ErrorResponse "Ошибка в файле или параметрах"
+// @Failure 404 {object} response.ErrorResponse "Формат не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка импорта"
+// @Router /transactions/import [post]
+func (h *Handler) ImportTransactions(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Загрузите файл выписки в поле file"})
+		return
+	}
+	defer file.Close()
+	if header.Size > maxFileSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Файл больше 10 МБ"})
+		return
+	}
+
+	opts := Options{
+		Format:     c.PostForm("format"),
+		Encoding:   c.PostForm("encoding"),
+		DateFormat: c.PostForm("dateFormat"),
+	}
+	if opts.Format == "" {
+		opts.Format = DetectFormat(header.Filename)
+	}
+	if opts.Format == FormatCSV {
+		mapping, ok := h.requestMapping(c, userID)
+		if !ok {
+			return
+		}
+		opts.Mapping = mapping
+	}
+
+	records, err := Parse(file, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, errMsg := ImportAccount(h.store, userID, c.PostForm("account"))
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	preview, err := Prepare(h.store, userID, account, records)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при проверке выписки"})
+		return
+	}
+
+	if c.PostForm("commit") != "true" {
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+	if preview.Invalid > 0 && c.PostForm("skipInvalid") != "true" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "В выписке есть строки с ошибками", "preview": preview})
+		return
+	}
+	if preview.Valid == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Нет новых операций для импорта"})
+		return
+	}
+
+	transactions := preview.Transactions()
+	if err := Commit(h.store, userID, transactions); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при импорте операций"})
+		return
+	}
+	budgets.Notify(h.store, userID, transactions...)
+
+	preview.Committed = true
+	c.JSON(http.StatusCreated, preview)
+}
+
+// requestMapping берёт формат из mappingId или из JSON в поле mapping.
+func (h *Handler) requestMapping(c *gin.Context, userID uint) (*models.ImportMapping, bool) {
+	if id := c.PostForm("mappingId"); id != "" {
+		mappingID, err := strconv.ParseUint(id, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Формат не найден"})
+			return nil, false
+		}
+		mapping, err := h.store.ImportMappings().GetOwned(uint(mappingID), userID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Формат не найден"})
+			return nil, false
+		}
+		return mapping, true
+	}
+
+	raw := c.PostForm("mapping")
+	if raw == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Укажите mappingId или mapping"})
+		return nil, false
+	}
+	var input MappingInput
+	if err := json.Unmarshal([]byte(raw), &input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный JSON в поле mapping"})
+		return nil, false
+	}
+	mapping := input.mapping(userID)
+	if err := ValidateMapping(mapping); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	if name := c.PostForm("saveAs"); name != "" {
+		mapping.Name = name
+		if err := h.store.ImportMappings().Create(&mapping); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении формата"})
+			return nil, false
+		}
+	}
+	return &mapping, true
+}
+
+// ownedMapping загружает формат из параметра пути и отвечает 404, если его нет.
+func (h *Handler) ownedMapping(c *gin.Context) (*models.ImportMapping, bool) {
+	userID := c.GetUint("userID")
+
+	mappingID, err := httputil.ParamID(c, "id")
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Формат не найден"})
+		return nil, false
+	}
+
+	mapping, err := h.store.ImportMappings().GetOwned(mappingID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Формат не найден"})
+		return nil, false
+	}
+	return mapping, true
+}
@@ -0,0 +1,335 @@
+// Package importer загружает историю операций из банковских выписок.
+//
+// Импорт идёт в два шага: парсер формата превращает файл в записи Record,
+// не обращаясь к хранилищу, затем Prepare привязывает их к счёту и категориям
+// пользователя и возвращает предпросмотр. Commit сохраняет подготовленные
+// транзакции одной транзакцией БД. Пакет используется и HTTP-обработчиком,
+// и консольной командой.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/journal"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+// MaxRows ограничивает количество операций в одном файле.
+const MaxRows = 10000
+
+// titleLimit — длина колонки transactions.title.
+const titleLimit = 100
+
+// Форматы файлов выписок.
+const (
+	FormatCSV = "csv"
+	FormatOFX = "ofx"
+	FormatQIF = "qif"
+
+	// Журналы учёта в простом тексте читаются одним разборщиком
+	FormatLedger    = "ledger"
+	FormatBeancount = "beancount"
+)
+
+// Options — параметры разбора файла.
+type Options struct {
+	Format     string
+	Mapping    *models.ImportMapping // обязателен для CSV
+	Encoding   string                // для QIF; в OFX кодировка берётся из заголовка
+	DateFormat string                // для QIF
+}
+
+// Parse разбирает файл выписки в указанном формате.
+func Parse(r io.Reader, opts Options) ([]Record, error) {
+	switch opts.Format {
+	case FormatCSV:
+		if opts.Mapping == nil {
+			return nil, errors.New("Для CSV нужно указать формат колонок")
+		}
+		return ParseCSV(r, *opts.Mapping)
+	case FormatOFX:
+		return ParseOFX(r)
+	case FormatQIF:
+		return ParseQIF(r, opts.Encoding, opts.DateFormat)
+	case FormatLedger, FormatBeancount:
+		return ParseJournal(r)
+	}
+	return nil, fmt.Errorf("Неизвестный формат файла %q, доступны csv, ofx, qif, ledger и beancount", opts.Format)
+}
+
+// DetectFormat определяет формат по расширению файла. По умолчанию — CSV.
+func DetectFormat(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ofx", ".qfx":
+		return FormatOFX
+	case ".qif":
+		return FormatQIF
+	case ".ledger", ".journal", ".hledger", ".dat":
+		return FormatLedger
+	case ".beancount", ".bean":
+		return FormatBeancount
+	}
+	return FormatCSV
+}
+
+// Record — операция из файла до привязки к счёту и категориям.
+type Record struct {
+	Line        int
+	Date        time.Time
+	Amount      money.Amount // всегда положительная, знак задаёт Type
+	Type        models.TransactionType
+	Title       string
+	Description string
+	Category    string // название категории из файла
+	Currency    string
+	ExternalID  string // FITID из OFX или код проводки журнала
+	Errors      []string
+
+	// Поля журналов: счета указываются по названию, пустой Account — счёт импорта
+	Account         string
+	CounterAccount  string // счёт зачисления перевода
+	CounterAmount   money.Amount
+	CounterCurrency string
+	Fee             money.Amount
+	BonusChange     money.Amount
+	BonusType       models.TransactionType
+}
+
+func (r *Record) fail(msg string) {
+	r.Errors = append(r.Errors, msg)
+}
+
+// Row — строка предпросмотра: готовая транзакция, ошибки разбора или отметка о дубле.
+type Row struct {
+	Line        int                 `json:"line"`
+	Transaction *models.Transaction `json:"transaction,omitempty"`
+	Errors      []string            `json:"errors,omitempty"`
+	Duplicate   bool                `json:"duplicate,omitempty"` // операция уже импортирована, будет пропущена
+}
+
+// Preview — результат проверки файла.
+type Preview struct {
+	Account   uint         `json:"account"`
+	Rows      []Row        `json:"rows"`
+	Valid     int          `json:"valid"`
+	Invalid   int          `json:"invalid"`
+	Duplicate int          `json:"duplicate"`
+	Income    money.Amount `json:"income" swaggertype:"number"`  // сумма корректных доходов
+	Expense   money.Amount `json:"expense" swaggertype:"number"` // сумма корректных расходов
+	Committed bool         `json:"committed"`
+}
+
+// Transactions возвращает транзакции корректных строк.
+func (p *Preview) Transactions() []models.Transaction {
+	transactions := make([]models.Transaction, 0, p.Valid)
+	for _, row := range p.Rows {
+		if row.Transaction != nil {
+			transactions = append(transactions, *row.Transaction)
+		}
+	}
+	return transactions
+}
+
+// Prepare проверяет записи и превращает корректные в транзакции счёта.
+// Категории ищутся по названию среди категорий пользователя и встроенных,
+// не найденные попадают в «Без категории». Записи журналов с названием
+// счёта привязываются к счёту пользователя с таким же названием.
+func Prepare(store repository.Store, userID uint, account *models.Account, records []Record) (*Preview, error) {
+	categories, err := store.Categories().ListForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	uncategorized, err := store.Categories().FindDefaultBySlug(models.UncategorizedSlug)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := store.Accounts().ListForUser(userID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := map[string]uint{}
+	for _, category := range categories {
+		for _, name := range category.Translations {
+			byName[strings.ToLower(name)] = category.ID
+		}
+	}
+	// Собственные названия приоритетнее переводов
+	for _, category := range categories {
+		byName[strings.ToLower(category.Name)] = category.ID
+	}
+	accountsByName := map[string]*models.Account{}
+	for i := range accounts {
+		accountsByName[journal.Key(accounts[i].Name)] = &accounts[i]
+	}
+	resolve := func(record *Record, name string) *models.Account {
+		if name == "" {
+			return account
+		}
+		found, ok := accountsByName[journal.Key(name)]
+		switch {
+		case !ok:
+			record.fail("Счёт «" + name + "» не найден")
+		case found.Archived:
+			record.fail("Счёт «" + found.Name + "» находится в архиве")
+		}
+		return found
+	}
+
+	// Счета записей определяются заранее, чтобы искать дубли по каждому счёту
+	targets := make([]*models.Account, len(records))
+	externalIDs := map[uint][]string{}
+	for i := range records {
+		targets[i] = resolve(&records[i], records[i].Account)
+		if targets[i] != nil && records[i].ExternalID != "" {
+			externalIDs[targets[i].ID] = append(externalIDs[targets[i].ID], records[i].ExternalID)
+		}
+	}
+	seen := map[uint]map[string]bool{}
+	for accountID, ids := range externalIDs {
+		if seen[accountID], err = store.Transactions().ExistingExternalIDs(accountID, ids); err != nil {
+			return nil, err
+		}
+	}
+
+	preview := &Preview{Account: account.ID, Rows: make([]Row, 0, len(records))}
+	for i, record := range records {
+		target := targets[i]
+		var counter *models.Account
+		if record.Type == models.Transfer {
+			counter = resolve(&record, record.CounterAccount)
+			if counter != nil && target != nil && counter.ID == target.ID {
+				record.fail("Счета списания и зачисления совпадают")
+			}
+			if counter != nil && record.CounterCurrency != "" && !strings.EqualFold(record.CounterCurrency, counter.Currency) {
+				record.fail("Валюта зачисления " + record.CounterCurrency + " не совпадает с валютой счёта " + counter.Currency)
+			}
+		}
+		if target != nil && record.Currency != "" && !strings.EqualFold(record.Currency, target.Currency) {
+			record.fail("Валюта операции " + record.Currency + " не совпадает с валютой счёта " + target.Currency)
+		}
+		if len(record.Errors) > 0 {
+			preview.Invalid++
+			preview.Rows = append(preview.Rows, Row{Line: record.Line, Errors: record.Errors})
+			continue
+		}
+		// Дубли ищутся и среди уже сохранённых операций, и внутри самого файла
+		if record.ExternalID != "" {
+			if seen[target.ID][record.ExternalID] {
+				preview.Duplicate++
+				preview.Rows = append(preview.Rows, Row{Line: record.Line, Duplicate: true})
+				continue
+			}
+			if seen[target.ID] == nil {
+				seen[target.ID] = map[string]bool{}
+			}
+			seen[target.ID][record.ExternalID] = true
+		}
+
+		category, ok := byName[strings.ToLower(strings.TrimSpace(record.Category))]
+		if !ok || record.Type == models.Transfer {
+			category = uncategorized.ID
+		}
+
+		transaction := &models.Transaction{
+			UserID:      userID,
+			AccountID:   target.ID,
+			Amount:      record.Amount,
+			Currency:    target.Currency,
+			Date:        record.Date,
+			Title:       truncate(record.Title, titleLimit),
+			Description: record.Description,
+			Category:    category,
+			Type:        record.Type,
+			BonusChange: record.BonusChange,
+			BonusType:   record.BonusType,
+		}
+		if counter != nil {
+			transaction.CounterAccountID = &counter.ID
+			transaction.CounterAmount = record.CounterAmount
+			transaction.Fee = record.Fee
+		}
+		if record.ExternalID != "" {
+			externalID := record.ExternalID
+			transaction.ExternalID = &externalID
+		}
+		preview.Valid++
+		switch record.Type {
+		case models.Income:
+			preview.Income += record.Amount
+		case models.Expense:
+			preview.Expense += record.Amount
+		}
+		preview.Rows = append(preview.Rows, Row{Line: record.Line, Transaction: transaction})
+	}
+	return preview, nil
+}
+
+// ImportAccount возвращает счёт для импорта: с указанным ID или основной,
+// если raw пуст. Вторым значением возвращается текст ошибки.
+func ImportAccount(store repository.Store, userID uint, raw string) (*models.Account, string) {
+	var account *models.Account
+	var err error
+	if raw == "" {
+		account, err = store.Accounts().Primary(userID)
+	} else {
+		var id uint64
+		if id, err = strconv.ParseUint(raw, 10, 32); err == nil {
+			account, err = store.Accounts().GetOwned(uint(id), userID)
+		}
+	}
+	if err != nil {
+		return nil, "Указан неверный счёт"
+	}
+	if account.Archived {
+		return nil, "Счёт находится в архиве"
+	}
+	return account, ""
+}
+
+// Commit сохраняет транзакции одной транзакцией БД. Балансы счетов и бонусы
+// изменяются один раз на всю пачку, а не на каждую операцию.
+func Commit(store repository.Store, userID uint, transactions []models.Transaction) error {
+	if len(transactions) == 0 {
+		return errors.New("Нет операций для импорта")
+	}
+
+	return store.Atomic(func(s repository.Store) error {
+		balances := map[uint]money.Amount{}
+		var bonus money.Amount
+		for i := range transactions {
+			t := &transactions[i]
+			if err := s.Transactions().Create(t); err != nil {
+				return err
+			}
+			balances[t.AccountID] += t.BalanceEffect()
+			if t.CounterAccountID != nil {
+				balances[*t.CounterAccountID] += t.CounterEffect()
+			}
+			bonus += t.BonusEffect()
+		}
+
+		for accountID, delta := range balances {
+			if err := s.Accounts().AdjustBalance(accountID, delta); err != nil {
+				return err
+			}
+		}
+		return s.Users().AdjustBonus(userID, bonus)
+	})
+}
+
+func truncate(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit])
+}
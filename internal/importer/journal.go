@@ -0,0 +1,190 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/journal"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+)
+
+// ParseJournal разбирает журнал ledger, hledger или beancount. Счета Assets
+// и Liabilities сопоставляются со счетами пользователя по названию, категория
+// берётся из первой части имени счёта Income или Expenses. Проводка с двумя
+// счетами пользователя становится переводом, строки Expenses в ней — комиссией.
+// Строки в товаре BONUS на счетах Assets меняют бонусы. Проводки с Equity
+// (начальные остатки) пропускаются. Код проводки или метаданные id служат
+// идентификатором для поиска дублей.
+func ParseJournal(r io.Reader) ([]Record, error) {
+	decoded, err := decode(r, "")
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := journal.Parse(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, t := range transactions {
+		record, ok := journalRecord(t)
+		if !ok {
+			continue
+		}
+		if len(records) == MaxRows {
+			return nil, fmt.Errorf("В файле больше %d операций", MaxRows)
+		}
+		records = append(records, record)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("В журнале нет проводок")
+	}
+	return records, nil
+}
+
+// journalRecord превращает проводку в запись. Второе значение false
+// для проводок, которые не импортируются.
+func journalRecord(t journal.Transaction) (Record, bool) {
+	record := Record{
+		Line:        t.Line,
+		Date:        t.Date,
+		Title:       t.Title,
+		Description: t.Description,
+		ExternalID:  t.Code,
+		Errors:      t.Errors,
+	}
+	if record.Title == "" {
+		record.Title, record.Description = record.Description, ""
+	}
+	if record.Title == "" {
+		record.fail("Пустое название операции")
+	}
+	if len(t.Errors) > 0 {
+		return record, true
+	}
+
+	postings, err := fillElided(t.Postings)
+	if err != nil {
+		record.fail(err.Error())
+		return record, true
+	}
+
+	var assets, categories, fees []journal.Posting
+	for _, p := range postings {
+		root, _ := journal.Root(p.Account)
+		switch strings.ToLower(root) {
+		case "assets", "liabilities":
+			if p.Commodity == journal.BonusCommodity {
+				record.BonusChange += p.Amount
+			} else {
+				assets = append(assets, p)
+			}
+		case "income", "revenue", "revenues":
+			if p.Commodity != journal.BonusCommodity {
+				categories = append(categories, p)
+			}
+		case "expenses", "expense":
+			if p.Commodity != journal.BonusCommodity {
+				categories = append(categories, p)
+				fees = append(fees, p)
+			}
+		case "equity":
+			return record, false
+		default:
+			record.fail(fmt.Sprintf("Неизвестный корневой счёт %q", root))
+			return record, true
+		}
+	}
+
+	switch {
+	case record.BonusChange < 0:
+		record.BonusChange, record.BonusType = -record.BonusChange, models.Expense
+	case record.BonusChange > 0:
+		record.BonusType = models.Income
+	}
+
+	switch len(assets) {
+	case 0:
+		record.fail("В проводке нет счёта Assets или Liabilities")
+	case 1:
+		asset := assets[0]
+		_, record.Account = journal.Root(asset.Account)
+		record.Currency = asset.Commodity
+		if len(categories) > 0 {
+			_, name := journal.Root(categories[0].Account)
+			record.Category, _, _ = strings.Cut(name, ":")
+		}
+		switch {
+		case asset.Amount > 0:
+			record.Amount, record.Type = asset.Amount, models.Income
+		case asset.Amount < 0:
+			record.Amount, record.Type = -asset.Amount, models.Expense
+		default:
+			record.fail("Нулевая сумма")
+		}
+	case 2:
+		from, to := assets[0], assets[1]
+		if from.Amount > 0 {
+			from, to = to, from
+		}
+		if from.Amount >= 0 || to.Amount <= 0 {
+			record.fail("В переводе должны быть списание и зачисление")
+			break
+		}
+		for _, fee := range fees {
+			if fee.Commodity == from.Commodity {
+				record.Fee += fee.Amount
+			}
+		}
+		record.Type = models.Transfer
+		record.Amount = -from.Amount - record.Fee
+		record.Currency = from.Commodity
+		_, record.Account = journal.Root(from.Account)
+		_, record.CounterAccount = journal.Root(to.Account)
+		record.CounterAmount, record.CounterCurrency = to.Amount, to.Commodity
+		if record.Amount <= 0 || record.Fee < 0 {
+			record.fail("Неверная сумма перевода")
+		}
+	default:
+		record.fail("Проводки с тремя и более счетами пользователя не поддерживаются")
+	}
+	return record, true
+}
+
+// fillElided вычисляет суммы строк без суммы. Такая строка может быть одна,
+// и остальные строки должны балансироваться в одной валюте.
+func fillElided(postings []journal.Posting) ([]journal.Posting, error) {
+	elided := -1
+	sums := map[string]money.Amount{}
+	for i, p := range postings {
+		if p.Elided {
+			if elided >= 0 {
+				return nil, errors.New("Сумма не указана больше чем в одной строке")
+			}
+			elided = i
+			continue
+		}
+		amount, commodity := p.Weight()
+		sums[commodity] += amount
+	}
+	if elided < 0 {
+		return postings, nil
+	}
+
+	var commodity string
+	for c, sum := range sums {
+		if sum == 0 {
+			continue
+		}
+		if commodity != "" {
+			return nil, errors.New("Нельзя вычислить пропущенную сумму в проводке с несколькими валютами")
+		}
+		commodity = c
+	}
+	filled := append([]journal.Posting(nil), postings...)
+	filled[elided].Amount, filled[elided].Commodity, filled[elided].Elided = -sums[commodity], commodity, false
+	return filled, nil
+}
@@ -0,0 +1,184 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// ParseOFX разбирает выписку OFX версии 1.x (SGML, теги без закрывающих)
+// и 2.x (XML). Оба варианта читаются одним разбором тегов: значение листового
+// тега — текст до следующего «<». Сумма со знаком определяет тип операции.
+func ParseOFX(r io.Reader) ([]Record, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFileSize {
+		return nil, errors.New("Файл больше 10 МБ")
+	}
+
+	start := bytes.Index(bytes.ToUpper(data), []byte("<OFX>"))
+	if start < 0 {
+		return nil, errors.New("Файл не похож на OFX: нет тега <OFX>")
+	}
+	body := string(data[start:])
+	if ofxCP1251(data[:start]) {
+		if body, err = charmap.Windows1251.NewDecoder().String(body); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		records  []Record
+		currency string
+		current  map[string]string // поля текущей STMTTRN
+		path     []string          // открытые агрегаты внутри STMTTRN
+		line     = 1 + bytes.Count(data[:start], []byte("\n"))
+	)
+	for body != "" {
+		lt := strings.IndexByte(body, '<')
+		if lt < 0 {
+			break
+		}
+		line += strings.Count(body[:lt], "\n")
+		gt := strings.IndexByte(body[lt:], '>')
+		if gt < 0 {
+			break
+		}
+		tag := strings.ToUpper(strings.TrimSpace(body[lt+1 : lt+gt]))
+		body = body[lt+gt+1:]
+
+		if strings.HasPrefix(tag, "/") {
+			name := tag[1:]
+			switch {
+			case name == "STMTTRN" && current != nil:
+				record := ofxRecord(current, currency)
+				record.Line, _ = strconv.Atoi(current["_LINE"])
+				records = append(records, record)
+				current, path = nil, nil
+			case current != nil && len(path) > 0 && path[len(path)-1] == name:
+				path = path[:len(path)-1]
+			}
+			continue
+		}
+		if strings.HasPrefix(tag, "?") || strings.HasPrefix(tag, "!") {
+			continue
+		}
+
+		value := body
+		if next := strings.IndexByte(body, '<'); next >= 0 {
+			value = body[:next]
+		}
+		value = html.UnescapeString(strings.TrimSpace(value))
+
+		switch {
+		case tag == "STMTTRN":
+			if len(records) == MaxRows {
+				return nil, fmt.Errorf("В файле больше %d операций", MaxRows)
+			}
+			current = map[string]string{"_LINE": strconv.Itoa(line)}
+		case tag == "CURDEF" && current == nil:
+			currency = strings.ToUpper(value)
+		case current != nil && ofxAggregates[tag]:
+			path = append(path, tag)
+		case current != nil:
+			key := tag
+			if len(path) > 0 {
+				key = path[len(path)-1] + "." + tag
+			}
+			current[key] = value
+		}
+	}
+	return records, nil
+}
+
+// ofxAggregates — составные теги внутри STMTTRN. Их поля сохраняются
+// с префиксом, например PAYEE.NAME.
+var ofxAggregates = map[string]bool{
+	"PAYEE": true, "CURRENCY": true, "ORIGCURRENCY": true, "BANKACCTTO": true, "CCACCTTO": true,
+}
+
+func ofxRecord(fields map[string]string, currency string) Record {
+	record := Record{
+		Title:       fields["NAME"],
+		Description: fields["MEMO"],
+		Currency:    currency,
+		ExternalID:  fields["FITID"],
+	}
+	if record.Title == "" {
+		record.Title = fields["PAYEE.NAME"]
+	}
+	if record.Title == "" {
+		record.Title, record.Description = record.Description, ""
+	}
+	if record.Title == record.Description {
+		record.Description = ""
+	}
+	if record.Title == "" {
+		record.fail("Пустое название операции")
+	}
+	if record.ExternalID == "" {
+		record.fail("Нет идентификатора операции FITID")
+	}
+
+	date, err := parseOFXDate(fields["DTPOSTED"])
+	if err != nil {
+		record.fail(fmt.Sprintf("Неверная дата %q", fields["DTPOSTED"]))
+	}
+	record.Date = date
+
+	amount, err := parseAmount(fields["TRNAMT"], ".")
+	switch {
+	case err != nil:
+		record.fail(fmt.Sprintf("Неверная сумма %q", fields["TRNAMT"]))
+	case amount == 0:
+		record.fail("Нулевая сумма")
+	case amount < 0:
+		record.Amount, record.Type = -amount, models.Expense
+	default:
+		record.Amount, record.Type = amount, models.Income
+	}
+	return record
+}
+
+var ofxDate = regexp.MustCompile(`^(\d{8})(\d{6})?(?:\.\d+)?(?:\[([+-]?\d+(?:\.\d+)?)(?::[^\]]*)?\])?$`)
+
+// parseOFXDate разбирает дату вида 20240131120000.000[+3:MSK].
+// Без часового пояса время считается UTC, как требует спецификация OFX.
+func parseOFXDate(s string) (time.Time, error) {
+	m := ofxDate.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return time.Time{}, errors.New("неверная дата")
+	}
+
+	clock := m[2]
+	if clock == "" {
+		clock = "000000"
+	}
+	loc := time.UTC
+	if m[3] != "" {
+		hours, err := strconv.ParseFloat(m[3], 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		loc = time.FixedZone("", int(hours*3600))
+	}
+	return time.ParseInLocation("20060102150405", m[1]+clock, loc)
+}
+
+// ofxCP1251 определяет кодировку по заголовку: CHARSET:1251 в OFX 1.x
+// или encoding="windows-1251" в XML-объявлении OFX 2.x.
+func ofxCP1251(header []byte) bool {
+	h := strings.ToUpper(string(header))
+	return strings.Contains(h, "CHARSET:1251") || strings.Contains(h, "WINDOWS-1251")
+}
@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestParseOFX(t *testing.T) {
+	cp1251 := func(s string) string {
+		encoded, err := charmap.Windows1251.NewEncoder().String(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return encoded
+	}
+	msk := time.FixedZone("", 3*3600)
+
+	tests := []struct {
+		name    string
+		file    string
+		want    []Record
+		wantErr bool
+	}{
+		{
+			name: "SGML в cp1251",
+			file: cp1251("OFXHEADER:100\nDATA:OFXSGML\nCHARSET:1251\n\n" +
+				"<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>rub\n" +
+				"<BANKTRANLIST>\n" +
+				"<STMTTRN>\n<TRNTYPE>DEBIT\n<DTPOSTED>20240131120000.000[+3:MSK]\n<TRNAMT>-1250.50\n<FITID>A1\n<NAME>Пятёрочка\n<MEMO>Пятёрочка\n</STMTTRN>\n" +
+				"<STMTTRN>\n<TRNTYPE>CREDIT\n<DTPOSTED>20240201\n<TRNAMT>5000\n<FITID>A2\n<PAYEE><NAME>ООО Ромашка</PAYEE>\n<MEMO>Зарплата &amp; премия\n</STMTTRN>\n" +
+				"</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>\n"),
+			want: []Record{
+				{
+					Line:       7,
+					Date:       time.Date(2024, 1, 31, 12, 0, 0, 0, msk),
+					Amount:     125050,
+					Type:       models.Expense,
+					Title:      "Пятёрочка",
+					Currency:   "RUB",
+					ExternalID: "A1",
+				},
+				{
+					Line:        15,
+					Date:        time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+					Amount:      500000,
+					Type:        models.Income,
+					Title:       "ООО Ромашка",
+					Description: "Зарплата & премия",
+					Currency:    "RUB",
+					ExternalID:  "A2",
+				},
+			},
+		},
+		{
+			name: "XML с ошибками в операции",
+			file: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<OFX><CURDEF>USD</CURDEF><STMTTRN><DTPOSTED>2024-01-01</DTPOSTED><TRNAMT>0</TRNAMT><MEMO>Кофе</MEMO></STMTTRN></OFX>`,
+			want: []Record{
+				{
+					Line:     2,
+					Title:    "Кофе",
+					Currency: "USD",
+					Errors:   []string{"Нет идентификатора операции FITID", `Неверная дата "2024-01-01"`, "Нулевая сумма"},
+				},
+			},
+		},
+		{
+			name:    "не OFX",
+			file:    "date,amount\n2024-01-01,100\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOFX(strings.NewReader(tt.file))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseOFX() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseOFX() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseOFX() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOFXDate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "20240131", want: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{in: "20240131235959", want: time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)},
+		{in: "20240131120000.000[-5:EST]", want: time.Date(2024, 1, 31, 17, 0, 0, 0, time.UTC)},
+		{in: "20240131120000[5.5]", want: time.Date(2024, 1, 31, 6, 30, 0, 0, time.UTC)},
+		{in: "2024-01-31", wantErr: true},
+		{in: "20241331", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseOFXDate(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseOFXDate(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseOFXDate(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
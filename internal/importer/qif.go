@@ -0,0 +1,156 @@
+package importer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+)
+
+// DefaultQIFDateFormat — формат дат в QIF по умолчанию, как в Quicken.
+const DefaultQIFDateFormat = "MM/DD/YYYY"
+
+// ParseQIF разбирает файл QIF со счетами типов Bank, Cash и CCard.
+// Даты читаются по dateFormat (по умолчанию MM/DD/YYYY), апостроф
+// в годе (1/31'24) заменяется на «/». Идентификаторов операций в QIF нет,
+// поэтому дубли при повторном импорте не отсеиваются.
+func ParseQIF(r io.Reader, encoding, dateFormat string) ([]Record, error) {
+	decoded, err := decode(r, encoding)
+	if err != nil {
+		return nil, err
+	}
+	if dateFormat == "" {
+		dateFormat = DefaultQIFDateFormat
+	}
+	layout := DateLayout(dateFormat)
+
+	var (
+		records []Record
+		fields  = map[byte]string{}
+		start   int
+		skip    bool // секция со списками категорий, счетов или инвестициями
+	)
+	scanner := bufio.NewScanner(decoded)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "!") {
+			header := strings.ToLower(strings.TrimSpace(text))
+			switch {
+			case strings.HasPrefix(header, "!type:"):
+				kind := strings.TrimPrefix(header, "!type:")
+				skip = kind != "bank" && kind != "cash" && kind != "ccard" && kind != "oth a" && kind != "oth l"
+			case header == "!option:autoswitch", header == "!clear:autoswitch":
+			default:
+				skip = true
+			}
+			continue
+		}
+
+		if text[0] == '^' {
+			if !skip && len(fields) > 0 {
+				if len(records) == MaxRows {
+					return nil, fmt.Errorf("В файле больше %d операций", MaxRows)
+				}
+				records = append(records, qifRecord(start, fields, dateFormat, layout))
+			}
+			fields = map[byte]string{}
+			continue
+		}
+
+		if len(fields) == 0 {
+			start = line
+		}
+		// Строки разбиения (S, E, $) повторяются, для импорта достаточно итоговой суммы
+		if _, ok := fields[text[0]]; !ok {
+			fields[text[0]] = strings.TrimSpace(text[1:])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Ошибка чтения QIF: %w", err)
+	}
+	if !skip && len(fields) > 0 {
+		records = append(records, qifRecord(start, fields, dateFormat, layout))
+	}
+	if len(records) == 0 {
+		return nil, errors.New("В файле QIF нет операций по счёту")
+	}
+	return records, nil
+}
+
+func qifRecord(line int, fields map[byte]string, dateFormat, layout string) Record {
+	record := Record{
+		Line:        line,
+		Title:       fields['P'],
+		Description: fields['M'],
+		Category:    fields['L'],
+	}
+	// Категория вида «Авто:Бензин» — берём верхний уровень; [Счёт] означает перевод
+	record.Category, _, _ = strings.Cut(record.Category, ":")
+	if strings.HasPrefix(record.Category, "[") {
+		record.Category = ""
+	}
+	if record.Title == "" {
+		record.Title, record.Description = record.Description, ""
+	}
+	if record.Title == "" {
+		record.fail("Пустое название операции")
+	}
+
+	date, err := qifDate(fields['D'], layout)
+	if err != nil {
+		record.fail(fmt.Sprintf("Неверная дата %q, ожидается формат %s", fields['D'], dateFormat))
+	}
+	record.Date = date
+
+	value := fields['T']
+	if value == "" {
+		value = fields['U']
+	}
+	amount, err := parseAmount(value, qifDecimal(value))
+	switch {
+	case err != nil:
+		record.fail(fmt.Sprintf("Неверная сумма %q", value))
+	case amount == 0:
+		record.fail("Нулевая сумма")
+	case amount < 0:
+		record.Amount, record.Type = -amount, models.Expense
+	default:
+		record.Amount, record.Type = amount, models.Income
+	}
+	return record
+}
+
+// qifDate разбирает дату по шаблону, допуская день и месяц без ведущего нуля
+// и год из двух цифр после апострофа: 1/31'24.
+func qifDate(s, layout string) (time.Time, error) {
+	s = strings.ReplaceAll(strings.ReplaceAll(s, " ", ""), "'", "/")
+	unpadded := strings.NewReplacer("01", "1", "02", "2").Replace(layout)
+	layouts := []string{layout, unpadded, strings.Replace(unpadded, "2006", "06", 1)}
+
+	var err error
+	for _, l := range layouts {
+		var date time.Time
+		if date, err = time.ParseInLocation(l, s, time.Local); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// qifDecimal угадывает десятичный разделитель: последний из «.» и «,»,
+// если после него не больше двух цифр. Иначе «,» разделяет разряды.
+func qifDecimal(s string) string {
+	i := strings.LastIndexAny(s, ".,")
+	if i >= 0 && len(s)-i-1 <= 2 {
+		return s[i : i+1]
+	}
+	return "."
+}
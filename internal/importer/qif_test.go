@@ -0,0 +1,88 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestParseQIF(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	}
+	cp1251, err := charmap.Windows1251.NewEncoder().String("!Type:Bank\nD31.01.2024\nT-1 250,50\nPМагнит\nLПродукты\n^\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		file       string
+		encoding   string
+		dateFormat string
+		want       []Record
+		wantErr    bool
+	}{
+		{
+			name: "формат Quicken",
+			file: "!Option:AutoSwitch\n!Account\nNКарта\n^\n!Clear:AutoSwitch\n" +
+				"!Type:Bank\n" +
+				"D1/31'24\nT-1,250.50\nPПятёрочка\nMпродукты\nLЕда:Супермаркеты\n^\n" +
+				"D02/01/2024\nU5000\nMЗарплата\nL[Сбережения]\n^\n" +
+				"!Type:Invst\nD02/02/2024\nT100\nPАкции\n^\n",
+			want: []Record{
+				{Line: 7, Date: day(2024, 1, 31), Amount: 125050, Type: models.Expense, Title: "Пятёрочка", Description: "продукты", Category: "Еда"},
+				{Line: 13, Date: day(2024, 2, 1), Amount: 500000, Type: models.Income, Title: "Зарплата"},
+			},
+		},
+		{
+			name:       "cp1251 и свой формат даты",
+			file:       cp1251,
+			encoding:   "cp1251",
+			dateFormat: "DD.MM.YYYY",
+			want: []Record{
+				{Line: 2, Date: day(2024, 1, 31), Amount: 125050, Type: models.Expense, Title: "Магнит", Category: "Продукты"},
+			},
+		},
+		{
+			name: "ошибки и последняя операция без ^",
+			file: "!Type:CCard\nD31/01/2024\nT0\n^\nD01/15/2024\nT12,345\nPКафе",
+			want: []Record{
+				{Line: 2, Errors: []string{"Пустое название операции", `Неверная дата "31/01/2024", ожидается формат MM/DD/YYYY`, "Нулевая сумма"}},
+				{Line: 5, Date: day(2024, 1, 15), Amount: 1234500, Type: models.Income, Title: "Кафе"},
+			},
+		},
+		{
+			name:    "нет операций по счёту",
+			file:    "!Type:Cat\nNЕда\nE\n^\n",
+			wantErr: true,
+		},
+		{
+			name:     "неизвестная кодировка",
+			file:     "!Type:Bank\n",
+			encoding: "koi8-r",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQIF(strings.NewReader(tt.file), tt.encoding, tt.dateFormat)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseQIF() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseQIF() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseQIF() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+// Package journal записывает транзакции в журналы учёта в простом тексте
+// (ledger, hledger, beancount) и читает такие журналы обратно.
+//
+// Счета приложения становятся счетами Assets или Liabilities (кредитные
+// карты), категории — счетами Income и Expenses, комиссии переводов
+// списываются на Expenses:Комиссии. Бонусы учитываются отдельным товаром
+// BONUS на счёте Assets:Бонусы.
+package journal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+)
+
+// Синтаксисы журнала. Формат ledger читается и hledger.
+const (
+	Ledger    = "ledger"
+	Beancount = "beancount"
+)
+
+// BonusCommodity — товар, в котором учитываются бонусы.
+const BonusCommodity = "BONUS"
+
+// Корневые счета и служебные счета журнала.
+const (
+	RootAssets      = "Assets"
+	RootLiabilities = "Liabilities"
+	RootIncome      = "Income"
+	RootExpenses    = "Expenses"
+	RootEquity      = "Equity"
+
+	feeAccount     = "Expenses:Комиссии"
+	bonusAccount   = "Assets:Бонусы"
+	bonusIncome    = "Income:Бонусы"
+	bonusExpense   = "Expenses:Бонусы"
+	openingAccount = "Equity:Opening-Balances"
+)
+
+// Book — справочники пользователя для записи журнала.
+type Book struct {
+	Accounts     map[uint]models.Account
+	Categories   map[uint]string // названия категорий по ID
+	OpeningBonus money.Amount
+}
+
+// Writer записывает транзакции в журнал одного синтаксиса.
+type Writer struct {
+	w        *bufio.Writer
+	syntax   string
+	book     Book
+	accounts map[uint]string // имена счетов в журнале
+}
+
+func NewWriter(w io.Writer, syntax string, book Book) *Writer {
+	writer := &Writer{w: bufio.NewWriter(w), syntax: syntax, book: book, accounts: map[uint]string{}}
+
+	// Названия счетов после замены символов могут совпасть, тогда к имени добавляется ID
+	used := map[string]bool{}
+	for _, account := range writer.sortedAccounts() {
+		name := writer.accountName(account)
+		if used[Key(name)] {
+			name = writer.accountName(models.Account{Name: account.Name + " " + strconv.FormatUint(uint64(account.ID), 10), Type: account.Type})
+		}
+		used[Key(name)] = true
+		writer.accounts[account.ID] = name
+	}
+	return writer
+}
+
+// Header записывает объявления товаров и счетов. Объявления датируются днём
+// первой транзакции или создания самого раннего счёта. Если opening, следом
+// записываются начальные остатки счетов и бонусов.
+func (w *Writer) Header(first time.Time, opening bool) {
+	date := first
+	for _, account := range w.book.Accounts {
+		if date.IsZero() || account.CreatedAt.Before(date) {
+			date = account.CreatedAt
+		}
+	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+
+	accounts := w.sortedAccounts()
+	commodities := map[string]bool{BonusCommodity: true}
+	for _, account := range accounts {
+		commodities[account.Currency] = true
+	}
+	codes := make([]string, 0, len(commodities))
+	for code := range commodities {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	fmt.Fprintf(w.w, "; Выгрузка pers-fin-m от %s\n\n", time.Now().Format("2006-01-02"))
+	if w.syntax == Beancount {
+		// Счета категорий заранее неизвестны, их открывает плагин
+		fmt.Fprintf(w.w, "option \"operating_currency\" %q\n", w.operatingCurrency(accounts))
+		fmt.Fprintln(w.w, `plugin "beancount.plugins.auto_accounts"`)
+		fmt.Fprintln(w.w)
+		for _, code := range codes {
+			fmt.Fprintf(w.w, "%s commodity %s\n", day(date), code)
+		}
+		fmt.Fprintln(w.w)
+		for _, account := range accounts {
+			fmt.Fprintf(w.w, "%s open %s %s\n", day(date), w.accounts[account.ID], account.Currency)
+		}
+		fmt.Fprintf(w.w, "%s open %s %s\n", day(date), bonusAccount, BonusCommodity)
+	} else {
+		for _, code := range codes {
+			fmt.Fprintf(w.w, "commodity %s\n", code)
+		}
+		fmt.Fprintln(w.w)
+		for _, account := range accounts {
+			fmt.Fprintf(w.w, "account %s\n", w.accounts[account.ID])
+		}
+		fmt.Fprintf(w.w, "account %s\n", bonusAccount)
+	}
+	fmt.Fprintln(w.w)
+
+	if !opening {
+		return
+	}
+	for _, account := range accounts {
+		if account.OpeningBalance != 0 {
+			w.entry(date, "", "Начальный остаток", "", []posting{
+				{account: w.accounts[account.ID], amount: account.OpeningBalance, commodity: account.Currency},
+				{account: openingAccount, amount: -account.OpeningBalance, commodity: account.Currency},
+			})
+		}
+	}
+	if w.book.OpeningBonus != 0 {
+		w.entry(date, "", "Начальные бонусы", "", []posting{
+			{account: bonusAccount, amount: w.book.OpeningBonus, commodity: BonusCommodity},
+			{account: openingAccount, amount: -w.book.OpeningBonus, commodity: BonusCommodity},
+		})
+	}
+}
+
+// Write записывает транзакцию.
+func (w *Writer) Write(t models.Transaction) error {
+	account := w.accounts[t.AccountID]
+	category := w.book.Categories[t.Category]
+	var postings []posting
+	switch t.Type {
+	case models.Income:
+		postings = []posting{
+			{account: account, amount: t.Amount, commodity: t.Currency},
+			{account: w.categoryName(RootIncome, category), amount: -t.Amount, commodity: t.Currency},
+		}
+	case models.Expense:
+		postings = []posting{
+			{account: w.categoryName(RootExpenses, category), amount: t.Amount, commodity: t.Currency},
+			{account: account, amount: -t.Amount, commodity: t.Currency},
+		}
+	case models.Transfer:
+		to := posting{commodity: t.Currency, amount: t.Amount}
+		if t.CounterAccountID != nil {
+			counter := w.book.Accounts[*t.CounterAccountID]
+			to = posting{account: w.accounts[counter.ID], amount: t.CounterAmount, commodity: counter.Currency}
+			if counter.Currency != t.Currency {
+				to.price, to.priceCommodity = t.Amount, t.Currency
+			}
+		}
+		postings = append(postings, to)
+		if t.Fee != 0 {
+			postings = append(postings, posting{account: feeAccount, amount: t.Fee, commodity: t.Currency})
+		}
+		postings = append(postings, posting{account: account, amount: -(t.Amount + t.Fee), commodity: t.Currency})
+	default:
+		return fmt.Errorf("неизвестный тип транзакции %q", t.Type)
+	}
+
+	if bonus := t.BonusEffect(); bonus != 0 {
+		counter := bonusIncome
+		if bonus < 0 {
+			counter = bonusExpense
+		}
+		postings = append(postings,
+			posting{account: bonusAccount, amount: bonus, commodity: BonusCommodity},
+			posting{account: counter, amount: -bonus, commodity: BonusCommodity},
+		)
+	}
+
+	w.entry(t.Date, strconv.FormatUint(uint64(t.ID), 10), t.Title, t.Description, postings)
+	return nil
+}
+
+// Flush сбрасывает буфер в выходной поток.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+type posting struct {
+	account        string
+	amount         money.Amount
+	commodity      string
+	price          money.Amount // полная стоимость в priceCommodity (@@)
+	priceCommodity string
+}
+
+// entry записывает одну проводку. ID и время суток сохраняются метаданными,
+// чтобы при обратном импорте найти дубли и восстановить время.
+func (w *Writer) entry(date time.Time, id, title, description string, postings []posting) {
+	clock := ""
+	if h, m, _ := date.Clock(); h != 0 || m != 0 {
+		clock = date.Format("15:04")
+	}
+
+	if w.syntax == Beancount {
+		fmt.Fprintf(w.w, "%s * %s", day(date), quote(title))
+		if description != "" {
+			fmt.Fprintf(w.w, " %s", quote(description))
+		}
+		fmt.Fprintln(w.w)
+		if id != "" {
+			fmt.Fprintf(w.w, "  id: %q\n", id)
+		}
+		if clock != "" {
+			fmt.Fprintf(w.w, "  time: %q\n", clock)
+		}
+	} else {
+		fmt.Fprintf(w.w, "%s *", day(date))
+		if id != "" {
+			fmt.Fprintf(w.w, " (%s)", id)
+		}
+		fmt.Fprintf(w.w, " %s\n", strings.Join(strings.Fields(title), " "))
+		if clock != "" {
+			fmt.Fprintf(w.w, "    ; time: %s\n", clock)
+		}
+		for _, line := range strings.Split(description, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				fmt.Fprintf(w.w, "    ; %s\n", line)
+			}
+		}
+	}
+
+	indent := "    "
+	if w.syntax == Beancount {
+		indent = "  "
+	}
+	for _, p := range postings {
+		fmt.Fprintf(w.w, "%s%-40s  %12s %s", indent, p.account, p.amount, p.commodity)
+		if p.priceCommodity != "" {
+			fmt.Fprintf(w.w, " @@ %s %s", p.price, p.priceCommodity)
+		}
+		fmt.Fprintln(w.w)
+	}
+	fmt.Fprintln(w.w)
+}
+
+func (w *Writer) sortedAccounts() []models.Account {
+	accounts := make([]models.Account, 0, len(w.book.Accounts))
+	for _, account := range w.book.Accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].ID < accounts[j].ID })
+	return accounts
+}
+
+// operatingCurrency — валюта большинства счетов, для отчётов beancount.
+func (w *Writer) operatingCurrency(accounts []models.Account) string {
+	counts := map[string]int{}
+	best := "RUB"
+	for _, account := range accounts {
+		counts[account.Currency]++
+		if counts[account.Currency] > counts[best] {
+			best = account.Currency
+		}
+	}
+	return best
+}
+
+// accountName возвращает имя счёта в журнале: Liabilities для кредитных карт, Assets для остальных.
+func (w *Writer) accountName(account models.Account) string {
+	root := RootAssets
+	if account.Type == models.AccountCredit {
+		root = RootLiabilities
+	}
+	return root + ":" + w.component(account.Name, "Счёт")
+}
+
+func (w *Writer) categoryName(root, category string) string {
+	return root + ":" + w.component(category, "Без категории")
+}
+
+// component приводит название к допустимой части имени счёта. В ledger
+// разрешены одиночные пробелы, но не «:»; в beancount часть имени состоит
+// из букв, цифр и дефисов и начинается с заглавной буквы или цифры.
+func (w *Writer) component(name, fallback string) string {
+	if w.syntax != Beancount {
+		name = strings.Join(strings.Fields(strings.NewReplacer(":", "-", ";", "-").Replace(name)), " ")
+		name = strings.TrimLeft(name, "([")
+		if name == "" {
+			name = fallback
+		}
+		return name
+	}
+
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			if b.Len() == 0 {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+		} else {
+			dash = true
+		}
+	}
+	if b.Len() == 0 {
+		return w.component(fallback, "X")
+	}
+	return b.String()
+}
+
+// Key приводит имя счёта или категории к виду для сравнения: без учёта
+// регистра, а дефисы, «:» и другие знаки считаются пробелами.
+func Key(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+func day(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// quote экранирует строку beancount. Переводы строк заменяются пробелами.
+func quote(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,375 @@
+package journal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+)
+
+// Posting — строка проводки из журнала.
+type Posting struct {
+	Account        string
+	Amount         money.Amount
+	Commodity      string
+	Elided         bool         // сумма не указана и выводится из остальных строк
+	Price          money.Amount // полная стоимость строки в PriceCommodity (@ или @@)
+	PriceCommodity string
+}
+
+// Weight возвращает сумму, которой строка участвует в балансе проводки:
+// для строки с ценой — её стоимость в валюте цены.
+func (p Posting) Weight() (money.Amount, string) {
+	if p.PriceCommodity == "" {
+		return p.Amount, p.Commodity
+	}
+	if p.Amount < 0 {
+		return -p.Price, p.PriceCommodity
+	}
+	return p.Price, p.PriceCommodity
+}
+
+// Transaction — проводка журнала.
+type Transaction struct {
+	Line        int
+	Date        time.Time
+	Code        string // код ledger в скобках или метаданные id
+	Title       string
+	Description string
+	Postings    []Posting
+	Errors      []string
+}
+
+// Директивы beancount, которые пишутся после даты, но не являются проводками.
+var directives = map[string]bool{
+	"open": true, "close": true, "commodity": true, "balance": true, "pad": true,
+	"note": true, "document": true, "event": true, "price": true, "query": true, "custom": true,
+}
+
+// metaKey — метаданные beancount «key: value» и теги ledger «; key: value».
+var metaKey = regexp.MustCompile(`^([a-z][a-zA-Z0-9_-]*):(?:\s+(.*))?$`)
+
+// Parse читает журнал ledger, hledger или beancount. Директивы, периодические
+// и автоматические проводки пропускаются. Ошибки в отдельной проводке
+// записываются в её Errors, ошибкой возвращается только сбой чтения.
+func Parse(r io.Reader) ([]Transaction, error) {
+	var (
+		transactions []Transaction
+		current      *Transaction
+	)
+	finish := func() {
+		if current != nil {
+			transactions = append(transactions, *current)
+			current = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\uFEFF")
+		}
+		if strings.TrimSpace(text) == "" {
+			finish()
+			continue
+		}
+
+		if text[0] == ' ' || text[0] == '\t' {
+			if current != nil {
+				current.addLine(strings.TrimSpace(text))
+			}
+			continue
+		}
+
+		finish()
+		if text[0] < '0' || text[0] > '9' {
+			continue // комментарии, директивы ledger, P, ~ и =
+		}
+		if t, ok := parseHeader(text, line); ok {
+			current = &t
+		}
+	}
+	finish()
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Ошибка чтения журнала: %w", err)
+	}
+	return transactions, nil
+}
+
+// parseHeader разбирает первую строку проводки: дату, флаг, код и описание.
+// Для директив beancount возвращает false.
+func parseHeader(text string, line int) (Transaction, bool) {
+	t := Transaction{Line: line}
+	raw, rest, _ := strings.Cut(text, " ")
+	rest = strings.TrimSpace(rest)
+	if word, _, _ := strings.Cut(rest, " "); directives[word] {
+		return t, false
+	}
+
+	// Вспомогательная дата ledger: 2024-01-15=2024-01-17
+	raw, _, _ = strings.Cut(raw, "=")
+	date, err := parseDate(raw)
+	if err != nil {
+		t.fail(fmt.Sprintf("Неверная дата %q, ожидается ГГГГ-ММ-ДД", raw))
+	}
+	t.Date = date
+
+	for _, flag := range []string{"txn ", "* ", "! "} {
+		rest = strings.TrimSpace(strings.TrimPrefix(rest+" ", flag))
+	}
+	if strings.HasPrefix(rest, "(") {
+		if end := strings.IndexByte(rest, ')'); end > 0 {
+			t.Code = strings.TrimSpace(rest[1:end])
+			rest = strings.TrimSpace(rest[end+1:])
+		}
+	}
+
+	if strings.HasPrefix(rest, `"`) {
+		// beancount: "получатель" "описание" или одно "описание", затем теги
+		var strs []string
+		for strings.HasPrefix(rest, `"`) {
+			s, tail, ok := unquote(rest)
+			if !ok {
+				t.fail("Незакрытая кавычка в описании")
+				break
+			}
+			strs = append(strs, s)
+			rest = strings.TrimSpace(tail)
+		}
+		switch len(strs) {
+		case 1:
+			t.Title = strs[0]
+		case 2:
+			t.Title, t.Description = strs[0], strs[1]
+		}
+		return t, true
+	}
+
+	// ledger: описание до комментария, комментарий — часть описания операции
+	title, comment := cutComment(rest)
+	t.Title = title
+	if comment != "" {
+		t.addComment(comment)
+	}
+	return t, true
+}
+
+// addLine разбирает строку проводки с отступом: комментарий, метаданные или строку со счётом.
+func (t *Transaction) addLine(text string) {
+	switch {
+	case strings.HasPrefix(text, ";"), strings.HasPrefix(text, "#"):
+		t.addComment(strings.TrimSpace(strings.TrimLeft(text, ";#")))
+		return
+	case metaKey.MatchString(text):
+		m := metaKey.FindStringSubmatch(text)
+		value, _, ok := unquote(m[2])
+		if !ok {
+			value = m[2]
+		}
+		t.addMeta(m[1], value)
+		return
+	}
+
+	text, _ = cutComment(text)
+	if strings.HasPrefix(text, "* ") || strings.HasPrefix(text, "! ") {
+		text = strings.TrimSpace(text[2:])
+	}
+	// Виртуальные строки ledger не участвуют в балансе
+	if strings.HasPrefix(text, "(") || strings.HasPrefix(text, "[") {
+		return
+	}
+
+	p := Posting{Account: text, Elided: true}
+	account, amount, ok := splitPosting(text)
+	if ok {
+		p.Account = account
+		if err := p.parseAmount(amount); err != nil {
+			t.fail(fmt.Sprintf("Счёт %s: %s", account, err))
+			return
+		}
+		p.Elided = false
+	}
+	t.Postings = append(t.Postings, p)
+}
+
+// addComment добавляет комментарий к описанию или читает из него тег ledger.
+func (t *Transaction) addComment(comment string) {
+	if m := metaKey.FindStringSubmatch(comment); m != nil && t.addMeta(m[1], m[2]) {
+		return
+	}
+	if comment == "" {
+		return
+	}
+	if t.Description != "" {
+		t.Description += "\n"
+	}
+	t.Description += comment
+}
+
+// addMeta применяет известные метаданные: id и time. Остальные пропускаются.
+func (t *Transaction) addMeta(key, value string) bool {
+	value = strings.TrimSpace(value)
+	switch key {
+	case "id":
+		t.Code = value
+	case "time":
+		clock, err := time.Parse("15:04", value)
+		if err != nil {
+			t.fail(fmt.Sprintf("Неверное время %q", value))
+			return true
+		}
+		t.Date = time.Date(t.Date.Year(), t.Date.Month(), t.Date.Day(), clock.Hour(), clock.Minute(), 0, 0, t.Date.Location())
+	default:
+		return false
+	}
+	return true
+}
+
+func (t *Transaction) fail(msg string) {
+	t.Errors = append(t.Errors, msg)
+}
+
+// splitPosting отделяет счёт от суммы. В ledger их разделяют два пробела
+// или табуляция, в beancount имя счёта не содержит пробелов.
+func splitPosting(text string) (string, string, bool) {
+	if i := strings.IndexAny(text, "\t"); i > 0 {
+		return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i:]), true
+	}
+	if i := strings.Index(text, "  "); i > 0 {
+		return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i:]), true
+	}
+	if account, amount, ok := strings.Cut(text, " "); ok {
+		var p Posting
+		if p.parseAmount(amount) == nil {
+			return account, amount, true
+		}
+	}
+	return text, "", false
+}
+
+// parseAmount разбирает сумму строки с необязательной ценой: «100 USD @@ 9000 RUB»
+// или «100 USD @ 90 RUB». Стоимость покупки {…} и проверки баланса «= …» пропускаются.
+func (p *Posting) parseAmount(s string) error {
+	if i := strings.IndexByte(s, '{'); i >= 0 {
+		if end := strings.IndexByte(s[i:], '}'); end > 0 {
+			s = s[:i] + s[i+end+1:]
+		}
+	}
+	s, _, _ = strings.Cut(s, "=")
+
+	value, price, hasPrice := strings.Cut(s, "@")
+	amount, commodity, err := ParseAmount(value)
+	if err != nil {
+		return err
+	}
+	p.Amount, p.Commodity = amount, commodity
+	if !hasPrice {
+		return nil
+	}
+
+	total := strings.HasPrefix(price, "@")
+	priceAmount, priceCommodity, err := ParseAmount(strings.TrimPrefix(price, "@"))
+	if err != nil {
+		return err
+	}
+	if !total {
+		if priceAmount, err = amount.Abs().MulRatio(int64(priceAmount), money.Scale); err != nil {
+			return fmt.Errorf("цена %q: %w", strings.TrimSpace(price), err)
+		}
+	}
+	p.Price, p.PriceCommodity = priceAmount.Abs(), priceCommodity
+	return nil
+}
+
+var amountPattern = regexp.MustCompile(`^(-)?\s*("[^"]*"|[^\s\d"+\-.,@]+)?\s*([-+]?\d[\d,.']*)\s*("[^"]*"|[^\s\d"+\-.,@]+)?$`)
+
+// ParseAmount разбирает сумму с валютой до или после числа: «1,250.00 RUB»,
+// «-$12.50», «RUB 100». Разделителем дробной части считается последний
+// из «.» и «,», другой знак разделяет разряды.
+func ParseAmount(s string) (money.Amount, string, error) {
+	m := amountPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, "", fmt.Errorf("неверная сумма %q", strings.TrimSpace(s))
+	}
+	commodity := m[2]
+	if commodity == "" {
+		commodity = m[4]
+	}
+	commodity = strings.Trim(commodity, `"`)
+
+	number := m[3]
+	last := strings.LastIndexAny(number, ".,")
+	if last >= 0 {
+		sep := number[last]
+		other := byte(',')
+		if sep == ',' {
+			other = '.'
+		}
+		if strings.Count(number, string(sep)) > 1 {
+			// «1,250,000» — только разделители разрядов
+			number = strings.ReplaceAll(number, string(sep), "")
+		} else {
+			number = strings.ReplaceAll(number, string(other), "")
+		}
+	}
+
+	amount, err := money.Parse(number)
+	if err != nil {
+		return 0, "", fmt.Errorf("неверная сумма %q", strings.TrimSpace(s))
+	}
+	if m[1] != "" {
+		amount = -amount
+	}
+	return amount, commodity, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	var err error
+	for _, layout := range []string{"2006-01-02", "2006/01/02", "2006.01.02", "2006-1-2", "2006/1/2", "2006.1.2"} {
+		var date time.Time
+		if date, err = time.ParseInLocation(layout, s, time.Local); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// cutComment отделяет комментарий после «;».
+func cutComment(s string) (string, string) {
+	text, comment, _ := strings.Cut(s, ";")
+	return strings.TrimSpace(text), strings.TrimSpace(comment)
+}
+
+// unquote читает строку в кавычках с экранированием \" и \\ в начале s.
+func unquote(s string) (string, string, bool) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", s, false
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String(), s[i+1:], true
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", s, false
+}
+
+// Root возвращает корневой счёт и остаток имени: Expenses:Еда:Кафе → Expenses, Еда:Кафе.
+func Root(account string) (string, string) {
+	root, rest, _ := strings.Cut(account, ":")
+	return root, rest
+}
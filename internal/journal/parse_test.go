@@ -0,0 +1,170 @@
+package journal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in        string
+		amount    string
+		commodity string
+		wantErr   bool
+	}{
+		{in: "100", amount: "100"},
+		{in: "1,250.00 RUB", amount: "1250", commodity: "RUB"},
+		{in: "1.250,50 EUR", amount: "1250.5", commodity: "EUR"},
+		{in: "1,250,000 RUB", amount: "1250000", commodity: "RUB"},
+		{in: "-$12.50", amount: "-12.5", commodity: "$"},
+		{in: "$-12.50", amount: "-12.5", commodity: "$"},
+		{in: "RUB 100", amount: "100", commodity: "RUB"},
+		{in: "  -42,5 ₽ ", amount: "-42.5", commodity: "₽"},
+		{in: `10 "BONUS POINTS"`, amount: "10", commodity: "BONUS POINTS"},
+		{in: "", wantErr: true},
+		{in: "RUB", wantErr: true},
+		{in: "1.234 RUB", wantErr: true}, // единственный разделитель — дробная часть
+		{in: "1.2345,678", wantErr: true},
+		{in: "100 RUB USD", wantErr: true},
+	}
+	for _, tt := range tests {
+		amount, commodity, err := ParseAmount(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseAmount(%q) = %s %q, want error", tt.in, amount, commodity)
+			}
+			continue
+		}
+		if err != nil || amount != money.MustParse(tt.amount) || commodity != tt.commodity {
+			t.Errorf("ParseAmount(%q) = %s %q, %v, want %s %q", tt.in, amount, commodity, err, tt.amount, tt.commodity)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	day := func(y int, m time.Month, d, hh, mm int) time.Time {
+		return time.Date(y, m, d, hh, mm, 0, 0, time.Local)
+	}
+	tests := []struct {
+		name    string
+		journal string
+		want    []Transaction
+	}{
+		{
+			name: "ledger",
+			journal: "; комментарий файла\n" +
+				"account Assets:Карта\n" +
+				"\n" +
+				"2024/01/15 * (42) Пятёрочка ; продукты\n" +
+				"    ; time: 18:30\n" +
+				"    Expenses:Еда        1,250.00 RUB\n" +
+				"    Assets:Карта\n" +
+				"\n" +
+				"2024-01-16=2024-01-17 ! Обмен\n" +
+				"    Assets:Валюта    100 USD @ 90.5 RUB\n" +
+				"    (Budget:Еда)    -100 RUB\n" +
+				"    Assets:Карта    -9050 RUB\n",
+			want: []Transaction{
+				{
+					Line:        4,
+					Date:        day(2024, 1, 15, 18, 30),
+					Code:        "42",
+					Title:       "Пятёрочка",
+					Description: "продукты",
+					Postings: []Posting{
+						{Account: "Expenses:Еда", Amount: 125000, Commodity: "RUB"},
+						{Account: "Assets:Карта", Elided: true},
+					},
+				},
+				{
+					Line:  9,
+					Date:  day(2024, 1, 16, 0, 0),
+					Title: "Обмен",
+					Postings: []Posting{
+						{Account: "Assets:Валюта", Amount: 10000, Commodity: "USD", Price: 905000, PriceCommodity: "RUB"},
+						{Account: "Assets:Карта", Amount: -905000, Commodity: "RUB"},
+					},
+				},
+			},
+		},
+		{
+			name: "beancount",
+			journal: "2024-01-01 open Assets:Card RUB\n" +
+				"\n" +
+				"2024-02-01 txn \"Магазин\" \"Подарок\" #теги\n" +
+				"  id: \"abc-1\"\n" +
+				"  Expenses:Gifts 15.5 USD @@ 1400 RUB\n" +
+				"  Assets:Card -1400 RUB\n" +
+				"2024-02-02 balance Assets:Card 0 RUB\n",
+			want: []Transaction{
+				{
+					Line:        3,
+					Date:        day(2024, 2, 1, 0, 0),
+					Code:        "abc-1",
+					Title:       "Магазин",
+					Description: "Подарок",
+					Postings: []Posting{
+						{Account: "Expenses:Gifts", Amount: 1550, Commodity: "USD", Price: 140000, PriceCommodity: "RUB"},
+						{Account: "Assets:Card", Amount: -140000, Commodity: "RUB"},
+					},
+				},
+			},
+		},
+		{
+			name: "ошибки проводки",
+			journal: "2024-13-01 Неверная дата\n" +
+				"    Expenses:Еда    сто RUB\n" +
+				"    Assets:Карта\n",
+			want: []Transaction{
+				{
+					Line:  1,
+					Title: "Неверная дата",
+					Postings: []Posting{
+						{Account: "Assets:Карта", Elided: true},
+					},
+					Errors: []string{
+						`Неверная дата "2024-13-01", ожидается ГГГГ-ММ-ДД`,
+						`Счёт Expenses:Еда: неверная сумма "сто RUB"`,
+					},
+				},
+			},
+		},
+		{
+			name:    "пустой журнал",
+			journal: "\uFEFF; только комментарии\n= expr\n~ monthly\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.journal))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostingWeight(t *testing.T) {
+	tests := []struct {
+		posting   Posting
+		amount    money.Amount
+		commodity string
+	}{
+		{Posting{Amount: 100, Commodity: "RUB"}, 100, "RUB"},
+		{Posting{Amount: 100, Commodity: "USD", Price: 9000, PriceCommodity: "RUB"}, 9000, "RUB"},
+		{Posting{Amount: -100, Commodity: "USD", Price: 9000, PriceCommodity: "RUB"}, -9000, "RUB"},
+	}
+	for _, tt := range tests {
+		amount, commodity := tt.posting.Weight()
+		if amount != tt.amount || commodity != tt.commodity {
+			t.Errorf("%+v.Weight() = %s %s, want %s %s", tt.posting, amount, commodity, tt.amount, tt.commodity)
+		}
+	}
+}
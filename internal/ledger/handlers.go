@@ -0,0 +1,54 @@
+package ledger
+
+import (
+	"net/http"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	store repository.Store
+}
+
+func NewHandler(store repository.Store) *Handler {
+	return &Handler{store: store}
+}
+
+// @Security BearerAuth
+// CheckBalance godoc
+// @Summary Сверить баланс с транзакциями
+// @Description Сравнивает сохранённые балансы счетов и бонусов с суммой транзакций, ничего не изменяя
+// @Tags Users
+// @Produce json
+// @Success 200 {object} Report "Результат сверки"
+// @Failure 500 {object} response.ErrorResponse "Ошибка сверки баланса"
+// @Router /users/balance/check [get]
+func (h *Handler) CheckBalance(c *gin.Context) {
+	report, err := Check(h.store, c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сверки баланса"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Security BearerAuth
+// RecalculateBalance godoc
+// @Summary Пересчитать баланс по транзакциям
+// @Description Приводит балансы счетов и бонусов к значениям, вычисленным по транзакциям. Возвращает расхождения до исправления
+// @Tags Users
+// @Produce json
+// @Success 200 {object} Report "Результат пересчёта"
+// @Failure 500 {object} response.ErrorResponse "Ошибка пересчёта баланса"
+// @Router /users/balance/recalculate [post]
+func (h *Handler) RecalculateBalance(c *gin.Context) {
+	report, err := Recalculate(h.store, c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка пересчёта баланса"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,55 @@
+package ledger
+
+import (
+	"log"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+// RunPeriodic раз в interval сверяет балансы всех пользователей
+// и пишет в лог найденные расхождения. Блокирует вызывающую горутину.
+func RunPeriodic(store repository.Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		CheckAll(store)
+	}
+}
+
+// CheckAll сверяет балансы всех пользователей и возвращает количество
+// пользователей, у которых есть расхождения. Балансы не изменяются:
+// исправление выполняет только сам пользователь через Recalculate.
+func CheckAll(store repository.Store) int {
+	ids, err := store.Users().ListIDs()
+	if err != nil {
+		log.Println("Сверка балансов: ошибка получения пользователей:", err)
+		return 0
+	}
+
+	drifted := 0
+	for _, id := range ids {
+		report, err := Check(store, id)
+		if err != nil {
+			log.Printf("Сверка балансов: пользователь %d: %v", id, err)
+			continue
+		}
+		if !report.HasDrift {
+			continue
+		}
+
+		drifted++
+		for _, account := range report.Accounts {
+			if account.Drift != 0 {
+				log.Printf("Сверка балансов: пользователь %d, счёт %d: сохранено %s, по журналу %s, расхождение %s",
+					id, account.AccountID, account.Stored, account.Expected, account.Drift)
+			}
+		}
+		if report.Bonus.Drift != 0 {
+			log.Printf("Сверка балансов: пользователь %d, бонусы: сохранено %s, по журналу %s, расхождение %s",
+				id, report.Bonus.Stored, report.Bonus.Expected, report.Bonus.Drift)
+		}
+	}
+	return drifted
+}
@@ -0,0 +1,115 @@
+// Package ledger сверяет сохранённые балансы с журналом транзакций.
+//
+// Ожидаемый баланс счёта равен начальному остатку плюс влияние всех его
+// транзакций; ожидаемые бонусы — начальным бонусам плюс бонусы транзакций.
+package ledger
+
+import (
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+type AccountDrift struct {
+	AccountID uint         `json:"accountId"`
+	Name      string       `json:"name"`
+	Currency  string       `json:"currency"`
+	Stored    money.Amount `json:"stored" swaggertype:"number"`
+	Expected  money.Amount `json:"expected" swaggertype:"number"`
+	Drift     money.Amount `json:"drift" swaggertype:"number"` // Stored - Expected
+}
+
+type BonusDrift struct {
+	Stored   money.Amount `json:"stored" swaggertype:"number"`
+	Expected money.Amount `json:"expected" swaggertype:"number"`
+	Drift    money.Amount `json:"drift" swaggertype:"number"`
+}
+
+// Report — результат сверки балансов пользователя.
+type Report struct {
+	UserID   uint           `json:"userId"`
+	Accounts []AccountDrift `json:"accounts"`
+	Bonus    BonusDrift     `json:"bonus"`
+	HasDrift bool           `json:"hasDrift"`
+	Fixed    bool           `json:"fixed"` // балансы приведены к ожидаемым
+}
+
+// Check сравнивает сохранённые балансы с журналом, ничего не меняя.
+func Check(store repository.Store, userID uint) (*Report, error) {
+	user, err := store.Users().GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := store.Accounts().ListForUser(userID, true)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := store.Transactions().Ledger(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{UserID: userID, Accounts: make([]AccountDrift, 0, len(accounts))}
+	for _, account := range accounts {
+		expected := account.OpeningBalance + totals.Accounts[account.ID]
+		drift := AccountDrift{
+			AccountID: account.ID,
+			Name:      account.Name,
+			Currency:  account.Currency,
+			Stored:    account.Balance,
+			Expected:  expected,
+			Drift:     account.Balance - expected,
+		}
+		if drift.Drift != 0 {
+			report.HasDrift = true
+		}
+		report.Accounts = append(report.Accounts, drift)
+	}
+
+	expectedBonus := user.OpeningBonus + totals.Bonus
+	report.Bonus = BonusDrift{
+		Stored:   user.Bonus,
+		Expected: expectedBonus,
+		Drift:    user.Bonus - expectedBonus,
+	}
+	if report.Bonus.Drift != 0 {
+		report.HasDrift = true
+	}
+	return report, nil
+}
+
+// Recalculate приводит балансы к значениям из журнала. Возвращённый отчёт
+// содержит расхождения, которые были до исправления.
+//
+// Перед сверкой счета и строка пользователя блокируются, иначе параллельная
+// транзакция между чтением журнала и чтением баланса дала бы ложное
+// расхождение, которое записалось бы как настоящее изменение баланса.
+func Recalculate(store repository.Store, userID uint) (*Report, error) {
+	var report *Report
+	err := store.Atomic(func(s repository.Store) error {
+		if err := s.Accounts().LockForUser(userID); err != nil {
+			return err
+		}
+		if err := s.Users().Lock(userID); err != nil {
+			return err
+		}
+
+		var err error
+		report, err = Check(s, userID)
+		if err != nil || !report.HasDrift {
+			return err
+		}
+
+		for _, account := range report.Accounts {
+			if err := s.Accounts().AdjustBalance(account.AccountID, -account.Drift); err != nil {
+				return err
+			}
+		}
+		return s.Users().AdjustBonus(userID, -report.Bonus.Drift)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Fixed = report.HasDrift
+	return report, nil
+}
@@ -0,0 +1,247 @@
+// Package migrations применяет версионированные SQL-миграции из каталога sql.
+//
+// Файлы называются NNNN_описание.up.sql и NNNN_описание.down.sql.
+// Применённые версии хранятся в таблице schema_migrations.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// lockKey — ключ advisory-блокировки, чтобы два процесса не мигрировали одновременно.
+const lockKey = 7262021
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+type schemaMigration struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Load читает миграции из встроенных файлов и сортирует их по версии.
+func Load() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("неизвестный файл миграции %s", name)
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		prefix, title, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("в имени миграции %s нет описания", name)
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("неверный номер миграции %s: %w", name, err)
+		}
+
+		body, err := fs.ReadFile(files, "sql/"+name)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: title}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("у миграции %04d_%s нет up или down файла", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func applied(db *gorm.DB) (map[int]schemaMigration, error) {
+	if err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name text NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`).Error; err != nil {
+		return nil, err
+	}
+
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[int]schemaMigration, len(rows))
+	for _, row := range rows {
+		result[row.Version] = row
+	}
+	return result, nil
+}
+
+// Up применяет все ещё не применённые миграции и возвращает их количество.
+func Up(db *gorm.DB) (int, error) {
+	migrations, err := Load()
+	if err != nil {
+		return 0, err
+	}
+	done, err := applied(db)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, m := range migrations {
+		if _, ok := done[m.Version]; ok {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockKey).Error; err != nil {
+				return err
+			}
+			// Другой процесс мог применить миграцию, пока мы ждали блокировку
+			var exists int64
+			if err := tx.Model(&schemaMigration{}).Where("version = ?", m.Version).Count(&exists).Error; err != nil {
+				return err
+			}
+			if exists > 0 {
+				return nil
+			}
+
+			if err := tx.Exec(m.Up).Error; err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return count, fmt.Errorf("миграция %04d_%s: %w", m.Version, m.Name, err)
+		}
+
+		log.Printf("Применена миграция %04d_%s", m.Version, m.Name)
+		count++
+	}
+	return count, nil
+}
+
+// Down откатывает последнюю применённую миграцию.
+// Возвращает false, если откатывать нечего.
+func Down(db *gorm.DB) (bool, error) {
+	migrations, err := Load()
+	if err != nil {
+		return false, err
+	}
+	done, err := applied(db)
+	if err != nil {
+		return false, err
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if _, ok := done[m.Version]; !ok {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockKey).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(m.Down).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, "version = ?", m.Version).Error
+		})
+		if err != nil {
+			return false, fmt.Errorf("откат миграции %04d_%s: %w", m.Version, m.Name, err)
+		}
+
+		log.Printf("Откачена миграция %04d_%s", m.Version, m.Name)
+		return true, nil
+	}
+	return false, nil
+}
+
+// Pending возвращает количество неприменённых миграций.
+func Pending(db *gorm.DB) (int, error) {
+	statuses, err := List(db)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// List возвращает все известные миграции с отметкой о применении.
+func List(db *gorm.DB) ([]Status, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(migrations))
+	for _, m := range migrations {
+		status := Status{Version: m.Version, Name: m.Name}
+		if row, ok := done[m.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,44 @@
+-- Исходная схема, ранее создававшаяся AutoMigrate.
+-- IF NOT EXISTS позволяет применить миграцию к уже развёрнутой базе.
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    username varchar(100) NOT NULL,
+    email varchar(100) NOT NULL CONSTRAINT uni_users_email UNIQUE,
+    password text NOT NULL,
+    balance decimal DEFAULT 0,
+    bonus decimal DEFAULT 0,
+    verification_code text,
+    verified boolean DEFAULT false
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id bigint NOT NULL,
+    amount decimal NOT NULL,
+    bonus_change decimal DEFAULT 0,
+    bonus_type varchar(10),
+    currency varchar(10) DEFAULT 'RUB',
+    date timestamptz NOT NULL,
+    title varchar(100) NOT NULL,
+    description text,
+    category bigint NOT NULL,
+    type varchar(10) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_transactions_deleted_at ON transactions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    user_id bigint,
+    color text NOT NULL DEFAULT '#16a34a',
+    is_default boolean NOT NULL DEFAULT false,
+    created_at timestamptz,
+    updated_at timestamptz
+);
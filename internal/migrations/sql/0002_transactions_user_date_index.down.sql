@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_categories_user_id;
+DROP INDEX IF EXISTS idx_transactions_user_date;
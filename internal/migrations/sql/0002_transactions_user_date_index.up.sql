@@ -0,0 +1,3 @@
+-- Поиск транзакций всегда фильтрует по пользователю и сортирует по дате.
+CREATE INDEX IF NOT EXISTS idx_transactions_user_date ON transactions (user_id, date DESC);
+CREATE INDEX IF NOT EXISTS idx_categories_user_id ON categories (user_id);
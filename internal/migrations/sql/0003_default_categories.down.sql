@@ -0,0 +1,6 @@
+ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
+
+DROP INDEX IF EXISTS uni_categories_default_slug;
+ALTER TABLE categories DROP COLUMN IF EXISTS retired;
+ALTER TABLE categories DROP COLUMN IF EXISTS translations;
+ALTER TABLE categories DROP COLUMN IF EXISTS slug;
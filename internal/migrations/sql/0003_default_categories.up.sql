@@ -0,0 +1,10 @@
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS slug text;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS translations jsonb NOT NULL DEFAULT '{}';
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS retired boolean NOT NULL DEFAULT false;
+CREATE UNIQUE INDEX IF NOT EXISTS uni_categories_default_slug ON categories (slug) WHERE user_id IS NULL;
+
+-- Категория «Без категории» могла быть создана вручную до появления кодов
+UPDATE categories SET slug = 'uncategorized', is_default = true
+WHERE id = (SELECT min(id) FROM categories WHERE name = 'Без категории' AND user_id IS NULL);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin boolean NOT NULL DEFAULT false;
@@ -0,0 +1,13 @@
+ALTER TABLE users ALTER COLUMN bonus DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN bonus TYPE decimal USING bonus / 100.0;
+ALTER TABLE users ALTER COLUMN bonus SET DEFAULT 0;
+
+ALTER TABLE users ALTER COLUMN balance DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN balance TYPE decimal USING balance / 100.0;
+ALTER TABLE users ALTER COLUMN balance SET DEFAULT 0;
+
+ALTER TABLE transactions ALTER COLUMN bonus_change DROP DEFAULT;
+ALTER TABLE transactions ALTER COLUMN bonus_change TYPE decimal USING bonus_change / 100.0;
+ALTER TABLE transactions ALTER COLUMN bonus_change SET DEFAULT 0;
+
+ALTER TABLE transactions ALTER COLUMN amount TYPE decimal USING amount / 100.0;
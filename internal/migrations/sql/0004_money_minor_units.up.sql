@@ -0,0 +1,13 @@
+-- Денежные суммы хранятся целым числом копеек вместо decimal/float.
+ALTER TABLE transactions ALTER COLUMN amount TYPE bigint USING round(amount * 100)::bigint;
+ALTER TABLE transactions ALTER COLUMN bonus_change DROP DEFAULT;
+ALTER TABLE transactions ALTER COLUMN bonus_change TYPE bigint USING round(coalesce(bonus_change, 0) * 100)::bigint;
+ALTER TABLE transactions ALTER COLUMN bonus_change SET DEFAULT 0;
+
+ALTER TABLE users ALTER COLUMN balance DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN balance TYPE bigint USING round(coalesce(balance, 0) * 100)::bigint;
+ALTER TABLE users ALTER COLUMN balance SET DEFAULT 0;
+
+ALTER TABLE users ALTER COLUMN bonus DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN bonus TYPE bigint USING round(coalesce(bonus, 0) * 100)::bigint;
+ALTER TABLE users ALTER COLUMN bonus SET DEFAULT 0;
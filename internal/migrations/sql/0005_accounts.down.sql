@@ -0,0 +1,7 @@
+ALTER TABLE users ADD COLUMN balance bigint DEFAULT 0;
+UPDATE users u SET balance = coalesce((
+    SELECT sum(a.balance) FROM accounts a WHERE a.user_id = u.id AND a.deleted_at IS NULL
+), 0);
+
+ALTER TABLE transactions DROP COLUMN account_id;
+DROP TABLE accounts;
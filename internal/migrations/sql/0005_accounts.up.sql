@@ -0,0 +1,36 @@
+CREATE TABLE accounts (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id bigint NOT NULL REFERENCES users (id),
+    name varchar(100) NOT NULL,
+    type varchar(20) NOT NULL,
+    currency varchar(10) NOT NULL DEFAULT 'RUB',
+    opening_balance bigint NOT NULL DEFAULT 0,
+    balance bigint NOT NULL DEFAULT 0,
+    archived boolean NOT NULL DEFAULT false
+);
+CREATE INDEX idx_accounts_user_id ON accounts (user_id);
+CREATE INDEX idx_accounts_deleted_at ON accounts (deleted_at);
+
+-- Каждый пользователь получает основной счёт с прежним балансом.
+-- Начальный остаток подбирается так, чтобы вместе с транзакциями давать этот баланс.
+INSERT INTO accounts (created_at, updated_at, user_id, name, type, currency, opening_balance, balance)
+SELECT now(), now(), u.id, 'Основной счёт', 'card', 'RUB',
+       u.balance - coalesce(l.total, 0), u.balance
+FROM users u
+LEFT JOIN (
+    SELECT user_id,
+           sum(CASE type WHEN 'income' THEN amount WHEN 'expense' THEN -amount ELSE 0 END) AS total
+    FROM transactions
+    WHERE deleted_at IS NULL
+    GROUP BY user_id
+) l ON l.user_id = u.id;
+
+ALTER TABLE transactions ADD COLUMN account_id bigint REFERENCES accounts (id);
+UPDATE transactions t SET account_id = a.id FROM accounts a WHERE a.user_id = t.user_id;
+ALTER TABLE transactions ALTER COLUMN account_id SET NOT NULL;
+CREATE INDEX idx_transactions_account_id ON transactions (account_id);
+
+ALTER TABLE users DROP COLUMN balance;
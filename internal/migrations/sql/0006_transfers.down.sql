@@ -0,0 +1,26 @@
+-- Переводы удаляются, поэтому их влияние на балансы отменяется: счёт списания
+-- получает обратно сумму с комиссией, со счёта зачисления снимается зачисленное.
+-- Удалённые переводы уже откатили балансы при удалении.
+UPDATE accounts a SET balance = a.balance + e.total
+FROM (
+    SELECT account_id AS id, sum(amount + fee) AS total
+    FROM transactions
+    WHERE type = 'transfer' AND deleted_at IS NULL
+    GROUP BY account_id
+) e
+WHERE a.id = e.id;
+
+UPDATE accounts a SET balance = a.balance - e.total
+FROM (
+    SELECT counter_account_id AS id, sum(counter_amount) AS total
+    FROM transactions
+    WHERE type = 'transfer' AND deleted_at IS NULL AND counter_account_id IS NOT NULL
+    GROUP BY counter_account_id
+) e
+WHERE a.id = e.id;
+
+DELETE FROM transactions WHERE type = 'transfer';
+ALTER TABLE transactions DROP COLUMN rate;
+ALTER TABLE transactions DROP COLUMN fee;
+ALTER TABLE transactions DROP COLUMN counter_amount;
+ALTER TABLE transactions DROP COLUMN counter_account_id;
@@ -0,0 +1,5 @@
+ALTER TABLE transactions ADD COLUMN counter_account_id bigint REFERENCES accounts (id);
+ALTER TABLE transactions ADD COLUMN counter_amount bigint NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN fee bigint NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN rate varchar(32);
+CREATE INDEX idx_transactions_counter_account_id ON transactions (counter_account_id);
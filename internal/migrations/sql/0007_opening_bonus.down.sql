@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN opening_bonus;
@@ -0,0 +1,7 @@
+-- Начальные бонусы подбираются так, чтобы вместе с транзакциями давать текущий баланс бонусов.
+ALTER TABLE users ADD COLUMN opening_bonus bigint NOT NULL DEFAULT 0;
+UPDATE users u SET opening_bonus = coalesce(u.bonus, 0) - coalesce((
+    SELECT sum(CASE t.bonus_type WHEN 'income' THEN t.bonus_change WHEN 'expense' THEN -t.bonus_change ELSE 0 END)
+    FROM transactions t
+    WHERE t.user_id = u.id AND t.deleted_at IS NULL
+), 0);
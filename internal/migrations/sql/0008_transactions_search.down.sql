@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transactions_search;
+ALTER TABLE transactions DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,8 @@
+-- Полнотекстовый поиск по названию (вес A) и описанию (вес B) на русском и английском.
+ALTER TABLE transactions ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('russian', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_transactions_search ON transactions USING GIN (search_vector);
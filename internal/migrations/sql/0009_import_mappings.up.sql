@@ -0,0 +1,25 @@
+CREATE TABLE import_mappings (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id bigint NOT NULL REFERENCES users (id),
+    name varchar(100) NOT NULL,
+    delimiter varchar(4) NOT NULL DEFAULT ',',
+    encoding varchar(20) NOT NULL DEFAULT 'utf-8',
+    skip_rows integer NOT NULL DEFAULT 0,
+    has_header boolean NOT NULL DEFAULT true,
+    date_column varchar(100) NOT NULL,
+    date_format varchar(50) NOT NULL,
+    amount_column varchar(100),
+    income_column varchar(100),
+    expense_column varchar(100),
+    amount_sign varchar(20) NOT NULL DEFAULT 'negative_expense',
+    decimal_separator varchar(1) NOT NULL DEFAULT '.',
+    title_column varchar(100) NOT NULL,
+    description_column varchar(100),
+    category_column varchar(100),
+    currency_column varchar(100)
+);
+CREATE INDEX idx_import_mappings_user_id ON import_mappings (user_id);
+CREATE INDEX idx_import_mappings_deleted_at ON import_mappings (deleted_at);
@@ -0,0 +1,4 @@
+-- Идентификатор операции в банке (FITID) защищает от повторного импорта одной выписки.
+ALTER TABLE transactions ADD COLUMN external_id varchar(255);
+CREATE UNIQUE INDEX idx_transactions_account_external_id ON transactions (account_id, external_id)
+    WHERE external_id IS NOT NULL AND deleted_at IS NULL;
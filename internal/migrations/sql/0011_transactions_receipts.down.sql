@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transactions_receipt;
+ALTER TABLE transactions
+    DROP COLUMN fiscal_sign,
+    DROP COLUMN fiscal_document,
+    DROP COLUMN fiscal_drive;
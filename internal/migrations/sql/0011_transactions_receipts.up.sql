@@ -0,0 +1,7 @@
+-- Фискальные признаки чека: один чек нельзя добавить дважды.
+ALTER TABLE transactions
+    ADD COLUMN fiscal_drive varchar(16),
+    ADD COLUMN fiscal_document varchar(10),
+    ADD COLUMN fiscal_sign varchar(10);
+CREATE UNIQUE INDEX idx_transactions_receipt ON transactions (user_id, fiscal_drive, fiscal_document, fiscal_sign)
+    WHERE fiscal_drive IS NOT NULL AND deleted_at IS NULL;
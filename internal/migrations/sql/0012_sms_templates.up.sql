@@ -0,0 +1,14 @@
+CREATE TABLE sms_templates (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id bigint NOT NULL REFERENCES users (id),
+    name varchar(100) NOT NULL,
+    pattern text NOT NULL,
+    type varchar(10) NOT NULL,
+    category bigint,
+    account bigint REFERENCES accounts (id)
+);
+CREATE INDEX idx_sms_templates_user_id ON sms_templates (user_id);
+CREATE INDEX idx_sms_templates_deleted_at ON sms_templates (deleted_at);
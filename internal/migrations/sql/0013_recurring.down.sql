@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transactions_recurring_occurrence;
+ALTER TABLE transactions
+    DROP COLUMN occurrence,
+    DROP COLUMN recurring_id;
+DROP TABLE IF EXISTS recurring_rules;
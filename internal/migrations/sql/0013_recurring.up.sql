@@ -0,0 +1,36 @@
+CREATE TABLE recurring_rules (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id bigint NOT NULL REFERENCES users (id),
+    type varchar(10) NOT NULL,
+    account_id bigint NOT NULL REFERENCES accounts (id),
+    counter_account_id bigint REFERENCES accounts (id),
+    amount bigint NOT NULL,
+    counter_amount bigint NOT NULL DEFAULT 0,
+    category bigint NOT NULL,
+    title varchar(100) NOT NULL,
+    description text,
+    frequency varchar(10) NOT NULL,
+    interval integer NOT NULL DEFAULT 1,
+    start timestamptz NOT NULL,
+    until timestamptz,
+    count integer NOT NULL DEFAULT 0,
+    adjust varchar(20) NOT NULL DEFAULT 'none',
+    skipped jsonb NOT NULL DEFAULT '[]',
+    next_index integer NOT NULL DEFAULT 0,
+    next_run timestamptz,
+    paused boolean NOT NULL DEFAULT false
+);
+CREATE INDEX idx_recurring_rules_user_id ON recurring_rules (user_id);
+CREATE INDEX idx_recurring_rules_deleted_at ON recurring_rules (deleted_at);
+CREATE INDEX idx_recurring_rules_next_run ON recurring_rules (next_run)
+    WHERE next_run IS NOT NULL AND NOT paused AND deleted_at IS NULL;
+
+-- Повторение создаётся один раз, даже если пользователь потом удалил транзакцию.
+ALTER TABLE transactions
+    ADD COLUMN recurring_id bigint REFERENCES recurring_rules (id),
+    ADD COLUMN occurrence timestamptz;
+CREATE UNIQUE INDEX idx_transactions_recurring_occurrence ON transactions (recurring_id, occurrence)
+    WHERE recurring_id IS NOT NULL;
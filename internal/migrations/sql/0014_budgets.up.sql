@@ -0,0 +1,16 @@
+CREATE TABLE budgets (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id bigint NOT NULL REFERENCES users (id),
+    name varchar(100) NOT NULL,
+    categories jsonb NOT NULL DEFAULT '[]',
+    period varchar(10) NOT NULL,
+    limit_amount bigint NOT NULL,
+    currency varchar(10) NOT NULL DEFAULT 'RUB',
+    rollover boolean NOT NULL DEFAULT false,
+    start timestamptz NOT NULL
+);
+CREATE INDEX idx_budgets_user_id ON budgets (user_id);
+CREATE INDEX idx_budgets_deleted_at ON budgets (deleted_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS budget_alerts;
+ALTER TABLE users DROP COLUMN budget_alerts;
@@ -0,0 +1,10 @@
+ALTER TABLE users ADD COLUMN budget_alerts boolean NOT NULL DEFAULT true;
+
+CREATE TABLE budget_alerts (
+    id bigserial PRIMARY KEY,
+    budget_id bigint NOT NULL REFERENCES budgets (id) ON DELETE CASCADE,
+    period_start timestamptz NOT NULL,
+    threshold integer NOT NULL,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX idx_budget_alerts_period ON budget_alerts (budget_id, period_start, threshold);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_transactions_goal_id;
+ALTER TABLE transactions DROP COLUMN goal_id;
+DROP TABLE IF EXISTS goals;
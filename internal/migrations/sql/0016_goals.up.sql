@@ -0,0 +1,18 @@
+CREATE TABLE goals (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id bigint NOT NULL REFERENCES users (id),
+    name varchar(100) NOT NULL,
+    description text,
+    target bigint NOT NULL,
+    currency varchar(10) NOT NULL,
+    account_id bigint NOT NULL REFERENCES accounts (id),
+    deadline timestamptz
+);
+CREATE INDEX idx_goals_user_id ON goals (user_id);
+CREATE INDEX idx_goals_deleted_at ON goals (deleted_at);
+
+ALTER TABLE transactions ADD COLUMN goal_id bigint REFERENCES goals (id);
+CREATE INDEX idx_transactions_goal_id ON transactions (goal_id) WHERE goal_id IS NOT NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS verification_expires_at,
+    DROP COLUMN IF EXISTS verification_attempts,
+    DROP COLUMN IF EXISTS verification_sent_at;
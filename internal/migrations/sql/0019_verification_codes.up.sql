@@ -0,0 +1,8 @@
+ALTER TABLE users
+    ADD COLUMN verification_expires_at timestamptz,
+    ADD COLUMN verification_attempts integer NOT NULL DEFAULT 0,
+    ADD COLUMN verification_sent_at timestamptz;
+
+-- Старые коды хранились открытым текстом и не имели срока действия:
+-- неподтверждённым пользователям нужно запросить новый код.
+UPDATE users SET verification_code = '' WHERE verification_code <> '';
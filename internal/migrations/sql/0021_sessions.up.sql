@@ -0,0 +1,22 @@
+CREATE TABLE sessions (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id),
+    token_id varchar(64) NOT NULL,
+    device_name varchar(100),
+    user_agent varchar(255),
+    ip varchar(45),
+    created_at timestamptz,
+    last_used_at timestamptz,
+    expires_at timestamptz NOT NULL,
+    revoked_at timestamptz
+);
+CREATE UNIQUE INDEX idx_sessions_token_id ON sessions (token_id);
+CREATE INDEX idx_sessions_user_id ON sessions (user_id);
+
+-- Действующие входы, выданные до появления сессий, сохраняются без сведений об устройстве.
+INSERT INTO sessions (user_id, token_id, created_at, last_used_at, expires_at)
+SELECT user_id, family_id, min(created_at), max(created_at), max(expires_at)
+FROM refresh_tokens
+WHERE revoked_at IS NULL
+GROUP BY user_id, family_id
+HAVING max(expires_at) > now();
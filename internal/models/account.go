@@ -0,0 +1,28 @@
+package models
+
+import (
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"gorm.io/gorm"
+)
+
+type AccountType string
+
+const (
+	AccountCard    AccountType = "card"    // дебетовая карта
+	AccountCredit  AccountType = "credit"  // кредитная карта
+	AccountCash    AccountType = "cash"    // наличные
+	AccountSavings AccountType = "savings" // накопительный счёт
+)
+
+// Account — счёт или кошелёк пользователя. Balance равен OpeningBalance
+// плюс влияние всех транзакций по счёту.
+type Account struct {
+	gorm.Model
+	UserID         uint         `gorm:"not null;index"`
+	Name           string       `gorm:"type:varchar(100);not null"`
+	Type           AccountType  `gorm:"type:varchar(20);not null"`
+	Currency       string       `gorm:"type:varchar(10);not null;default:'RUB'"`
+	OpeningBalance money.Amount `gorm:"type:bigint;not null;default:0" swaggertype:"number"`
+	Balance        money.Amount `gorm:"type:bigint;not null;default:0" swaggertype:"number"`
+	Archived       bool         `gorm:"not null;default:false"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"gorm.io/gorm"
+)
+
+// Budget — лимит расходов по категории или группе категорий за период
+// (неделю, месяц или год). Без категорий бюджет охватывает все расходы.
+// Учитываются только расходы в валюте бюджета.
+type Budget struct {
+	gorm.Model
+	UserID     uint         `gorm:"not null;index"`
+	Name       string       `gorm:"type:varchar(100);not null"`
+	Categories IDList       `gorm:"type:jsonb;not null;default:'[]'"`
+	Period     Frequency    `gorm:"type:varchar(10);not null"` // weekly, monthly или yearly
+	Limit      money.Amount `gorm:"column:limit_amount;type:bigint;not null" swaggertype:"number"`
+	Currency   string       `gorm:"type:varchar(10);not null;default:'RUB'"`
+	// Rollover переносит остаток (или перерасход) каждого периода на следующий.
+	Rollover bool      `gorm:"not null;default:false"`
+	Start    time.Time `gorm:"not null"` // начало первого периода бюджета
+}
+
+// BudgetAlert — отправленное уведомление о достижении порога бюджета.
+// Каждый порог отправляется один раз за период.
+type BudgetAlert struct {
+	ID          uint      `gorm:"primaryKey"`
+	BudgetID    uint      `gorm:"not null"`
+	PeriodStart time.Time `gorm:"not null"`
+	Threshold   int       `gorm:"not null"` // процент от доступной суммы
+	CreatedAt   time.Time
+}
+
+// IDList — список идентификаторов.
+type IDList []uint
+
+// Contains сообщает, есть ли id в списке.
+func (l IDList) Contains(id uint) bool {
+	for _, v := range l {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (l IDList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(l)
+	return string(b), err
+}
+
+func (l *IDList) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*l = IDList{}
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("неподдерживаемый тип для IDList")
+	}
+	return json.Unmarshal(b, l)
+}
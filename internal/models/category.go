@@ -1,13 +1,60 @@
 package models
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// UncategorizedSlug — код встроенной категории, в которую переносятся
+// транзакции удалённых категорий.
+const UncategorizedSlug = "uncategorized"
 
 type Category struct {
-	ID        uint   `gorm:"primaryKey"`
-	Name      string `gorm:"not null"`
-	UserID    *uint  // nil для дефолтных категорий
-	Color     string `gorm:"type:text;not null;default:'#16a34a'"`
-	IsDefault bool   `gorm:"not null;default:false"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID           uint         `gorm:"primaryKey"`
+	Name         string       `gorm:"not null"`
+	UserID       *uint        // nil для дефолтных категорий
+	Color        string       `gorm:"type:text;not null;default:'#16a34a'"`
+	IsDefault    bool         `gorm:"not null;default:false"`
+	Slug         *string      // код дефолтной категории, у пользовательских nil
+	Translations Translations `gorm:"type:jsonb;not null;default:'{}'"`
+	Retired      bool         `gorm:"not null;default:false"` // дефолтная категория скрыта для новых транзакций
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// LocalizedName возвращает название категории на указанном языке, если перевод есть.
+func (c Category) LocalizedName(lang string) string {
+	if name, ok := c.Translations[lang]; ok && name != "" {
+		return name
+	}
+	return c.Name
+}
+
+// Translations хранит названия категории по кодам языков ("en", "ru").
+type Translations map[string]string
+
+func (t Translations) Value() (driver.Value, error) {
+	if t == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(t)
+	return string(b), err
+}
+
+func (t *Translations) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*t = Translations{}
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("неподдерживаемый тип для Translations")
+	}
+	return json.Unmarshal(b, t)
 }
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"gorm.io/gorm"
+)
+
+// Goal — цель накоплений. Деньги цели лежат на связанном счёте, а накоплено
+// столько, сколько внесли на этот счёт транзакции цели за вычетом снятий.
+// На одном счёте могут копиться несколько целей.
+type Goal struct {
+	gorm.Model
+	UserID      uint         `gorm:"not null;index"`
+	Name        string       `gorm:"type:varchar(100);not null"`
+	Description string       `gorm:"type:text"`
+	Target      money.Amount `gorm:"type:bigint;not null" swaggertype:"number"`
+	Currency    string       `gorm:"type:varchar(10);not null"` // валюта связанного счёта
+	AccountID   uint         `gorm:"not null"`
+	Deadline    *time.Time
+}
+
+// Contribution возвращает изменение накоплений цели от транзакции t:
+// её влияние на баланс счёта цели.
+func (g Goal) Contribution(t Transaction) money.Amount {
+	var effect money.Amount
+	if t.AccountID == g.AccountID {
+		effect += t.BalanceEffect()
+	}
+	if t.CounterAccountID != nil && *t.CounterAccountID == g.AccountID {
+		effect += t.CounterEffect()
+	}
+	return effect
+}
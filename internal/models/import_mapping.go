@@ -0,0 +1,33 @@
+package models
+
+import "gorm.io/gorm"
+
+// Способы определить тип операции по сумме в выписке.
+const (
+	SignNegativeExpense = "negative_expense" // отрицательная сумма — расход
+	SignNegativeIncome  = "negative_income"  // отрицательная сумма — доход, как в выписках кредитных карт
+	SignSeparateColumns = "separate"         // приход и расход в разных колонках
+)
+
+// ImportMapping — сохранённое описание формата CSV-выписки банка.
+// Колонки задаются названием из заголовка или номером, начиная с 1.
+type ImportMapping struct {
+	gorm.Model
+	UserID            uint   `gorm:"not null;index"`
+	Name              string `gorm:"type:varchar(100);not null"`
+	Delimiter         string `gorm:"type:varchar(4);not null;default:','"`
+	Encoding          string `gorm:"type:varchar(20);not null;default:'utf-8'"` // utf-8 или cp1251
+	SkipRows          int    `gorm:"not null;default:0"`                        // строки перед заголовком
+	HasHeader         bool   `gorm:"not null;default:true"`
+	DateColumn        string `gorm:"type:varchar(100);not null"`
+	DateFormat        string `gorm:"type:varchar(50);not null"` // например DD.MM.YYYY HH:mm
+	AmountColumn      string `gorm:"type:varchar(100)"`
+	IncomeColumn      string `gorm:"type:varchar(100)"`
+	ExpenseColumn     string `gorm:"type:varchar(100)"`
+	AmountSign        string `gorm:"type:varchar(20);not null;default:'negative_expense'"`
+	DecimalSeparator  string `gorm:"type:varchar(1);not null;default:'.'"`
+	TitleColumn       string `gorm:"type:varchar(100);not null"`
+	DescriptionColumn string `gorm:"type:varchar(100)"`
+	CategoryColumn    string `gorm:"type:varchar(100)"` // название категории пользователя или встроенной
+	CurrencyColumn    string `gorm:"type:varchar(100)"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// PasswordReset — одноразовый токен сброса пароля. Хранится только SHA-256 хеш.
+type PasswordReset struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// RecoveryCode — одноразовый код восстановления доступа при двухфакторной
+// аутентификации. Хранится только SHA-256 хеш.
+type RecoveryCode struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	CodeHash  string `gorm:"type:varchar(64);not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"gorm.io/gorm"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "daily"
+	Weekly  Frequency = "weekly"
+	Monthly Frequency = "monthly"
+	Yearly  Frequency = "yearly"
+)
+
+// BusinessDayAdjustment — перенос повторения, выпавшего на субботу или воскресенье.
+type BusinessDayAdjustment string
+
+const (
+	AdjustNone              BusinessDayAdjustment = "none"
+	AdjustFollowing         BusinessDayAdjustment = "following"          // на следующий рабочий день
+	AdjustPreceding         BusinessDayAdjustment = "preceding"          // на предыдущий рабочий день
+	AdjustModifiedFollowing BusinessDayAdjustment = "modified_following" // на следующий, если он в том же месяце, иначе на предыдущий
+)
+
+// RecurringRule — повторяющаяся операция: шаблон транзакции и расписание
+// в духе RRULE. Повторения нумеруются с нуля от Start; n-е повторение
+// приходится на Start плюс n*Interval единиц Frequency, затем переносится
+// с выходных по Adjust. Транзакции создаёт планировщик.
+type RecurringRule struct {
+	gorm.Model
+	UserID uint `gorm:"not null;index"`
+
+	// Шаблон транзакции
+	Type             TransactionType `gorm:"type:varchar(10);not null"`
+	AccountID        uint            `gorm:"not null"`
+	CounterAccountID *uint           // счёт зачисления для переводов
+	Amount           money.Amount    `gorm:"type:bigint;not null" swaggertype:"number"`
+	CounterAmount    money.Amount    `gorm:"type:bigint;not null;default:0" swaggertype:"number"`
+	Category         uint            `gorm:"not null"`
+	Title            string          `gorm:"type:varchar(100);not null"`
+	Description      string          `gorm:"type:text"`
+
+	// Расписание
+	Frequency Frequency             `gorm:"type:varchar(10);not null"`
+	Interval  int                   `gorm:"not null;default:1"`
+	Start     time.Time             `gorm:"not null"` // первое повторение вместе со временем суток
+	Until     *time.Time            // последний день повторений включительно
+	Count     int                   `gorm:"not null;default:0"` // число повторений, 0 — без ограничения
+	Adjust    BusinessDayAdjustment `gorm:"type:varchar(20);not null;default:'none'"`
+	Skipped   DateList              `gorm:"type:jsonb;not null;default:'[]'"` // пропущенные повторения по дате по расписанию
+
+	// Состояние планировщика: номер и дата следующего несозданного повторения.
+	// NextRun пуст, когда повторения закончились.
+	NextIndex int `gorm:"not null;default:0"`
+	NextRun   *time.Time
+	Paused    bool `gorm:"not null;default:false"`
+}
+
+// DateList — список дней в формате YYYY-MM-DD.
+type DateList []string
+
+// Contains сообщает, есть ли в списке день даты t.
+func (d DateList) Contains(t time.Time) bool {
+	day := t.Format("2006-01-02")
+	for _, v := range d {
+		if v == day {
+			return true
+		}
+	}
+	return false
+}
+
+func (d DateList) Value() (driver.Value, error) {
+	if d == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(d)
+	return string(b), err
+}
+
+func (d *DateList) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*d = DateList{}
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("неподдерживаемый тип для DateList")
+	}
+	return json.Unmarshal(b, d)
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// RefreshToken — выданный refresh-токен. Хранится только SHA-256 хеш.
+// При обновлении токен помечается использованным и заменяется новым
+// с тем же FamilyID: цепочка токенов одного входа образует семейство.
+// Повторное предъявление использованного токена означает утечку,
+// и всё семейство отзывается.
+type RefreshToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	FamilyID  string    `gorm:"type:varchar(64);not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Session — вход пользователя с одного устройства. TokenID совпадает с
+// семейством refresh-токенов и claim sid access-токенов: отзыв сессии
+// отзывает её refresh-токены и сразу запрещает её access-токены.
+type Session struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"not null;index"`
+	TokenID    string `gorm:"type:varchar(64);not null;uniqueIndex"`
+	DeviceName string `gorm:"type:varchar(100)"`
+	UserAgent  string `gorm:"type:varchar(255)"`
+	IP         string `gorm:"type:varchar(45)"`
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	ExpiresAt  time.Time `gorm:"not null"` // срок действия последнего refresh-токена
+	RevokedAt  *time.Time
+}
@@ -0,0 +1,16 @@
+package models
+
+import "gorm.io/gorm"
+
+// SMSTemplate — пользовательский шаблон разбора SMS или push-уведомления банка.
+// Pattern — регулярное выражение с именованными группами или подстановками
+// {amount}, {currency}, {merchant}, {balance}, {card}, {date}, {time}.
+type SMSTemplate struct {
+	gorm.Model
+	UserID   uint            `gorm:"not null;index"`
+	Name     string          `gorm:"type:varchar(100);not null"`
+	Pattern  string          `gorm:"type:text;not null"`
+	Type     TransactionType `gorm:"type:varchar(10);not null"` // income или expense
+	Category *uint           // категория для черновика, если задана
+	Account  *uint           // счёт для черновика, если задан
+}
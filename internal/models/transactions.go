@@ -3,21 +3,24 @@ package models
 import (
 	"time"
 
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
 	"gorm.io/gorm"
 )
 
 type TransactionType string
 
 const (
-	Income  TransactionType = "income"
-	Expense TransactionType = "expense"
+	Income   TransactionType = "income"
+	Expense  TransactionType = "expense"
+	Transfer TransactionType = "transfer" // перевод между счетами, не доход и не расход
 )
 
 type Transaction struct {
 	gorm.Model
 	UserID      uint            `gorm:"not null"`
-	Amount      float64         `gorm:"not null"`
-	BonusChange float64         `gorm:"default:0"`
+	AccountID   uint            `gorm:"not null"`
+	Amount      money.Amount    `gorm:"type:bigint;not null" swaggertype:"number"`
+	BonusChange money.Amount    `gorm:"type:bigint;default:0" swaggertype:"number"`
 	BonusType   TransactionType `gorm:"type:varchar(10)"`
 	Currency    string          `gorm:"type:varchar(10);default:'RUB'"`
 	Date        time.Time       `gorm:"not null"`
@@ -25,4 +28,57 @@ type Transaction struct {
 	Description string          `gorm:"type:text"`
 	Category    uint            `gorm:"not null"`
 	Type        TransactionType `gorm:"type:varchar(10);not null"` // income или expense //доход или расход
+	ExternalID  *string         `gorm:"type:varchar(255)"`         // идентификатор операции в банке (FITID) для импорта без дублей
+
+	// Фискальные признаки кассового чека из QR-кода: номер фискального
+	// накопителя (fn), номер документа (i) и фискальный признак (fp).
+	FiscalDrive    *string `gorm:"type:varchar(16)"`
+	FiscalDocument *string `gorm:"type:varchar(10)"`
+	FiscalSign     *string `gorm:"type:varchar(10)"`
+
+	// Повторяющаяся операция, создавшая транзакцию, и дата повторения
+	// по расписанию: по этой паре повторение не создаётся дважды.
+	RecurringID *uint
+	Occurrence  *time.Time
+
+	GoalID *uint // цель накоплений, для взносов и снятий
+
+	// Поля перевода: Amount списывается с AccountID вместе с Fee,
+	// CounterAmount зачисляется на CounterAccountID в его валюте.
+	CounterAccountID *uint
+	CounterAmount    money.Amount `gorm:"type:bigint;not null;default:0" swaggertype:"number"`
+	Fee              money.Amount `gorm:"type:bigint;not null;default:0" swaggertype:"number"`
+	Rate             string       `gorm:"type:varchar(32)"` // курс пересчёта для переводов между валютами
+}
+
+// BalanceEffect возвращает изменение баланса счёта от транзакции.
+func (t Transaction) BalanceEffect() money.Amount {
+	switch t.Type {
+	case Income:
+		return t.Amount
+	case Expense:
+		return -t.Amount
+	case Transfer:
+		return -(t.Amount + t.Fee)
+	}
+	return 0
+}
+
+// CounterEffect возвращает изменение баланса счёта-получателя перевода.
+func (t Transaction) CounterEffect() money.Amount {
+	if t.Type != Transfer {
+		return 0
+	}
+	return t.CounterAmount
+}
+
+// BonusEffect возвращает изменение бонусного баланса пользователя от транзакции.
+func (t Transaction) BonusEffect() money.Amount {
+	switch t.BonusType {
+	case Income:
+		return t.BonusChange
+	case Expense:
+		return -t.BonusChange
+	}
+	return 0
 }
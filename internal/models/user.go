@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"gorm.io/gorm"
+)
+
+type User struct {
+	gorm.Model
+	Username     string       `gorm:"type:varchar(100);not null"`
+	Email        string       `gorm:"type:varchar(100);unique;not null"`
+	Password     string       `gorm:"not null"`
+	Bonus        money.Amount `gorm:"type:bigint;default:0"`
+	OpeningBonus money.Amount `gorm:"type:bigint;not null;default:0"` // бонусы, не объяснённые транзакциями
+	Verified     bool         `gorm:"default:false"`
+	IsAdmin      bool         `gorm:"not null;default:false"`
+
+	// Код подтверждения почты: хранится HMAC-SHA256 с секретом сервера (JWT_KEY),
+	// код действует до VerificationExpiresAt и допускает ограниченное число попыток ввода
+	VerificationCode      string
+	VerificationExpiresAt *time.Time
+	VerificationAttempts  int        `gorm:"not null;default:0"`
+	VerificationSentAt    *time.Time // для ограничения частоты повторной отправки
+
+	// Двухфакторная аутентификация TOTP. Секрет сохраняется при начале
+	// подключения и действует только после подтверждения кодом
+	TOTPSecret         string     `gorm:"column:totp_secret"`
+	TOTPEnabled        bool       `gorm:"column:totp_enabled;not null;default:false"`
+	TOTPLastStep       int64      `gorm:"column:totp_last_step;not null;default:0"` // последний принятый интервал, защищает от повторного ввода кода
+	TOTPFailedAttempts int        `gorm:"column:totp_failed_attempts;not null;default:0"`
+	TOTPLockedUntil    *time.Time `gorm:"column:totp_locked_until"`
+
+	// Настройки уведомлений
+	BudgetAlerts bool `gorm:"not null;default:true"` // письма о расходовании бюджетов
+}
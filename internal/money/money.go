@@ -0,0 +1,238 @@
+// Package money содержит точное представление денежных сумм.
+//
+// Суммы хранятся целым числом минорных единиц (копеек, центов), поэтому
+// сложение и вычитание не накапливают ошибок округления. Для всех валют
+// используется два знака после запятой.
+package money
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scale — количество минорных единиц в одной основной единице валюты.
+const Scale = 100
+
+// Amount — денежная сумма в минорных единицах.
+// В JSON записывается числом с двумя знаками после запятой: 1250.50.
+type Amount int64
+
+var (
+	ErrInvalid  = errors.New("неверный формат суммы")
+	ErrOverflow = errors.New("сумма слишком велика")
+)
+
+// FromMinor создаёт сумму из минорных единиц.
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// FromUnits создаёт сумму из целого количества основных единиц.
+func FromUnits(units int64) Amount {
+	return Amount(units * Scale)
+}
+
+// Minor возвращает сумму в минорных единицах.
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Abs возвращает модуль суммы.
+func (a Amount) Abs() Amount {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Float возвращает приближённое значение суммы. Используется только для
+// отображения и оценок, но не для расчёта балансов.
+func (a Amount) Float() float64 {
+	return float64(a) / Scale
+}
+
+// MulRatio умножает сумму на num/den с округлением до ближайшей минорной единицы.
+// Промежуточное произведение считается без ограничения разрядности; если
+// результат не помещается в Amount или den равен нулю, возвращается ErrOverflow.
+func (a Amount) MulRatio(num, den int64) (Amount, error) {
+	if den == 0 {
+		return 0, ErrOverflow
+	}
+	p := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(num))
+	d := big.NewInt(den)
+	q, r := new(big.Int).QuoRem(p, d, new(big.Int))
+	// Округление половины от нуля: |2r| >= |den|
+	if r.Sign() != 0 && new(big.Int).Abs(r.Lsh(r, 1)).Cmp(d.Abs(d)) >= 0 {
+		if (p.Sign() < 0) == (den < 0) {
+			q.Add(q, big.NewInt(1))
+		} else {
+			q.Sub(q, big.NewInt(1))
+		}
+	}
+	if !q.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return Amount(q.Int64()), nil
+}
+
+// Div делит сумму на положительное n с округлением до ближайшей минорной единицы.
+// В отличие от MulRatio не может переполниться.
+func (a Amount) Div(n int64) Amount {
+	if n <= 0 {
+		return 0
+	}
+	q, r := int64(a)/n, int64(a)%n
+	if abs64(r) >= n-abs64(r) {
+		if a < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return Amount(q)
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// String форматирует сумму как "1250.50" или "-0.05".
+func (a Amount) String() string {
+	v := int64(a)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/Scale, v%Scale)
+}
+
+// Parse разбирает десятичную запись суммы без перевода через float64.
+// Допускаются знак, пробелы между разрядами и запятая или точка в качестве
+// десятичного разделителя. Больше двух значащих знаков после запятой — ошибка.
+func Parse(s string) (Amount, error) {
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\u202f', '\'':
+			return -1
+		}
+		return r
+	}, s)
+	if s == "" {
+		return 0, ErrInvalid
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(strings.Replace(s, ",", ".", 1), ".")
+	if whole == "" && frac == "" {
+		return 0, ErrInvalid
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("%w: больше двух знаков после запятой", ErrInvalid)
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+
+	if len(whole) > 16 {
+		return 0, fmt.Errorf("%w: слишком большая сумма", ErrInvalid)
+	}
+	units, err := strconv.ParseUint(whole, 10, 64)
+	if err != nil {
+		return 0, ErrInvalid
+	}
+	cents, err := strconv.ParseUint(frac, 10, 64)
+	if err != nil {
+		return 0, ErrInvalid
+	}
+
+	value := int64(units)*Scale + int64(cents)
+	if negative {
+		value = -value
+	}
+	return Amount(value), nil
+}
+
+// MustParse работает как Parse, но паникует при ошибке. Для констант в коде.
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON принимает как число (1250.5), так и строку ("1250,50").
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// UnmarshalParam позволяет gin разбирать сумму из query-параметров.
+func (a *Amount) UnmarshalParam(param string) error {
+	parsed, err := Parse(param)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Convert пересчитывает сумму по курсу, заданному десятичной строкой ("92.4512"),
+// с округлением до минорной единицы. Курс должен быть положительным.
+func Convert(a Amount, rate string) (Amount, error) {
+	rate = strings.TrimSpace(strings.Replace(rate, ",", ".", 1))
+	whole, frac, _ := strings.Cut(rate, ".")
+	frac = strings.TrimRight(frac, "0")
+	if whole == "" {
+		whole = "0"
+	}
+	if len(frac) > 8 || len(whole) > 9 {
+		return 0, errors.New("неверный формат курса")
+	}
+
+	num, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil || num <= 0 {
+		return 0, errors.New("неверный формат курса")
+	}
+	den := int64(1)
+	for range frac {
+		den *= 10
+	}
+	return a.MulRatio(num, den)
+}
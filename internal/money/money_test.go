@@ -0,0 +1,181 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Amount
+		wantErr bool
+	}{
+		{in: "0", want: 0},
+		{in: "1250.50", want: 125050},
+		{in: "1250,5", want: 125050},
+		{in: "-0.05", want: -5},
+		{in: "+12", want: 1200},
+		{in: ".5", want: 50},
+		{in: "7.", want: 700},
+		{in: "1 250 000,00", want: 125000000},
+		{in: "1 250.10", want: 125010},
+		{in: "1'000", want: 100000},
+		{in: "3.1000", want: 310},
+		{in: "9999999999999999.99", want: 999999999999999999},
+		{in: "", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "1.234", wantErr: true},
+		{in: "1.2.3", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "1e5", wantErr: true},
+		{in: "--1", wantErr: true},
+		{in: "10000000000000000", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("Parse(%q) error = %v, want ErrInvalid", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in   Amount
+		want string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{-5, "-0.05"},
+		{125050, "1250.50"},
+		{-100, "-1.00"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Amount(%d).String() = %q, want %q", int64(tt.in), got, tt.want)
+		}
+	}
+}
+
+func TestMulRatio(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        Amount
+		num, den int64
+		want     Amount
+		wantErr  bool
+	}{
+		{name: "точное", a: 100, num: 3, den: 2, want: 150},
+		{name: "половина вверх", a: 150, num: 1, den: 2, want: 75},
+		{name: "округление вверх", a: 149, num: 1, den: 2, want: 75},
+		{name: "округление вниз", a: 101, num: 1, den: 3, want: 34},
+		{name: "отрицательная сумма", a: -149, num: 1, den: 2, want: -75},
+		{name: "отрицательный знаменатель", a: 5, num: 1, den: -2, want: -3},
+		{name: "оба отрицательные", a: -5, num: -1, den: 2, want: 3},
+		{name: "большое произведение", a: math.MaxInt64 / 2, num: 4, den: 2, want: math.MaxInt64 - 1},
+		{name: "переполнение", a: math.MaxInt64, num: 2, den: 1, wantErr: true},
+		{name: "переполнение вниз", a: math.MinInt64, num: 2, den: 1, wantErr: true},
+		{name: "деление на ноль", a: 10, num: 1, den: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.MulRatio(tt.num, tt.den)
+			if tt.wantErr {
+				if !errors.Is(err, ErrOverflow) {
+					t.Fatalf("MulRatio() error = %v, want ErrOverflow", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("MulRatio() = %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiv(t *testing.T) {
+	tests := []struct {
+		a    Amount
+		n    int64
+		want Amount
+	}{
+		{100, 10, 10},
+		{15, 10, 2},
+		{14, 10, 1},
+		{-15, 10, -2},
+		{-14, 10, -1},
+		{5, 2, 3},
+		{math.MaxInt64, math.MaxInt64, 1},
+		{10, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Div(tt.n); got != tt.want {
+			t.Errorf("Amount(%d).Div(%d) = %d, want %d", int64(tt.a), tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		a       Amount
+		rate    string
+		want    Amount
+		wantErr bool
+	}{
+		{a: 10000, rate: "92.4512", want: 924512},
+		{a: 10000, rate: "92,45", want: 924500},
+		{a: 100, rate: "0.015", want: 2},
+		{a: 100, rate: "1", want: 100},
+		{a: 100, rate: " 2.50000000000 ", want: 250},
+		{a: -100, rate: "0.5", want: -50},
+		{a: 999999999999999999, rate: "92.45", wantErr: true},
+		{a: 100, rate: "0", wantErr: true},
+		{a: 100, rate: "-1", wantErr: true},
+		{a: 100, rate: "abc", wantErr: true},
+		{a: 100, rate: "1.123456789", wantErr: true},
+		{a: 100, rate: "1234567890", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.a, tt.rate)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Convert(%d, %q) = %d, want error", int64(tt.a), tt.rate, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Convert(%d, %q) = %d, %v, want %d", int64(tt.a), tt.rate, got, err, tt.want)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+	}{
+		{`1250.5`, 125050},
+		{`"1250,50"`, 125050},
+		{`-3`, -300},
+	}
+	for _, tt := range tests {
+		var got Amount
+		if err := got.UnmarshalJSON([]byte(tt.in)); err != nil || got != tt.want {
+			t.Errorf("UnmarshalJSON(%s) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+
+	got := Amount(7)
+	if err := got.UnmarshalJSON([]byte("null")); err != nil || got != 7 {
+		t.Errorf("UnmarshalJSON(null) = %d, %v, want unchanged", got, err)
+	}
+}
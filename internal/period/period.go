@@ -0,0 +1,77 @@
+// Package period вычисляет границы относительных периодов вроде «этот месяц».
+//
+// Все периоды полуоткрытые: начало включается, конец — нет. Неделя
+// начинается с понедельника.
+package period
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	Today      = "today"
+	Yesterday  = "yesterday"
+	ThisWeek   = "this_week"
+	LastWeek   = "last_week"
+	ThisMonth  = "this_month"
+	LastMonth  = "last_month"
+	ThisYear   = "this_year"
+	LastYear   = "last_year"
+	Last7Days  = "last_7_days"
+	Last30Days = "last_30_days"
+)
+
+// Names перечисляет поддерживаемые периоды.
+var Names = []string{Today, Yesterday, ThisWeek, LastWeek, ThisMonth, LastMonth, ThisYear, LastYear, Last7Days, Last30Days}
+
+// Range возвращает границы периода name относительно now в часовом поясе now.
+func Range(name string, now time.Time) (from, to time.Time, err error) {
+	day := StartOfDay(now)
+	switch name {
+	case Today:
+		return day, day.AddDate(0, 0, 1), nil
+	case Yesterday:
+		return day.AddDate(0, 0, -1), day, nil
+	case ThisWeek:
+		from = StartOfWeek(now)
+		return from, from.AddDate(0, 0, 7), nil
+	case LastWeek:
+		to = StartOfWeek(now)
+		return to.AddDate(0, 0, -7), to, nil
+	case ThisMonth:
+		from = StartOfMonth(now)
+		return from, from.AddDate(0, 1, 0), nil
+	case LastMonth:
+		to = StartOfMonth(now)
+		return to.AddDate(0, -1, 0), to, nil
+	case ThisYear:
+		from = time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+		return from, from.AddDate(1, 0, 0), nil
+	case LastYear:
+		to = time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+		return to.AddDate(-1, 0, 0), to, nil
+	case Last7Days:
+		to = day.AddDate(0, 0, 1)
+		return to.AddDate(0, 0, -7), to, nil
+	case Last30Days:
+		to = day.AddDate(0, 0, 1)
+		return to.AddDate(0, 0, -30), to, nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("Неизвестный период %q", name)
+}
+
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek возвращает начало понедельника недели, в которую попадает t.
+func StartOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return StartOfDay(t).AddDate(0, 0, -offset)
+}
+
+func StartOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,503 @@
+package recurring
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/httputil"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/Anabol1ks/pers-fin-m/internal/transactions"
+	"github.com/gin-gonic/gin"
+)
+
+// Ограничения запроса ближайших повторений.
+const (
+	defaultUpcomingDays = 30
+	maxUpcomingDays     = 366
+	maxUpcoming         = 1000
+)
+
+type Handler struct {
+	store repository.Store
+}
+
+func NewHandler(store repository.Store) *Handler {
+	return &Handler{store: store}
+}
+
+type RuleInput struct {
+	Type           string        `json:"type" binding:"required,oneof=income expense transfer"`
+	Account        uint          `json:"account"`        // если не указан, используется основной счёт
+	CounterAccount uint          `json:"counterAccount"` // счёт зачисления перевода
+	Amount         money.Amount  `json:"amount" binding:"required" swaggertype:"number"`
+	CounterAmount  *money.Amount `json:"counterAmount" swaggertype:"number"` // для перевода между валютами
+	Category       uint          `json:"category"`
+	Title          string        `json:"title" binding:"required,max=100"`
+	Description    string        `json:"description"`
+
+	Frequency string     `json:"frequency" binding:"required,oneof=daily weekly monthly yearly"`
+	Interval  int        `json:"interval" binding:"min=0,max=1000"` // по умолчанию 1
+	Start     time.Time  `json:"start"`                             // первое повторение, по умолчанию сейчас
+	Until     *time.Time `json:"until"`                             // последний день повторений включительно
+	Count     int        `json:"count" binding:"min=0"`             // число повторений, 0 — без ограничения
+	Adjust    string     `json:"adjust" binding:"omitempty,oneof=none following preceding modified_following"`
+	Paused    bool       `json:"paused"`
+}
+
+type SkipInput struct {
+	Date string `json:"date" binding:"required" example:"2026-11-01"` // дата повторения по расписанию или после переноса, YYYY-MM-DD
+}
+
+// Upcoming — будущее повторение правила.
+type Upcoming struct {
+	Occurrence
+	Rule    uint                   `json:"rule"`
+	Type    models.TransactionType `json:"type"`
+	Account uint                   `json:"account"`
+	Amount  money.Amount           `json:"amount" swaggertype:"number"`
+	Title   string                 `json:"title"`
+}
+
+// @Security BearerAuth
+// ListRules godoc
+// @Summary Список повторяющихся операций
+// @Tags Recurring
+// @Produce json
+// @Success 200 {array} models.RecurringRule
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении правил"
+// @Router /recurring [get]
+func (h *Handler) ListRules(c *gin.Context) {
+	rules, err := h.store.RecurringRules().ListForUser(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении правил"})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// @Security BearerAuth
+// GetRule godoc
+// @Summary Получить повторяющуюся операцию
+// @Tags Recurring
+// @Produce json
+// @Param id path string true "ID правила"
+// @Success 200 {object} models.RecurringRule
+// @Failure 404 {object} response.ErrorResponse "Правило не найдено"
+// @Router /recurring/{id} [get]
+func (h *Handler) GetRule(c *gin.Context) {
+	rule, ok := h.ownedRule(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Security BearerAuth
+// CreateRule godoc
+// @Summary Создать повторяющуюся операцию
+// @Description Создаёт правило: шаблон транзакции (доход, расход или перевод) и расписание — частоту, интервал,
+// @Description дату окончания until или число повторений count и перенос с выходных adjust (для ежедневных правил недоступен).
+// @Description Транзакции создаёт планировщик, наступившие повторения — сразу, в том числе с даты начала в прошлом.
+// @Description Созданные транзакции помечены полями RecurringID и Occurrence.
+// @Tags Recurring
+// @Accept json
+// @Produce json
+// @Param input body RuleInput true "Правило"
+// @Success 201 {object} models.RecurringRule
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 500 {object} response.ErrorResponse "Ошибка сохранения правила"
+// @Router /recurring [post]
+func (h *Handler) CreateRule(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input RuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, errMsg := h.rule(userID, input)
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+	Schedule(rule)
+
+	if err := h.store.RecurringRules().Create(rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении правила"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.generate(rule))
+}
+
+// @Security BearerAuth
+// UpdateRule godoc
+// @Summary Изменить серию повторяющихся операций
+// @Description Заменяет правило целиком. Изменения касаются только будущих повторений, созданные транзакции не меняются.
+// @Description При изменении расписания серия продолжается с первого повторения нового расписания после последнего созданного.
+// @Description paused=true приостанавливает создание транзакций. Без start сохраняется прежняя дата начала.
+// @Tags Recurring
+// @Accept json
+// @Produce json
+// @Param id path string true "ID правила"
+// @Param input body RuleInput true "Правило"
+// @Success 200 {object} models.RecurringRule
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 404 {object} response.ErrorResponse "Правило не найдено"
+// @Failure 500 {object} response.ErrorResponse "Ошибка сохранения правила"
+// @Router /recurring/{id} [put]
+func (h *Handler) UpdateRule(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input RuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	existing, ok := h.ownedRule(c)
+	if !ok {
+		return
+	}
+	if input.Start.IsZero() {
+		input.Start = existing.Start
+	}
+
+	rule, errMsg := h.rule(userID, input)
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+	rule.Model = existing.Model
+	rule.Skipped = existing.Skipped
+	rule.NextIndex = existing.NextIndex
+	if scheduleChanged(existing, rule) {
+		var last *time.Time
+		if existing.NextIndex > 0 {
+			date := Nominal(existing, existing.NextIndex-1)
+			last = &date
+		}
+		Rebase(rule, last)
+	} else {
+		Schedule(rule)
+	}
+
+	if err := h.store.RecurringRules().Save(rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении правила"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.generate(rule))
+}
+
+// @Security BearerAuth
+// DeleteRule godoc
+// @Summary Удалить повторяющуюся операцию
+// @Description Удаляет правило. Уже созданные транзакции остаются.
+// @Tags Recurring
+// @Produce json
+// @Param id path string true "ID правила"
+// @Success 200 {object} response.SuccessResponse "Правило удалено"
+// @Failure 404 {object} response.ErrorResponse "Правило не найдено"
+// @Failure 500 {object} response.ErrorResponse "Ошибка удаления правила"
+// @Router /recurring/{id} [delete]
+func (h *Handler) DeleteRule(c *gin.Context) {
+	rule, ok := h.ownedRule(c)
+	if !ok {
+		return
+	}
+
+	if err := h.store.RecurringRules().Delete(rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении правила"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Правило удалено"})
+}
+
+// @Security BearerAuth
+// ListUpcoming godoc
+// @Summary Ближайшие повторения
+// @Description Возвращает ещё не созданные повторения всех правил или одного правила за days дней, по дате. Пропущенные повторения помечены skipped.
+// @Tags Recurring
+// @Produce json
+// @Param days query int false "Период в днях, по умолчанию 30, не больше 366"
+// @Param rule query int false "ID правила"
+// @Success 200 {array} Upcoming
+// @Failure 400 {object} response.ErrorResponse "Неверные параметры запроса"
+// @Failure 404 {object} response.ErrorResponse "Правило не найдено"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении правил"
+// @Router /recurring/upcoming [get]
+func (h *Handler) ListUpcoming(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	days := defaultUpcomingDays
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxUpcomingDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Параметр days должен быть от 1 до 366"})
+			return
+		}
+		days = parsed
+	}
+
+	var rules []models.RecurringRule
+	if raw := c.Query("rule"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Правило не найдено"})
+			return
+		}
+		rule, err := h.store.RecurringRules().GetOwned(uint(id), userID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Правило не найдено"})
+			return
+		}
+		rules = append(rules, *rule)
+	} else {
+		var err error
+		if rules, err = h.store.RecurringRules().ListForUser(userID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении правил"})
+			return
+		}
+	}
+
+	to := time.Now().AddDate(0, 0, days)
+	upcoming := []Upcoming{}
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Paused {
+			continue
+		}
+		for _, occurrence := range Occurrences(rule, rule.NextIndex, to, maxUpcoming) {
+			upcoming = append(upcoming, Upcoming{
+				Occurrence: occurrence,
+				Rule:       rule.ID,
+				Type:       rule.Type,
+				Account:    rule.AccountID,
+				Amount:     rule.Amount,
+				Title:      rule.Title,
+			})
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool { return upcoming[i].Date.Before(upcoming[j].Date) })
+	if len(upcoming) > maxUpcoming {
+		upcoming = upcoming[:maxUpcoming]
+	}
+
+	c.JSON(http.StatusOK, upcoming)
+}
+
+// @Security BearerAuth
+// SkipOccurrence godoc
+// @Summary Пропустить повторение
+// @Description Отменяет одно ещё не созданное повторение. Дату можно указать по расписанию или после переноса с выходных.
+// @Tags Recurring
+// @Accept json
+// @Produce json
+// @Param id path string true "ID правила"
+// @Param input body SkipInput true "Дата повторения"
+// @Success 200 {object} models.RecurringRule
+// @Failure 400 {object} response.ErrorResponse "Повторения на эту дату нет"
+// @Failure 404 {object} response.ErrorResponse "Правило не найдено"
+// @Failure 500 {object} response.ErrorResponse "Ошибка сохранения правила"
+// @Router /recurring/{id}/skip [post]
+func (h *Handler) SkipOccurrence(c *gin.Context) {
+	var input SkipInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.setSkipped(c, input.Date, true)
+}
+
+// @Security BearerAuth
+// RestoreOccurrence godoc
+// @Summary Вернуть пропущенное повторение
+// @Tags Recurring
+// @Produce json
+// @Param id path string true "ID правила"
+// @Param date path string true "Дата повторения, YYYY-MM-DD"
+// @Success 200 {object} models.RecurringRule
+// @Failure 400 {object} response.ErrorResponse "Повторения на эту дату нет"
+// @Failure 404 {object} response.ErrorResponse "Правило не найдено"
+// @Failure 500 {object} response.ErrorResponse "Ошибка сохранения правила"
+// @Router /recurring/{id}/skip/{date} [delete]
+func (h *Handler) RestoreOccurrence(c *gin.Context) {
+	h.setSkipped(c, c.Param("date"), false)
+}
+
+// setSkipped отмечает повторение на дату raw пропущенным или возвращает его.
+// Менять можно только повторения, которые ещё не созданы.
+func (h *Handler) setSkipped(c *gin.Context, raw string, skipped bool) {
+	rule, ok := h.ownedRule(c)
+	if !ok {
+		return
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", raw, rule.Start.Location())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверная дата, ожидается формат YYYY-MM-DD"})
+		return
+	}
+
+	var found *Occurrence
+	// Перенос с выходных сдвигает дату не больше чем на два дня
+	for _, occurrence := range Occurrences(rule, rule.NextIndex, day.AddDate(0, 0, 3), maxUpcoming) {
+		if sameDay(occurrence.Nominal, day) || sameDay(occurrence.Date, day) {
+			found = &occurrence
+			break
+		}
+	}
+	if found == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "На эту дату нет будущего повторения"})
+		return
+	}
+
+	nominal := found.Nominal.Format("2006-01-02")
+	list := models.DateList{}
+	for _, d := range rule.Skipped {
+		if d != nominal {
+			list = append(list, d)
+		}
+	}
+	if skipped {
+		list = append(list, nominal)
+		sort.Strings(list)
+	}
+	rule.Skipped = list
+
+	if err := h.store.RecurringRules().Save(rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении правила"})
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// rule проверяет ввод и собирает правило. Вторым значением возвращается текст ошибки.
+func (h *Handler) rule(userID uint, input RuleInput) (*models.RecurringRule, string) {
+	if input.Amount <= 0 {
+		return nil, "Сумма должна быть больше 0"
+	}
+	if input.Interval == 0 {
+		input.Interval = 1
+	}
+	if input.Adjust == "" {
+		input.Adjust = string(models.AdjustNone)
+	}
+	if input.Frequency == string(models.Daily) && input.Adjust != string(models.AdjustNone) {
+		return nil, "Перенос с выходных недоступен для ежедневных повторений"
+	}
+	if input.Start.IsZero() {
+		input.Start = time.Now()
+	}
+	if input.Until != nil && input.Until.Before(input.Start) && !sameDay(*input.Until, input.Start) {
+		return nil, "Дата окончания раньше даты начала"
+	}
+
+	account, errMsg := transactions.UserAccount(h.store, userID, input.Account)
+	if errMsg != "" {
+		return nil, errMsg
+	}
+
+	rule := &models.RecurringRule{
+		UserID:      userID,
+		Type:        models.TransactionType(input.Type),
+		AccountID:   account.ID,
+		Amount:      input.Amount,
+		Title:       input.Title,
+		Description: input.Description,
+		Frequency:   models.Frequency(input.Frequency),
+		Interval:    input.Interval,
+		Start:       input.Start,
+		Until:       input.Until,
+		Count:       input.Count,
+		Adjust:      models.BusinessDayAdjustment(input.Adjust),
+		Skipped:     models.DateList{},
+		Paused:      input.Paused,
+	}
+
+	if rule.Type != models.Transfer {
+		if _, err := h.store.Categories().GetAvailable(input.Category, userID); err != nil {
+			return nil, "Указана неверная категория"
+		}
+		rule.Category = input.Category
+		return rule, ""
+	}
+
+	if input.CounterAccount == 0 {
+		return nil, "Укажите счёт зачисления"
+	}
+	counter, errMsg := transactions.UserAccount(h.store, userID, input.CounterAccount)
+	if errMsg != "" {
+		return nil, errMsg
+	}
+	if counter.ID == account.ID {
+		return nil, "Счета списания и зачисления должны различаться"
+	}
+	switch {
+	case input.CounterAmount != nil:
+		if *input.CounterAmount <= 0 {
+			return nil, "Сумма зачисления должна быть больше 0"
+		}
+		rule.CounterAmount = *input.CounterAmount
+	case counter.Currency == account.Currency:
+		rule.CounterAmount = input.Amount
+	default:
+		return nil, "Для перевода между валютами укажите counterAmount"
+	}
+	uncategorized, err := h.store.Categories().FindDefaultBySlug(models.UncategorizedSlug)
+	if err != nil {
+		return nil, "Ошибка при сохранении правила"
+	}
+	rule.Category = uncategorized.ID
+	rule.CounterAccountID = &counter.ID
+	return rule, ""
+}
+
+// generate создаёт наступившие повторения сразу после сохранения правила
+// и возвращает правило в актуальном состоянии. Ошибка только пишется
+// в лог: повторения создаст планировщик.
+func (h *Handler) generate(rule *models.RecurringRule) *models.RecurringRule {
+	if _, err := Generate(h.store, rule.ID, rule.UserID, time.Now()); err != nil {
+		log.Printf("Повторяющиеся операции: правило %d: %v", rule.ID, err)
+		return rule
+	}
+	if fresh, err := h.store.RecurringRules().GetOwned(rule.ID, rule.UserID); err == nil {
+		return fresh
+	}
+	return rule
+}
+
+func (h *Handler) ownedRule(c *gin.Context) (*models.RecurringRule, bool) {
+	userID := c.GetUint("userID")
+
+	ruleID, err := httputil.ParamID(c, "id")
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Правило не найдено"})
+		return nil, false
+	}
+	rule, err := h.store.RecurringRules().GetOwned(ruleID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Правило не найдено"})
+		return nil, false
+	}
+	return rule, true
+}
+
+// scheduleChanged сообщает, изменилось ли расписание правила.
+func scheduleChanged(old, updated *models.RecurringRule) bool {
+	return old.Frequency != updated.Frequency || old.Interval != updated.Interval ||
+		!old.Start.Equal(updated.Start) || old.Count != updated.Count || old.Adjust != updated.Adjust ||
+		(old.Until == nil) != (updated.Until == nil) || (old.Until != nil && !old.Until.Equal(*updated.Until))
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
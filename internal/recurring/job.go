@@ -0,0 +1,171 @@
+package recurring
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/budgets"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/Anabol1ks/pers-fin-m/internal/transactions"
+)
+
+// maxBatch ограничивает число повторений одного правила за запуск, чтобы
+// правило с давней датой начала не задерживало остальные.
+const maxBatch = 500
+
+// RunPeriodic создаёт наступившие повторения сразу и затем раз в interval.
+// Блокирует вызывающую горутину.
+func RunPeriodic(store repository.Store, interval time.Duration) {
+	GenerateAll(store, time.Now())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		GenerateAll(store, now)
+	}
+}
+
+// GenerateAll создаёт наступившие к now повторения всех правил
+// и возвращает количество созданных транзакций.
+func GenerateAll(store repository.Store, now time.Time) int {
+	rules, err := store.RecurringRules().ListDue(now)
+	if err != nil {
+		log.Println("Повторяющиеся операции: ошибка получения правил:", err)
+		return 0
+	}
+
+	total := 0
+	for _, rule := range rules {
+		created, err := Generate(store, rule.ID, rule.UserID, now)
+		if err != nil {
+			log.Printf("Повторяющиеся операции: правило %d: %v", rule.ID, err)
+		}
+		total += created
+	}
+	return total
+}
+
+// Generate создаёт наступившие к now повторения правила. Каждое повторение
+// обрабатывается в своей транзакции БД: транзакция создаётся вместе со
+// сдвигом NextIndex, пропущенные повторения только сдвигают его. Если счёт
+// правила удалён или в архиве, правило приостанавливается. Бюджеты по
+// созданным расходам проверяются в фоне.
+func Generate(store repository.Store, ruleID, userID uint, now time.Time) (int, error) {
+	var created []models.Transaction
+	defer func() { budgets.Notify(store, userID, created...) }()
+
+	for i := 0; i < maxBatch; i++ {
+		done := false
+		var made *models.Transaction
+		err := store.Atomic(func(s repository.Store) error {
+			made = nil
+			rule, err := s.RecurringRules().GetOwned(ruleID, userID)
+			if err != nil {
+				return err
+			}
+			if rule.Paused || rule.NextRun == nil || rule.NextRun.After(now) {
+				done = true
+				return nil
+			}
+
+			nominal := Nominal(rule, rule.NextIndex)
+			if !rule.Skipped.Contains(nominal) {
+				exists, err := s.Transactions().ExistsForOccurrence(rule.ID, nominal)
+				if err != nil {
+					return err
+				}
+				if !exists {
+					transaction, err := occurrenceTransaction(s, rule, nominal)
+					if errors.Is(err, errUnavailable) {
+						log.Printf("Повторяющиеся операции: правило %d приостановлено: %v", rule.ID, err)
+						rule.Paused = true
+						done = true
+						return s.RecurringRules().Save(rule)
+					}
+					if err != nil {
+						return err
+					}
+					if err := create(s, transaction); err != nil {
+						return err
+					}
+					made = transaction
+				}
+			}
+
+			rule.NextIndex++
+			Schedule(rule)
+			return s.RecurringRules().Save(rule)
+		})
+		if err != nil {
+			return len(created), err
+		}
+		if done {
+			break
+		}
+		if made != nil {
+			created = append(created, *made)
+		}
+	}
+	return len(created), nil
+}
+
+var errUnavailable = errors.New("счёт правила удалён или находится в архиве")
+
+// occurrenceTransaction собирает транзакцию повторения по шаблону правила.
+// Валюта берётся у счёта, удалённая категория заменяется на «Без категории».
+func occurrenceTransaction(s repository.Store, rule *models.RecurringRule, nominal time.Time) (*models.Transaction, error) {
+	account, err := s.Accounts().GetOwned(rule.AccountID, rule.UserID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && account.Archived) {
+		return nil, errUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	category := rule.Category
+	if _, err := s.Categories().GetAvailable(category, rule.UserID); err != nil {
+		uncategorized, err := s.Categories().FindDefaultBySlug(models.UncategorizedSlug)
+		if err != nil {
+			return nil, err
+		}
+		category = uncategorized.ID
+	}
+
+	ruleID, occurrence := rule.ID, nominal
+	transaction := &models.Transaction{
+		UserID:      rule.UserID,
+		AccountID:   account.ID,
+		Amount:      rule.Amount,
+		Currency:    account.Currency,
+		Date:        Adjust(nominal, rule.Adjust),
+		Title:       rule.Title,
+		Description: rule.Description,
+		Category:    category,
+		Type:        rule.Type,
+		RecurringID: &ruleID,
+		Occurrence:  &occurrence,
+	}
+
+	if rule.Type == models.Transfer {
+		counter, err := s.Accounts().GetOwned(*rule.CounterAccountID, rule.UserID)
+		if errors.Is(err, repository.ErrNotFound) || (err == nil && counter.Archived) {
+			return nil, errUnavailable
+		}
+		if err != nil {
+			return nil, err
+		}
+		transaction.CounterAccountID = &counter.ID
+		transaction.CounterAmount = rule.CounterAmount
+	}
+	return transaction, nil
+}
+
+// create сохраняет транзакцию и меняет балансы счетов.
+func create(s repository.Store, t *models.Transaction) error {
+	if err := s.Transactions().Create(t); err != nil {
+		return err
+	}
+	return transactions.ApplyEffect(s, t, 1)
+}
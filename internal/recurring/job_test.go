@@ -0,0 +1,67 @@
+package recurring
+
+import (
+	"testing"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository/memory"
+)
+
+func TestGenerateSkipsDeleted(t *testing.T) {
+	store := memory.NewStore()
+	if err := store.Users().Create(&models.User{Username: "test", Email: "test@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	slug := models.UncategorizedSlug
+	category := models.Category{Name: "Без категории", IsDefault: true, Slug: &slug}
+	if err := store.Categories().Create(&category); err != nil {
+		t.Fatal(err)
+	}
+	account := models.Account{UserID: 1, Name: "Карта", Type: models.AccountCard, Currency: "RUB"}
+	if err := store.Accounts().Create(&account); err != nil {
+		t.Fatal(err)
+	}
+	rule := models.RecurringRule{
+		UserID: 1, Type: models.Income, AccountID: account.ID, Amount: 100000, Category: category.ID,
+		Title: "Зарплата", Frequency: models.Monthly, Interval: 1, Start: date(2024, 1, 10), Count: 3,
+	}
+	Schedule(&rule)
+	if err := store.RecurringRules().Create(&rule); err != nil {
+		t.Fatal(err)
+	}
+
+	now := date(2024, 4, 1)
+	if created, err := Generate(store, rule.ID, 1, now); err != nil || created != 3 {
+		t.Fatalf("Generate() = %d, %v, want 3", created, err)
+	}
+
+	// Пользователь удалил февральское повторение
+	hits, err := store.Transactions().Search(1, repository.TransactionFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, hit := range hits {
+		if hit.Occurrence.Equal(date(2024, 2, 10)) {
+			if err := store.Transactions().Delete(&hit.Transaction); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// После перестройки расписания с начала удалённое повторение не создаётся снова
+	saved, err := store.RecurringRules().GetOwned(rule.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Rebase(saved, nil)
+	if err := store.RecurringRules().Save(saved); err != nil {
+		t.Fatal(err)
+	}
+	if created, err := Generate(store, rule.ID, 1, now); err != nil || created != 0 {
+		t.Errorf("повторный Generate() = %d, %v, want 0", created, err)
+	}
+	if hits, _ := store.Transactions().Search(1, repository.TransactionFilter{}); len(hits) != 2 {
+		t.Errorf("транзакций = %d, want 2", len(hits))
+	}
+}
@@ -0,0 +1,142 @@
+// Package recurring создаёт транзакции по правилам повторяющихся операций:
+// аренда, зарплата, подписки.
+//
+// Расписание задаётся как в RRULE: частота, интервал, дата окончания или
+// число повторений и перенос с выходных. Планировщик периодически создаёт
+// наступившие повторения. Сдвиг номера следующего повторения сохраняется
+// в одной транзакции БД с созданной транзакцией, поэтому после перезапуска
+// повторения не теряются и не дублируются.
+package recurring
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+)
+
+// Occurrence — одно повторение правила.
+type Occurrence struct {
+	Index   int       `json:"index"`
+	Nominal time.Time `json:"nominal"` // дата по расписанию
+	Date    time.Time `json:"date"`    // дата после переноса с выходных
+	Skipped bool      `json:"skipped"`
+}
+
+// Nominal возвращает дату n-го повторения по расписанию без переноса с выходных.
+// Для ежемесячных и ежегодных правил число, которого нет в месяце (31-е, 29 февраля),
+// заменяется последним днём месяца.
+func Nominal(rule *models.RecurringRule, n int) time.Time {
+	step := n * rule.Interval
+	switch rule.Frequency {
+	case models.Weekly:
+		return rule.Start.AddDate(0, 0, 7*step)
+	case models.Monthly:
+		return addMonths(rule.Start, step)
+	case models.Yearly:
+		return addMonths(rule.Start, 12*step)
+	}
+	return rule.Start.AddDate(0, 0, step)
+}
+
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// Adjust переносит дату с субботы или воскресенья на рабочий день.
+func Adjust(t time.Time, adjust models.BusinessDayAdjustment) time.Time {
+	switch adjust {
+	case models.AdjustFollowing:
+		return following(t)
+	case models.AdjustPreceding:
+		return preceding(t)
+	case models.AdjustModifiedFollowing:
+		if next := following(t); next.Month() == t.Month() {
+			return next
+		}
+		return preceding(t)
+	}
+	return t
+}
+
+func following(t time.Time) time.Time {
+	for isWeekend(t) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+func preceding(t time.Time) time.Time {
+	for isWeekend(t) {
+		t = t.AddDate(0, 0, -1)
+	}
+	return t
+}
+
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+// ended сообщает, что повторение n с датой nominal выходит за Count или Until.
+func ended(rule *models.RecurringRule, n int, nominal time.Time) bool {
+	if rule.Count > 0 && n >= rule.Count {
+		return true
+	}
+	if rule.Until != nil {
+		until := *rule.Until
+		end := time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, until.Location()).AddDate(0, 0, 1)
+		return !nominal.Before(end)
+	}
+	return false
+}
+
+// Occurrences возвращает не больше limit повторений, начиная с номера from,
+// с датой не позже to.
+func Occurrences(rule *models.RecurringRule, from int, to time.Time, limit int) []Occurrence {
+	var occurrences []Occurrence
+	for n := from; len(occurrences) < limit; n++ {
+		nominal := Nominal(rule, n)
+		if ended(rule, n, nominal) {
+			break
+		}
+		// Дата после переноса может оказаться по другую сторону to, чем дата по расписанию
+		date := Adjust(nominal, rule.Adjust)
+		if date.After(to) && nominal.After(to) {
+			break
+		}
+		if date.After(to) {
+			continue
+		}
+		occurrences = append(occurrences, Occurrence{Index: n, Nominal: nominal, Date: date, Skipped: rule.Skipped.Contains(nominal)})
+	}
+	return occurrences
+}
+
+// Schedule пересчитывает NextRun по NextIndex: дату следующего повторения
+// или nil, если повторения закончились.
+func Schedule(rule *models.RecurringRule) {
+	nominal := Nominal(rule, rule.NextIndex)
+	if ended(rule, rule.NextIndex, nominal) {
+		rule.NextRun = nil
+		return
+	}
+	next := Adjust(nominal, rule.Adjust)
+	rule.NextRun = &next
+}
+
+// Rebase переводит правило на новое расписание после изменения серии:
+// следующим становится первое повторение нового расписания позже last —
+// даты последнего уже созданного повторения. Без last серия начинается с Start.
+func Rebase(rule *models.RecurringRule, last *time.Time) {
+	rule.NextIndex = 0
+	if last != nil {
+		for !Nominal(rule, rule.NextIndex).After(*last) && !ended(rule, rule.NextIndex, Nominal(rule, rule.NextIndex)) {
+			rule.NextIndex++
+		}
+	}
+	Schedule(rule)
+}
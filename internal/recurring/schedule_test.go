@@ -0,0 +1,193 @@
+package recurring
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 10, 0, 0, 0, time.UTC)
+}
+
+func TestNominal(t *testing.T) {
+	tests := []struct {
+		name string
+		rule models.RecurringRule
+		n    int
+		want time.Time
+	}{
+		{"ежедневно через 3 дня", models.RecurringRule{Frequency: models.Daily, Interval: 3, Start: date(2024, 1, 30)}, 2, date(2024, 2, 5)},
+		{"раз в две недели", models.RecurringRule{Frequency: models.Weekly, Interval: 2, Start: date(2024, 1, 1)}, 2, date(2024, 1, 29)},
+		{"31-е в феврале високосного года", models.RecurringRule{Frequency: models.Monthly, Interval: 1, Start: date(2024, 1, 31)}, 1, date(2024, 2, 29)},
+		{"31-е возвращается после короткого месяца", models.RecurringRule{Frequency: models.Monthly, Interval: 1, Start: date(2024, 1, 31)}, 2, date(2024, 3, 31)},
+		{"31-е в апреле", models.RecurringRule{Frequency: models.Monthly, Interval: 1, Start: date(2024, 1, 31)}, 3, date(2024, 4, 30)},
+		{"раз в квартал через год", models.RecurringRule{Frequency: models.Monthly, Interval: 3, Start: date(2024, 11, 30)}, 1, date(2025, 2, 28)},
+		{"29 февраля в обычный год", models.RecurringRule{Frequency: models.Yearly, Interval: 1, Start: date(2024, 2, 29)}, 1, date(2025, 2, 28)},
+		{"29 февраля в високосный год", models.RecurringRule{Frequency: models.Yearly, Interval: 1, Start: date(2024, 2, 29)}, 4, date(2028, 2, 29)},
+		{"нулевое повторение", models.RecurringRule{Frequency: models.Monthly, Interval: 1, Start: date(2024, 1, 31)}, 0, date(2024, 1, 31)},
+	}
+	for _, tt := range tests {
+		if got := Nominal(&tt.rule, tt.n); !got.Equal(tt.want) {
+			t.Errorf("%s: Nominal(%d) = %v, want %v", tt.name, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestAdjust(t *testing.T) {
+	tests := []struct {
+		in     time.Time
+		adjust models.BusinessDayAdjustment
+		want   time.Time
+	}{
+		{date(2024, 6, 1), models.AdjustNone, date(2024, 6, 1)},
+		{date(2024, 6, 1), models.AdjustFollowing, date(2024, 6, 3)},
+		{date(2024, 6, 1), models.AdjustPreceding, date(2024, 5, 31)},
+		{date(2024, 6, 1), models.AdjustModifiedFollowing, date(2024, 6, 3)},
+		{date(2024, 8, 31), models.AdjustModifiedFollowing, date(2024, 8, 30)},
+		{date(2024, 1, 31), models.AdjustFollowing, date(2024, 1, 31)},
+	}
+	for _, tt := range tests {
+		if got := Adjust(tt.in, tt.adjust); !got.Equal(tt.want) {
+			t.Errorf("Adjust(%v, %s) = %v, want %v", tt.in, tt.adjust, got, tt.want)
+		}
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	until := date(2024, 4, 30)
+	monthly := models.RecurringRule{Frequency: models.Monthly, Interval: 1, Start: date(2024, 1, 31), Until: &until}
+
+	tests := []struct {
+		name  string
+		rule  func(r *models.RecurringRule)
+		from  int
+		to    time.Time
+		limit int
+		want  []Occurrence
+	}{
+		{
+			name:  "до Until включительно",
+			to:    date(2030, 1, 1),
+			limit: 10,
+			want: []Occurrence{
+				{Index: 0, Nominal: date(2024, 1, 31), Date: date(2024, 1, 31)},
+				{Index: 1, Nominal: date(2024, 2, 29), Date: date(2024, 2, 29)},
+				{Index: 2, Nominal: date(2024, 3, 31), Date: date(2024, 3, 31)},
+				{Index: 3, Nominal: date(2024, 4, 30), Date: date(2024, 4, 30)},
+			},
+		},
+		{
+			name: "перенос за границу to и пропуск",
+			rule: func(r *models.RecurringRule) {
+				r.Adjust = models.AdjustFollowing
+				r.Skipped = models.DateList{"2024-02-29"}
+			},
+			to:    date(2024, 3, 31).Add(time.Hour),
+			limit: 10,
+			want: []Occurrence{
+				{Index: 0, Nominal: date(2024, 1, 31), Date: date(2024, 1, 31)},
+				{Index: 1, Nominal: date(2024, 2, 29), Date: date(2024, 2, 29), Skipped: true},
+			},
+		},
+		{
+			name:  "перенос назад попадает в to",
+			rule:  func(r *models.RecurringRule) { r.Adjust = models.AdjustPreceding },
+			from:  2,
+			to:    date(2024, 3, 30),
+			limit: 10,
+			want: []Occurrence{
+				{Index: 2, Nominal: date(2024, 3, 31), Date: date(2024, 3, 29)},
+			},
+		},
+		{
+			name:  "Count и limit",
+			rule:  func(r *models.RecurringRule) { r.Until, r.Count = nil, 3 },
+			from:  1,
+			to:    date(2030, 1, 1),
+			limit: 1,
+			want: []Occurrence{
+				{Index: 1, Nominal: date(2024, 2, 29), Date: date(2024, 2, 29)},
+			},
+		},
+		{
+			name:  "повторения закончились",
+			rule:  func(r *models.RecurringRule) { r.Until, r.Count = nil, 3 },
+			from:  3,
+			to:    date(2030, 1, 1),
+			limit: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := monthly
+			if tt.rule != nil {
+				tt.rule(&rule)
+			}
+			if got := Occurrences(&rule, tt.from, tt.to, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Occurrences() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRebase(t *testing.T) {
+	lastRun := date(2024, 3, 15)
+	tests := []struct {
+		name      string
+		rule      models.RecurringRule
+		last      *time.Time
+		nextIndex int
+		nextRun   *time.Time
+	}{
+		{
+			name:      "без созданных повторений",
+			rule:      models.RecurringRule{Frequency: models.Monthly, Interval: 1, Start: date(2024, 1, 15)},
+			nextIndex: 0,
+			nextRun:   ptr(date(2024, 1, 15)),
+		},
+		{
+			name:      "то же расписание",
+			rule:      models.RecurringRule{Frequency: models.Monthly, Interval: 1, Start: date(2024, 1, 15)},
+			last:      &lastRun,
+			nextIndex: 3,
+			nextRun:   ptr(date(2024, 4, 15)),
+		},
+		{
+			name:      "начало сдвинуто на 20-е",
+			rule:      models.RecurringRule{Frequency: models.Monthly, Interval: 1, Start: date(2024, 1, 20)},
+			last:      &lastRun,
+			nextIndex: 2,
+			nextRun:   ptr(date(2024, 3, 20)),
+		},
+		{
+			name:      "стало еженедельно с переносом",
+			rule:      models.RecurringRule{Frequency: models.Weekly, Interval: 1, Start: date(2024, 3, 2), Adjust: models.AdjustFollowing},
+			last:      &lastRun,
+			nextIndex: 2,
+			nextRun:   ptr(date(2024, 3, 18)),
+		},
+		{
+			name:      "повторения закончились",
+			rule:      models.RecurringRule{Frequency: models.Monthly, Interval: 1, Start: date(2024, 1, 15), Count: 2},
+			last:      &lastRun,
+			nextIndex: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			rule.NextIndex = 7
+			Rebase(&rule, tt.last)
+			if rule.NextIndex != tt.nextIndex || !reflect.DeepEqual(rule.NextRun, tt.nextRun) {
+				t.Errorf("Rebase() NextIndex = %d, NextRun = %v, want %d, %v", rule.NextIndex, rule.NextRun, tt.nextIndex, tt.nextRun)
+			}
+		})
+	}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+type accountRepository struct {
+	s *Store
+}
+
+func (r *accountRepository) ListForUser(userID uint, includeArchived bool) ([]models.Account, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	accounts := []models.Account{}
+	for _, account := range r.s.data.accounts {
+		if account.UserID == userID && (includeArchived || !account.Archived) {
+			accounts = append(accounts, account)
+		}
+	}
+	sortByID(accounts, func(a models.Account) uint { return a.ID })
+	return accounts, nil
+}
+
+func (r *accountRepository) GetOwned(id, userID uint) (*models.Account, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	account, ok := r.s.data.accounts[id]
+	if !ok || account.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &account, nil
+}
+
+func (r *accountRepository) Primary(userID uint) (*models.Account, error) {
+	accounts, err := r.ListForUser(userID, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &accounts[0], nil
+}
+
+// LockForUser ничего не делает: Atomic хранилища в памяти и так
+// выполняет транзакции последовательно.
+func (r *accountRepository) LockForUser(userID uint) error {
+	return nil
+}
+
+func (r *accountRepository) Create(account *models.Account) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	account.ID = r.s.data.nextID("accounts")
+	account.CreatedAt = now
+	account.UpdatedAt = now
+	if account.Currency == "" {
+		account.Currency = "RUB"
+	}
+	r.s.data.accounts[account.ID] = *account
+	return nil
+}
+
+func (r *accountRepository) Save(account *models.Account) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if account.ID == 0 {
+		account.ID = r.s.data.nextID("accounts")
+		account.CreatedAt = time.Now()
+	}
+	account.UpdatedAt = time.Now()
+	r.s.data.accounts[account.ID] = *account
+	return nil
+}
+
+func (r *accountRepository) Delete(account *models.Account) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.data.accounts, account.ID)
+	return nil
+}
+
+func (r *accountRepository) AdjustBalance(id uint, delta money.Amount) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	account, ok := r.s.data.accounts[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	account.Balance += delta
+	r.s.data.accounts[id] = account
+	return nil
+}
+
+func (r *accountRepository) UpdateDetails(account *models.Account) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.data.accounts[account.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	stored.Name = account.Name
+	stored.Type = account.Type
+	stored.Currency = account.Currency
+	stored.Archived = account.Archived
+	stored.UpdatedAt = time.Now()
+	r.s.data.accounts[account.ID] = stored
+	return nil
+}
+
+func (r *accountRepository) AdjustOpeningBalance(id uint, delta money.Amount) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	account, ok := r.s.data.accounts[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	account.OpeningBalance += delta
+	account.Balance += delta
+	r.s.data.accounts[id] = account
+	return nil
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+type budgetRepository struct {
+	s *Store
+}
+
+func (r *budgetRepository) ListForUser(userID uint) ([]models.Budget, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	budgets := []models.Budget{}
+	for _, budget := range r.s.data.budgets {
+		if budget.UserID == userID {
+			budgets = append(budgets, budget)
+		}
+	}
+	sortByID(budgets, func(b models.Budget) uint { return b.ID })
+	return budgets, nil
+}
+
+func (r *budgetRepository) GetOwned(id, userID uint) (*models.Budget, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	budget, ok := r.s.data.budgets[id]
+	if !ok || budget.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &budget, nil
+}
+
+func (r *budgetRepository) Create(budget *models.Budget) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	budget.ID = r.s.data.nextID("budgets")
+	budget.CreatedAt = now
+	budget.UpdatedAt = now
+	r.s.data.budgets[budget.ID] = *budget
+	return nil
+}
+
+func (r *budgetRepository) Save(budget *models.Budget) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if budget.ID == 0 {
+		budget.ID = r.s.data.nextID("budgets")
+		budget.CreatedAt = time.Now()
+	}
+	budget.UpdatedAt = time.Now()
+	r.s.data.budgets[budget.ID] = *budget
+	return nil
+}
+
+func (r *budgetRepository) Delete(budget *models.Budget) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.data.budgets, budget.ID)
+	return nil
+}
+
+func (r *budgetRepository) RecordAlert(budgetID uint, periodStart time.Time, threshold int) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, alert := range r.s.data.alerts {
+		if alert.BudgetID == budgetID && alert.PeriodStart.Equal(periodStart) && alert.Threshold == threshold {
+			return false, nil
+		}
+	}
+	id := r.s.data.nextID("budget_alerts")
+	r.s.data.alerts[id] = models.BudgetAlert{ID: id, BudgetID: budgetID, PeriodStart: periodStart, Threshold: threshold, CreatedAt: time.Now()}
+	return true, nil
+}
+
+func (r *budgetRepository) DeleteAlert(budgetID uint, periodStart time.Time, threshold int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, alert := range r.s.data.alerts {
+		if alert.BudgetID == budgetID && alert.PeriodStart.Equal(periodStart) && alert.Threshold == threshold {
+			delete(r.s.data.alerts, id)
+		}
+	}
+	return nil
+}
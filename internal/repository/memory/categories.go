@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+type categoryRepository struct {
+	s *Store
+}
+
+func (r *categoryRepository) ListForUser(userID uint) ([]models.Category, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	categories := []models.Category{}
+	for _, category := range r.s.data.categories {
+		if (category.UserID == nil && !category.Retired) || (category.UserID != nil && *category.UserID == userID) {
+			categories = append(categories, category)
+		}
+	}
+	sortByID(categories, func(c models.Category) uint { return c.ID })
+	return categories, nil
+}
+
+func (r *categoryRepository) GetAvailable(id, userID uint) (*models.Category, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	category, ok := r.s.data.categories[id]
+	if !ok || (category.UserID == nil && category.Retired) || (category.UserID != nil && *category.UserID != userID) {
+		return nil, repository.ErrNotFound
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) GetOwned(id, userID uint) (*models.Category, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	category, ok := r.s.data.categories[id]
+	if !ok || category.UserID == nil || *category.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) FindOwnedByName(userID uint, name string) (*models.Category, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, category := range r.s.data.categories {
+		if category.Name == name && category.UserID != nil && *category.UserID == userID {
+			return &category, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *categoryRepository) ListDefaults() ([]models.Category, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	categories := []models.Category{}
+	for _, category := range r.s.data.categories {
+		if category.UserID == nil {
+			categories = append(categories, category)
+		}
+	}
+	sortByID(categories, func(c models.Category) uint { return c.ID })
+	return categories, nil
+}
+
+func (r *categoryRepository) GetDefault(id uint) (*models.Category, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	category, ok := r.s.data.categories[id]
+	if !ok || category.UserID != nil {
+		return nil, repository.ErrNotFound
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) FindDefaultBySlug(slug string) (*models.Category, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, category := range r.s.data.categories {
+		if category.UserID == nil && category.Slug != nil && *category.Slug == slug {
+			return &category, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *categoryRepository) Create(category *models.Category) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	category.ID = r.s.data.nextID("categories")
+	category.CreatedAt = now
+	category.UpdatedAt = now
+	if category.Color == "" {
+		category.Color = "#16a34a"
+	}
+	r.s.data.categories[category.ID] = *category
+	return nil
+}
+
+func (r *categoryRepository) Save(category *models.Category) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if category.ID == 0 {
+		category.ID = r.s.data.nextID("categories")
+		category.CreatedAt = time.Now()
+	}
+	category.UpdatedAt = time.Now()
+	r.s.data.categories[category.ID] = *category
+	return nil
+}
+
+func (r *categoryRepository) Delete(category *models.Category) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.data.categories, category.ID)
+	return nil
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+type goalRepository struct {
+	s *Store
+}
+
+func (r *goalRepository) ListForUser(userID uint) ([]models.Goal, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	goals := []models.Goal{}
+	for _, goal := range r.s.data.goals {
+		if goal.UserID == userID {
+			goals = append(goals, goal)
+		}
+	}
+	sortByID(goals, func(g models.Goal) uint { return g.ID })
+	return goals, nil
+}
+
+func (r *goalRepository) GetOwned(id, userID uint) (*models.Goal, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	goal, ok := r.s.data.goals[id]
+	if !ok || goal.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &goal, nil
+}
+
+// Lock только проверяет наличие цели: Atomic хранилища в памяти и так
+// выполняет транзакции последовательно.
+func (r *goalRepository) Lock(id uint) error {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	if _, ok := r.s.data.goals[id]; !ok {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *goalRepository) Create(goal *models.Goal) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	goal.ID = r.s.data.nextID("goals")
+	goal.CreatedAt = now
+	goal.UpdatedAt = now
+	r.s.data.goals[goal.ID] = *goal
+	return nil
+}
+
+func (r *goalRepository) Save(goal *models.Goal) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if goal.ID == 0 {
+		goal.ID = r.s.data.nextID("goals")
+		goal.CreatedAt = time.Now()
+	}
+	goal.UpdatedAt = time.Now()
+	r.s.data.goals[goal.ID] = *goal
+	return nil
+}
+
+func (r *goalRepository) Delete(goal *models.Goal) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.data.goals, goal.ID)
+	return nil
+}
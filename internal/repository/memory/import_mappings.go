@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+type importMappingRepository struct {
+	s *Store
+}
+
+func (r *importMappingRepository) ListForUser(userID uint) ([]models.ImportMapping, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	mappings := []models.ImportMapping{}
+	for _, mapping := range r.s.data.mappings {
+		if mapping.UserID == userID {
+			mappings = append(mappings, mapping)
+		}
+	}
+	sortByID(mappings, func(m models.ImportMapping) uint { return m.ID })
+	return mappings, nil
+}
+
+func (r *importMappingRepository) GetOwned(id, userID uint) (*models.ImportMapping, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	mapping, ok := r.s.data.mappings[id]
+	if !ok || mapping.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &mapping, nil
+}
+
+func (r *importMappingRepository) Create(mapping *models.ImportMapping) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	mapping.ID = r.s.data.nextID("import_mappings")
+	mapping.CreatedAt = now
+	mapping.UpdatedAt = now
+	r.s.data.mappings[mapping.ID] = *mapping
+	return nil
+}
+
+func (r *importMappingRepository) Save(mapping *models.ImportMapping) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if mapping.ID == 0 {
+		mapping.ID = r.s.data.nextID("import_mappings")
+		mapping.CreatedAt = time.Now()
+	}
+	mapping.UpdatedAt = time.Now()
+	r.s.data.mappings[mapping.ID] = *mapping
+	return nil
+}
+
+func (r *importMappingRepository) Delete(mapping *models.ImportMapping) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.data.mappings, mapping.ID)
+	return nil
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"slices"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+type passwordResetRepository struct {
+	s *Store
+}
+
+func (r *passwordResetRepository) Create(reset *models.PasswordReset) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	reset.ID = r.s.data.nextID("password_resets")
+	reset.CreatedAt = time.Now()
+	r.s.data.resets[reset.ID] = *reset
+	return nil
+}
+
+func (r *passwordResetRepository) GetByHash(hash string) (*models.PasswordReset, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, reset := range r.s.data.resets {
+		if reset.TokenHash == hash {
+			return &reset, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *passwordResetRepository) ListActive(userID uint, now time.Time) ([]models.PasswordReset, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var result []models.PasswordReset
+	for _, reset := range r.s.data.resets {
+		if reset.UserID == userID && reset.UsedAt == nil && reset.ExpiresAt.After(now) {
+			result = append(result, reset)
+		}
+	}
+	sortByID(result, func(reset models.PasswordReset) uint { return reset.ID })
+	slices.Reverse(result)
+	return result, nil
+}
+
+func (r *passwordResetRepository) DeleteInactive(userID uint, now time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, reset := range r.s.data.resets {
+		if reset.UserID == userID && (reset.UsedAt != nil || !reset.ExpiresAt.After(now)) {
+			delete(r.s.data.resets, id)
+		}
+	}
+	return nil
+}
+
+func (r *passwordResetRepository) MarkUsed(id uint, at time.Time) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	reset, ok := r.s.data.resets[id]
+	if !ok || reset.UsedAt != nil {
+		return false, nil
+	}
+	reset.UsedAt = &at
+	r.s.data.resets[id] = reset
+	return true, nil
+}
+
+func (r *passwordResetRepository) InvalidateUser(userID uint, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, reset := range r.s.data.resets {
+		if reset.UserID == userID && reset.UsedAt == nil {
+			reset.UsedAt = &at
+			r.s.data.resets[id] = reset
+		}
+	}
+	return nil
+}
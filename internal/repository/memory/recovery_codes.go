@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+)
+
+type recoveryCodeRepository struct {
+	s *Store
+}
+
+func (r *recoveryCodeRepository) Replace(userID uint, hashes []string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.deleteForUser(userID)
+	now := time.Now()
+	for _, hash := range hashes {
+		id := r.s.data.nextID("recovery_codes")
+		r.s.data.recovery[id] = models.RecoveryCode{ID: id, UserID: userID, CodeHash: hash, CreatedAt: now}
+	}
+	return nil
+}
+
+func (r *recoveryCodeRepository) Use(userID uint, hash string, at time.Time) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, code := range r.s.data.recovery {
+		if code.UserID == userID && code.CodeHash == hash && code.UsedAt == nil {
+			code.UsedAt = &at
+			r.s.data.recovery[id] = code
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *recoveryCodeRepository) CountUnused(userID uint) (int, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	count := 0
+	for _, code := range r.s.data.recovery {
+		if code.UserID == userID && code.UsedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *recoveryCodeRepository) DeleteForUser(userID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.deleteForUser(userID)
+	return nil
+}
+
+func (r *recoveryCodeRepository) deleteForUser(userID uint) {
+	for id, code := range r.s.data.recovery {
+		if code.UserID == userID {
+			delete(r.s.data.recovery, id)
+		}
+	}
+}
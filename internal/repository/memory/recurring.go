@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+type recurringRuleRepository struct {
+	s *Store
+}
+
+func (r *recurringRuleRepository) ListForUser(userID uint) ([]models.RecurringRule, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	rules := []models.RecurringRule{}
+	for _, rule := range r.s.data.recurring {
+		if rule.UserID == userID {
+			rules = append(rules, rule)
+		}
+	}
+	sortByID(rules, func(r models.RecurringRule) uint { return r.ID })
+	return rules, nil
+}
+
+func (r *recurringRuleRepository) GetOwned(id, userID uint) (*models.RecurringRule, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	rule, ok := r.s.data.recurring[id]
+	if !ok || rule.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &rule, nil
+}
+
+func (r *recurringRuleRepository) ListDue(now time.Time) ([]models.RecurringRule, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	rules := []models.RecurringRule{}
+	for _, rule := range r.s.data.recurring {
+		if rule.NextRun != nil && !rule.NextRun.After(now) && !rule.Paused {
+			rules = append(rules, rule)
+		}
+	}
+	sortByID(rules, func(r models.RecurringRule) uint { return r.ID })
+	return rules, nil
+}
+
+func (r *recurringRuleRepository) Create(rule *models.RecurringRule) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	rule.ID = r.s.data.nextID("recurring_rules")
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	r.s.data.recurring[rule.ID] = *rule
+	return nil
+}
+
+func (r *recurringRuleRepository) Save(rule *models.RecurringRule) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if rule.ID == 0 {
+		rule.ID = r.s.data.nextID("recurring_rules")
+		rule.CreatedAt = time.Now()
+	}
+	rule.UpdatedAt = time.Now()
+	r.s.data.recurring[rule.ID] = *rule
+	return nil
+}
+
+func (r *recurringRuleRepository) Delete(rule *models.RecurringRule) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.data.recurring, rule.ID)
+	return nil
+}
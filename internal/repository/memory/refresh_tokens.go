@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+type refreshTokenRepository struct {
+	s *Store
+}
+
+func (r *refreshTokenRepository) Create(token *models.RefreshToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	token.ID = r.s.data.nextID("refresh_tokens")
+	token.CreatedAt = time.Now()
+	r.s.data.tokens[token.ID] = *token
+	return nil
+}
+
+func (r *refreshTokenRepository) GetByHash(hash string) (*models.RefreshToken, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, token := range r.s.data.tokens {
+		if token.TokenHash == hash {
+			return &token, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *refreshTokenRepository) MarkUsed(id uint, at time.Time) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	token, ok := r.s.data.tokens[id]
+	if !ok || token.UsedAt != nil || token.RevokedAt != nil {
+		return false, nil
+	}
+	token.UsedAt = &at
+	r.s.data.tokens[id] = token
+	return true, nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(familyID string, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, token := range r.s.data.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+			r.s.data.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeUser(userID uint, exceptFamilyID string, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, token := range r.s.data.tokens {
+		if token.UserID == userID && token.FamilyID != exceptFamilyID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+			r.s.data.tokens[id] = token
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"html"
+	"strings"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+)
+
+// textQuery — упрощённый полнотекстовый запрос для хранилища в памяти.
+// Морфология не учитывается: слова и "фразы" ищутся как подстроки без учёта
+// регистра, слова с минусом исключают транзакцию.
+type textQuery struct {
+	include []string
+	exclude []string
+}
+
+func parseTextQuery(q string) textQuery {
+	var query textQuery
+	q = strings.ToLower(q)
+	for q != "" {
+		q = strings.TrimSpace(q)
+		if q == "" {
+			break
+		}
+
+		negative := strings.HasPrefix(q, "-")
+		if negative {
+			q = q[1:]
+		}
+
+		var term string
+		if strings.HasPrefix(q, `"`) {
+			phrase, rest, _ := strings.Cut(q[1:], `"`)
+			term, q = strings.TrimSpace(phrase), rest
+		} else {
+			term, q, _ = strings.Cut(q, " ")
+		}
+		if term == "" || term == "or" {
+			continue
+		}
+
+		if negative {
+			query.exclude = append(query.exclude, term)
+		} else {
+			query.include = append(query.include, term)
+		}
+	}
+	return query
+}
+
+// rank возвращает релевантность транзакции; 0 — транзакция не подходит.
+// Совпадение в названии весит больше, чем в описании, как веса A и B в PostgreSQL.
+func (q textQuery) rank(t models.Transaction) float64 {
+	title, description := strings.ToLower(t.Title), strings.ToLower(t.Description)
+	for _, term := range q.exclude {
+		if strings.Contains(title, term) || strings.Contains(description, term) {
+			return 0
+		}
+	}
+
+	if len(q.include) == 0 {
+		return 1
+	}
+
+	var rank float64
+	for _, term := range q.include {
+		inTitle, inDescription := strings.Contains(title, term), strings.Contains(description, term)
+		if !inTitle && !inDescription {
+			return 0
+		}
+		if inTitle {
+			rank += 1
+		}
+		if inDescription {
+			rank += 0.4
+		}
+	}
+	return rank / float64(len(q.include))
+}
+
+// highlight оборачивает найденные слова в <mark>. Остальной текст
+// экранируется, чтобы разметку из названия нельзя было вставить в страницу.
+func (q textQuery) highlight(s string) string {
+	lower := strings.ToLower(s)
+	if len(lower) != len(s) {
+		// Смена регистра изменила длину строки, позиции не совпадут
+		return html.EscapeString(s)
+	}
+
+	marked := make([]bool, len(s))
+	for _, term := range q.include {
+		for from := 0; ; {
+			i := strings.Index(lower[from:], term)
+			if i < 0 {
+				break
+			}
+			for j := from + i; j < from+i+len(term); j++ {
+				marked[j] = true
+			}
+			from += i + len(term)
+		}
+	}
+
+	var b strings.Builder
+	for start := 0; start < len(s); {
+		end := start
+		for end < len(s) && marked[end] == marked[start] {
+			end++
+		}
+		segment := html.EscapeString(s[start:end])
+		if marked[start] {
+			segment = "<mark>" + segment + "</mark>"
+		}
+		b.WriteString(segment)
+		start = end
+	}
+	return b.String()
+}
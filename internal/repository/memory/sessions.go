@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+type sessionRepository struct {
+	s *Store
+}
+
+func (r *sessionRepository) Create(session *models.Session) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	session.ID = r.s.data.nextID("sessions")
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = now
+	}
+	if session.LastUsedAt.IsZero() {
+		session.LastUsedAt = now
+	}
+	r.s.data.sessions[session.ID] = *session
+	return nil
+}
+
+func (r *sessionRepository) GetByToken(tokenID string) (*models.Session, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, session := range r.s.data.sessions {
+		if session.TokenID == tokenID {
+			return &session, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *sessionRepository) GetOwned(id, userID uint) (*models.Session, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	session, ok := r.s.data.sessions[id]
+	if !ok || session.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) ListActive(userID uint, now time.Time) ([]models.Session, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var result []models.Session
+	for _, session := range r.s.data.sessions {
+		if session.UserID == userID && session.RevokedAt == nil && session.ExpiresAt.After(now) {
+			result = append(result, session)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].LastUsedAt.Equal(result[j].LastUsedAt) {
+			return result[i].LastUsedAt.After(result[j].LastUsedAt)
+		}
+		return result[i].ID > result[j].ID
+	})
+	return result, nil
+}
+
+func (r *sessionRepository) Touch(tokenID string, at time.Time, ip string) error {
+	return r.update(tokenID, func(session *models.Session) {
+		session.LastUsedAt = at
+		session.IP = ip
+	})
+}
+
+func (r *sessionRepository) Extend(tokenID string, expiresAt time.Time) error {
+	return r.update(tokenID, func(session *models.Session) {
+		session.ExpiresAt = expiresAt
+	})
+}
+
+func (r *sessionRepository) Revoke(tokenID string, at time.Time) error {
+	return r.update(tokenID, func(session *models.Session) {
+		if session.RevokedAt == nil {
+			session.RevokedAt = &at
+		}
+	})
+}
+
+func (r *sessionRepository) RevokeUser(userID uint, exceptTokenID string, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, session := range r.s.data.sessions {
+		if session.UserID == userID && session.TokenID != exceptTokenID && session.RevokedAt == nil {
+			session.RevokedAt = &at
+			r.s.data.sessions[id] = session
+		}
+	}
+	return nil
+}
+
+func (r *sessionRepository) update(tokenID string, fn func(session *models.Session)) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, session := range r.s.data.sessions {
+		if session.TokenID == tokenID {
+			fn(&session)
+			r.s.data.sessions[id] = session
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+type smsTemplateRepository struct {
+	s *Store
+}
+
+func (r *smsTemplateRepository) ListForUser(userID uint) ([]models.SMSTemplate, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	templates := []models.SMSTemplate{}
+	for _, template := range r.s.data.templates {
+		if template.UserID == userID {
+			templates = append(templates, template)
+		}
+	}
+	sortByID(templates, func(t models.SMSTemplate) uint { return t.ID })
+	return templates, nil
+}
+
+func (r *smsTemplateRepository) GetOwned(id, userID uint) (*models.SMSTemplate, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	template, ok := r.s.data.templates[id]
+	if !ok || template.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &template, nil
+}
+
+func (r *smsTemplateRepository) Create(template *models.SMSTemplate) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	template.ID = r.s.data.nextID("sms_templates")
+	template.CreatedAt = now
+	template.UpdatedAt = now
+	r.s.data.templates[template.ID] = *template
+	return nil
+}
+
+func (r *smsTemplateRepository) Save(template *models.SMSTemplate) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if template.ID == 0 {
+		template.ID = r.s.data.nextID("sms_templates")
+		template.CreatedAt = time.Now()
+	}
+	template.UpdatedAt = time.Now()
+	r.s.data.templates[template.ID] = *template
+	return nil
+}
+
+func (r *smsTemplateRepository) Delete(template *models.SMSTemplate) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.data.templates, template.ID)
+	return nil
+}
@@ -0,0 +1,172 @@
+// Package memory реализует интерфейсы repository в памяти процесса.
+// Предназначен для тестов и локального запуска без PostgreSQL.
+package memory
+
+import (
+	"maps"
+	"sort"
+	"sync"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+type tables struct {
+	users        map[uint]models.User
+	accounts     map[uint]models.Account
+	categories   map[uint]models.Category
+	transactions map[uint]models.Transaction
+	mappings     map[uint]models.ImportMapping
+	templates    map[uint]models.SMSTemplate
+	recurring    map[uint]models.RecurringRule
+	budgets      map[uint]models.Budget
+	alerts       map[uint]models.BudgetAlert
+	goals        map[uint]models.Goal
+	tokens       map[uint]models.RefreshToken
+	resets       map[uint]models.PasswordReset
+	recovery     map[uint]models.RecoveryCode
+	sessions     map[uint]models.Session
+	lastID       map[string]uint
+	// deletedOccurrences помнит повторения удалённых транзакций, как
+	// soft delete в PostgreSQL, чтобы планировщик не создал их заново.
+	deletedOccurrences map[occurrenceKey]bool
+}
+
+// occurrenceKey — повторение правила: ID правила и дата в наносекундах Unix.
+type occurrenceKey struct {
+	ruleID uint
+	at     int64
+}
+
+func (t *tables) clone() *tables {
+	return &tables{
+		users:        maps.Clone(t.users),
+		accounts:     maps.Clone(t.accounts),
+		categories:   maps.Clone(t.categories),
+		transactions: maps.Clone(t.transactions),
+		mappings:     maps.Clone(t.mappings),
+		templates:    maps.Clone(t.templates),
+		recurring:    maps.Clone(t.recurring),
+		budgets:      maps.Clone(t.budgets),
+		alerts:       maps.Clone(t.alerts),
+		goals:        maps.Clone(t.goals),
+		tokens:       maps.Clone(t.tokens),
+		resets:       maps.Clone(t.resets),
+		recovery:     maps.Clone(t.recovery),
+		sessions:     maps.Clone(t.sessions),
+		lastID:       maps.Clone(t.lastID),
+
+		deletedOccurrences: maps.Clone(t.deletedOccurrences),
+	}
+}
+
+func (t *tables) nextID(table string) uint {
+	t.lastID[table]++
+	return t.lastID[table]
+}
+
+type Store struct {
+	// atomicMu сериализует вызовы Atomic, mu защищает данные.
+	atomicMu sync.Mutex
+	mu       sync.RWMutex
+	data     *tables
+}
+
+func NewStore() *Store {
+	return &Store{
+		data: &tables{
+			users:        map[uint]models.User{},
+			accounts:     map[uint]models.Account{},
+			categories:   map[uint]models.Category{},
+			transactions: map[uint]models.Transaction{},
+			mappings:     map[uint]models.ImportMapping{},
+			templates:    map[uint]models.SMSTemplate{},
+			recurring:    map[uint]models.RecurringRule{},
+			budgets:      map[uint]models.Budget{},
+			alerts:       map[uint]models.BudgetAlert{},
+			goals:        map[uint]models.Goal{},
+			tokens:       map[uint]models.RefreshToken{},
+			resets:       map[uint]models.PasswordReset{},
+			recovery:     map[uint]models.RecoveryCode{},
+			sessions:     map[uint]models.Session{},
+			lastID:       map[string]uint{},
+
+			deletedOccurrences: map[occurrenceKey]bool{},
+		},
+	}
+}
+
+func (s *Store) Users() repository.UserRepository {
+	return &userRepository{s: s}
+}
+
+func (s *Store) Accounts() repository.AccountRepository {
+	return &accountRepository{s: s}
+}
+
+func (s *Store) Categories() repository.CategoryRepository {
+	return &categoryRepository{s: s}
+}
+
+func (s *Store) Transactions() repository.TransactionRepository {
+	return &transactionRepository{s: s}
+}
+
+func (s *Store) ImportMappings() repository.ImportMappingRepository {
+	return &importMappingRepository{s: s}
+}
+
+func (s *Store) SMSTemplates() repository.SMSTemplateRepository {
+	return &smsTemplateRepository{s: s}
+}
+
+func (s *Store) RecurringRules() repository.RecurringRuleRepository {
+	return &recurringRuleRepository{s: s}
+}
+
+func (s *Store) Budgets() repository.BudgetRepository {
+	return &budgetRepository{s: s}
+}
+
+func (s *Store) Goals() repository.GoalRepository {
+	return &goalRepository{s: s}
+}
+
+func (s *Store) RefreshTokens() repository.RefreshTokenRepository {
+	return &refreshTokenRepository{s: s}
+}
+
+func (s *Store) PasswordResets() repository.PasswordResetRepository {
+	return &passwordResetRepository{s: s}
+}
+
+func (s *Store) RecoveryCodes() repository.RecoveryCodeRepository {
+	return &recoveryCodeRepository{s: s}
+}
+
+func (s *Store) Sessions() repository.SessionRepository {
+	return &sessionRepository{s: s}
+}
+
+// Atomic запоминает состояние перед вызовом fn и восстанавливает его при ошибке.
+// Изменения, сделанные вне Atomic во время выполнения fn, при откате теряются.
+func (s *Store) Atomic(fn func(s repository.Store) error) error {
+	s.atomicMu.Lock()
+	defer s.atomicMu.Unlock()
+
+	s.mu.RLock()
+	snapshot := s.data.clone()
+	s.mu.RUnlock()
+
+	if err := fn(s); err != nil {
+		s.mu.Lock()
+		s.data = snapshot
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func sortByID[T any](items []T, id func(T) uint) {
+	sort.Slice(items, func(i, j int) bool { return id(items[i]) < id(items[j]) })
+}
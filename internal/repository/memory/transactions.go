@@ -0,0 +1,324 @@
+package memory
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+type transactionRepository struct {
+	s *Store
+}
+
+func (r *transactionRepository) Create(transaction *models.Transaction) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	transaction.ID = r.s.data.nextID("transactions")
+	transaction.CreatedAt = now
+	transaction.UpdatedAt = now
+	if transaction.Currency == "" {
+		transaction.Currency = "RUB"
+	}
+	r.s.data.transactions[transaction.ID] = *transaction
+	return nil
+}
+
+func (r *transactionRepository) GetOwned(id, userID uint) (*models.Transaction, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	transaction, ok := r.s.data.transactions[id]
+	if !ok || transaction.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &transaction, nil
+}
+
+func (r *transactionRepository) Save(transaction *models.Transaction) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if transaction.ID == 0 {
+		transaction.ID = r.s.data.nextID("transactions")
+		transaction.CreatedAt = time.Now()
+	}
+	transaction.UpdatedAt = time.Now()
+	r.s.data.transactions[transaction.ID] = *transaction
+	return nil
+}
+
+func (r *transactionRepository) Delete(transaction *models.Transaction) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if stored, ok := r.s.data.transactions[transaction.ID]; ok && stored.RecurringID != nil && stored.Occurrence != nil {
+		key := occurrenceKey{ruleID: *stored.RecurringID, at: stored.Occurrence.UnixNano()}
+		r.s.data.deletedOccurrences[key] = true
+	}
+	delete(r.s.data.transactions, transaction.ID)
+	return nil
+}
+
+func (r *transactionRepository) Search(userID uint, filter repository.TransactionFilter) ([]repository.SearchHit, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	query := parseTextQuery(filter.Query)
+	hits := []repository.SearchHit{}
+	for _, t := range r.s.data.transactions {
+		if t.UserID != userID || !matchTransaction(t, filter) {
+			continue
+		}
+		hit := repository.SearchHit{Transaction: t}
+		if filter.Query != "" {
+			hit.Rank = query.rank(t)
+			hit.TitleHighlight = query.highlight(t.Title)
+			hit.DescriptionHighlight = query.highlight(t.Description)
+		}
+		hits = append(hits, hit)
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank > hits[j].Rank
+		}
+		return hits[i].Date.After(hits[j].Date)
+	})
+	return hits, nil
+}
+
+func (r *transactionRepository) ExistsForAccount(accountID uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, t := range r.s.data.transactions {
+		if t.AccountID == accountID || (t.CounterAccountID != nil && *t.CounterAccountID == accountID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *transactionRepository) List(userID uint, filter repository.TransactionFilter, opts repository.ListOptions) (*repository.TransactionPage, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	page := &repository.TransactionPage{Items: []models.Transaction{}, Sums: map[string]repository.TransactionSums{}}
+	var matched []models.Transaction
+	for _, t := range r.s.data.transactions {
+		if t.UserID != userID || !matchTransaction(t, filter) {
+			continue
+		}
+		page.Total++
+		sums := page.Sums[t.Currency]
+		switch t.Type {
+		case models.Income:
+			sums.Income += t.Amount
+		case models.Expense:
+			sums.Expense += t.Amount
+		}
+		page.Sums[t.Currency] = sums
+		matched = append(matched, t)
+	}
+
+	// less сравнивает транзакции по полю сортировки, а при равенстве — по ID
+	less := func(a, b repository.Cursor) bool {
+		var c int
+		switch opts.Sort {
+		case repository.SortByAmount:
+			c = cmp.Compare(a.Amount, b.Amount)
+		case repository.SortByTitle:
+			c = strings.Compare(a.Title, b.Title)
+		case repository.SortByCreated:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		default:
+			c = a.Date.Compare(b.Date)
+		}
+		if c == 0 {
+			c = cmp.Compare(a.ID, b.ID)
+		}
+		if opts.Desc {
+			return c > 0
+		}
+		return c < 0
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return less(repository.CursorFor(matched[i]), repository.CursorFor(matched[j]))
+	})
+	for _, t := range matched {
+		if opts.After != nil && !less(*opts.After, repository.CursorFor(t)) {
+			continue
+		}
+		if len(page.Items) == opts.Limit {
+			page.HasMore = true
+			break
+		}
+		page.Items = append(page.Items, t)
+	}
+	return page, nil
+}
+
+func (r *transactionRepository) ExistingExternalIDs(accountID uint, ids []string) (map[string]bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	existing := map[string]bool{}
+	for _, t := range r.s.data.transactions {
+		if t.AccountID == accountID && t.ExternalID != nil && slices.Contains(ids, *t.ExternalID) {
+			existing[*t.ExternalID] = true
+		}
+	}
+	return existing, nil
+}
+
+func (r *transactionRepository) FindByReceipt(userID uint, drive, document, sign string) (*models.Transaction, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, t := range r.s.data.transactions {
+		if t.UserID == userID && t.FiscalDrive != nil && *t.FiscalDrive == drive &&
+			*t.FiscalDocument == document && *t.FiscalSign == sign {
+			return &t, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *transactionRepository) ExistsForOccurrence(ruleID uint, occurrence time.Time) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	if r.s.data.deletedOccurrences[occurrenceKey{ruleID: ruleID, at: occurrence.UnixNano()}] {
+		return true, nil
+	}
+
+	for _, t := range r.s.data.transactions {
+		if t.RecurringID != nil && *t.RecurringID == ruleID && t.Occurrence.Equal(occurrence) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *transactionRepository) ListForGoal(goalID uint) ([]models.Transaction, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	transactions := []models.Transaction{}
+	for _, t := range r.s.data.transactions {
+		if t.GoalID != nil && *t.GoalID == goalID {
+			transactions = append(transactions, t)
+		}
+	}
+	sort.Slice(transactions, func(i, j int) bool {
+		if !transactions[i].Date.Equal(transactions[j].Date) {
+			return transactions[i].Date.Before(transactions[j].Date)
+		}
+		return transactions[i].ID < transactions[j].ID
+	})
+	return transactions, nil
+}
+
+func (r *transactionRepository) ReassignCategory(userID, from, to uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, t := range r.s.data.transactions {
+		if t.UserID == userID && t.Category == from {
+			t.Category = to
+			r.s.data.transactions[id] = t
+		}
+	}
+	return nil
+}
+
+func matchTransaction(t models.Transaction, f repository.TransactionFilter) bool {
+	if f.Query != "" && parseTextQuery(f.Query).rank(t) == 0 {
+		return false
+	}
+	if f.Title != nil && *f.Title != "" && !containsFold(t.Title, *f.Title) {
+		return false
+	}
+	if f.Description != nil && *f.Description != "" && !containsFold(t.Description, *f.Description) {
+		return false
+	}
+	if f.AmountMin != nil && t.Amount < *f.AmountMin {
+		return false
+	}
+	if f.AmountMax != nil && t.Amount > *f.AmountMax {
+		return false
+	}
+	if f.BonusMin != nil && t.BonusChange < *f.BonusMin {
+		return false
+	}
+	if f.BonusMax != nil && t.BonusChange > *f.BonusMax {
+		return false
+	}
+	if f.DateFrom != nil && t.Date.Before(*f.DateFrom) {
+		return false
+	}
+	if f.DateTo != nil && !t.Date.Before(*f.DateTo) {
+		return false
+	}
+	if len(f.Categories) > 0 && !slices.Contains(f.Categories, t.Category) {
+		return false
+	}
+	if len(f.Accounts) > 0 && !touchesAccount(t, f.Accounts) {
+		return false
+	}
+	if len(f.Currencies) > 0 && !slices.Contains(f.Currencies, t.Currency) {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, string(t.Type)) {
+		return false
+	}
+	if f.BonusType != nil && *f.BonusType != "" && string(t.BonusType) != *f.BonusType {
+		return false
+	}
+
+	if slices.Contains(f.ExcludeCategories, t.Category) ||
+		touchesAccount(t, f.ExcludeAccounts) ||
+		slices.Contains(f.ExcludeCurrencies, t.Currency) ||
+		slices.Contains(f.ExcludeTypes, string(t.Type)) {
+		return false
+	}
+	return true
+}
+
+// touchesAccount сообщает, списывает ли транзакция с одного из счетов или зачисляет на него.
+func touchesAccount(t models.Transaction, accounts []uint) bool {
+	if slices.Contains(accounts, t.AccountID) {
+		return true
+	}
+	return t.CounterAccountID != nil && slices.Contains(accounts, *t.CounterAccountID)
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func (r *transactionRepository) Ledger(userID uint) (*repository.LedgerTotals, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	totals := &repository.LedgerTotals{Accounts: map[uint]money.Amount{}}
+	for _, t := range r.s.data.transactions {
+		if t.UserID != userID {
+			continue
+		}
+		totals.Accounts[t.AccountID] += t.BalanceEffect()
+		if t.CounterAccountID != nil {
+			totals.Accounts[*t.CounterAccountID] += t.CounterEffect()
+		}
+		totals.Bonus += t.BonusEffect()
+	}
+	return totals, nil
+}
@@ -0,0 +1,240 @@
+package memory
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+)
+
+var errDuplicateEmail = errors.New("пользователь с такой почтой уже существует")
+
+type userRepository struct {
+	s *Store
+}
+
+func (r *userRepository) GetByID(id uint) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	user, ok := r.s.data.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &user, nil
+}
+
+func (r *userRepository) GetByEmail(email string) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, user := range r.s.data.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// Lock только проверяет наличие пользователя: Atomic хранилища в памяти и так
+// выполняет транзакции последовательно.
+func (r *userRepository) Lock(id uint) error {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	if _, ok := r.s.data.users[id]; !ok {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) Create(user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.data.users {
+		if existing.Email == user.Email {
+			return errDuplicateEmail
+		}
+	}
+
+	now := time.Now()
+	user.ID = r.s.data.nextID("users")
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	r.s.data.users[user.ID] = *user
+	return nil
+}
+
+func (r *userRepository) Save(user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if user.ID == 0 {
+		user.ID = r.s.data.nextID("users")
+		user.CreatedAt = time.Now()
+	}
+	user.UpdatedAt = time.Now()
+	r.s.data.users[user.ID] = *user
+	return nil
+}
+
+func (r *userRepository) ListIDs() ([]uint, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	ids := make([]uint, 0, len(r.s.data.users))
+	for id := range r.s.data.users {
+		ids = append(ids, id)
+	}
+	sortByID(ids, func(id uint) uint { return id })
+	return ids, nil
+}
+
+func (r *userRepository) AdjustBonus(userID uint, delta money.Amount) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.data.users[userID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	user.Bonus += delta
+	r.s.data.users[userID] = user
+	return nil
+}
+
+func (r *userRepository) AdjustOpeningBonus(userID uint, delta money.Amount) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.data.users[userID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	user.OpeningBonus += delta
+	user.Bonus += delta
+	r.s.data.users[userID] = user
+	return nil
+}
+
+func (r *userRepository) RecordVerifyAttempt(userID uint, max int) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.data.users[userID]
+	if !ok {
+		return false, repository.ErrNotFound
+	}
+	if user.VerificationAttempts >= max {
+		return false, nil
+	}
+	user.VerificationAttempts++
+	r.s.data.users[userID] = user
+	return true, nil
+}
+
+func (r *userRepository) UseTOTPStep(userID uint, step int64) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.data.users[userID]
+	if !ok {
+		return false, repository.ErrNotFound
+	}
+	if user.TOTPLastStep >= step {
+		return false, nil
+	}
+	user.TOTPLastStep = step
+	r.s.data.users[userID] = user
+	return true, nil
+}
+
+func (r *userRepository) RecordTOTPFailure(userID uint, max int, lockUntil time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.data.users[userID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	user.TOTPFailedAttempts++
+	if user.TOTPFailedAttempts >= max {
+		user.TOTPFailedAttempts = 0
+		user.TOTPLockedUntil = &lockUntil
+	}
+	r.s.data.users[userID] = user
+	return nil
+}
+
+func (r *userRepository) SetBudgetAlerts(userID uint, enabled bool) error {
+	return r.update(userID, func(user *models.User) {
+		user.BudgetAlerts = enabled
+	})
+}
+
+func (r *userRepository) SetAdmin(userID uint, isAdmin bool) error {
+	return r.update(userID, func(user *models.User) {
+		user.IsAdmin = isAdmin
+	})
+}
+
+func (r *userRepository) SetPassword(userID uint, hash string) error {
+	return r.update(userID, func(user *models.User) {
+		user.Password = hash
+	})
+}
+
+func (r *userRepository) SetVerificationCode(userID uint, hash string, expiresAt, sentAt time.Time) error {
+	return r.update(userID, func(user *models.User) {
+		user.VerificationCode = hash
+		user.VerificationExpiresAt = &expiresAt
+		user.VerificationAttempts = 0
+		user.VerificationSentAt = &sentAt
+	})
+}
+
+func (r *userRepository) SetVerificationSentAt(userID uint, sentAt *time.Time) error {
+	return r.update(userID, func(user *models.User) {
+		user.VerificationSentAt = sentAt
+	})
+}
+
+func (r *userRepository) MarkVerified(userID uint) error {
+	return r.update(userID, func(user *models.User) {
+		user.Verified = true
+		user.VerificationCode = ""
+		user.VerificationExpiresAt = nil
+		user.VerificationAttempts = 0
+	})
+}
+
+func (r *userRepository) SetTOTP(userID uint, secret string, enabled bool) error {
+	return r.update(userID, func(user *models.User) {
+		user.TOTPSecret = secret
+		user.TOTPEnabled = enabled
+	})
+}
+
+func (r *userRepository) ResetTOTPFailures(userID uint) error {
+	return r.update(userID, func(user *models.User) {
+		user.TOTPFailedAttempts = 0
+		user.TOTPLockedUntil = nil
+	})
+}
+
+func (r *userRepository) update(userID uint, fn func(user *models.User)) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.data.users[userID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	fn(&user)
+	user.UpdatedAt = time.Now()
+	r.s.data.users[userID] = user
+	return nil
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type accountRepository struct {
+	db *gorm.DB
+}
+
+func (r *accountRepository) ListForUser(userID uint, includeArchived bool) ([]models.Account, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if !includeArchived {
+		query = query.Where("NOT archived")
+	}
+
+	var accounts []models.Account
+	if err := query.Order("id").Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *accountRepository) GetOwned(id, userID uint) (*models.Account, error) {
+	var account models.Account
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&account).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &account, nil
+}
+
+func (r *accountRepository) Primary(userID uint) (*models.Account, error) {
+	var account models.Account
+	if err := r.db.Where("user_id = ? AND NOT archived", userID).Order("id").First(&account).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &account, nil
+}
+
+func (r *accountRepository) LockForUser(userID uint) error {
+	var ids []uint
+	return r.db.Model(&models.Account{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).Order("id").Pluck("id", &ids).Error
+}
+
+func (r *accountRepository) Create(account *models.Account) error {
+	return r.db.Create(account).Error
+}
+
+func (r *accountRepository) Save(account *models.Account) error {
+	return r.db.Save(account).Error
+}
+
+func (r *accountRepository) UpdateDetails(account *models.Account) error {
+	return r.db.Model(account).Select("Name", "Type", "Currency", "Archived").Updates(account).Error
+}
+
+func (r *accountRepository) Delete(account *models.Account) error {
+	return r.db.Delete(account).Error
+}
+
+func (r *accountRepository) AdjustBalance(id uint, delta money.Amount) error {
+	return adjust(r.db.Model(&models.Account{}).Where("id = ?", id), "balance", delta)
+}
+
+func (r *accountRepository) AdjustOpeningBalance(id uint, delta money.Amount) error {
+	if delta == 0 {
+		return nil
+	}
+	result := r.db.Model(&models.Account{}).Where("id = ?", id).UpdateColumns(map[string]any{
+		"opening_balance": gorm.Expr("opening_balance + ?", delta),
+		"balance":         gorm.Expr("balance + ?", delta),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// adjust прибавляет delta к числовой колонке одним UPDATE, без гонки чтения и записи.
+func adjust(query *gorm.DB, column string, delta money.Amount) error {
+	if delta == 0 {
+		return nil
+	}
+	result := query.UpdateColumn(column, gorm.Expr(column+" + ?", delta))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type budgetRepository struct {
+	db *gorm.DB
+}
+
+func (r *budgetRepository) ListForUser(userID uint) ([]models.Budget, error) {
+	var budgets []models.Budget
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&budgets).Error; err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+func (r *budgetRepository) GetOwned(id, userID uint) (*models.Budget, error) {
+	var budget models.Budget
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&budget).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &budget, nil
+}
+
+func (r *budgetRepository) Create(budget *models.Budget) error {
+	return r.db.Create(budget).Error
+}
+
+func (r *budgetRepository) Save(budget *models.Budget) error {
+	return r.db.Save(budget).Error
+}
+
+func (r *budgetRepository) Delete(budget *models.Budget) error {
+	return r.db.Delete(budget).Error
+}
+
+func (r *budgetRepository) RecordAlert(budgetID uint, periodStart time.Time, threshold int) (bool, error) {
+	alert := models.BudgetAlert{BudgetID: budgetID, PeriodStart: periodStart, Threshold: threshold}
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&alert)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *budgetRepository) DeleteAlert(budgetID uint, periodStart time.Time, threshold int) error {
+	return r.db.Where("budget_id = ? AND period_start = ? AND threshold = ?", budgetID, periodStart, threshold).
+		Delete(&models.BudgetAlert{}).Error
+}
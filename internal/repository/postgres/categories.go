@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"gorm.io/gorm"
+)
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func (r *categoryRepository) ListForUser(userID uint) ([]models.Category, error) {
+	var categories []models.Category
+	if err := r.db.Where("(user_id IS NULL AND NOT retired) OR user_id = ?", userID).Order("id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) GetAvailable(id, userID uint) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.
+		Where("id = ? AND ((user_id IS NULL AND NOT retired) OR user_id = ?)", id, userID).
+		First(&category).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) GetOwned(id, userID uint) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&category).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) FindOwnedByName(userID uint, name string) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.Where("name = ? AND user_id = ?", name, userID).First(&category).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) ListDefaults() ([]models.Category, error) {
+	var categories []models.Category
+	if err := r.db.Where("user_id IS NULL").Order("id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) GetDefault(id uint) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.Where("id = ? AND user_id IS NULL", id).First(&category).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) FindDefaultBySlug(slug string) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.Where("slug = ? AND user_id IS NULL", slug).First(&category).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) Create(category *models.Category) error {
+	return r.db.Create(category).Error
+}
+
+func (r *categoryRepository) Save(category *models.Category) error {
+	return r.db.Save(category).Error
+}
+
+func (r *categoryRepository) Delete(category *models.Category) error {
+	return r.db.Delete(category).Error
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type goalRepository struct {
+	db *gorm.DB
+}
+
+func (r *goalRepository) ListForUser(userID uint) ([]models.Goal, error) {
+	var goals []models.Goal
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&goals).Error; err != nil {
+		return nil, err
+	}
+	return goals, nil
+}
+
+func (r *goalRepository) GetOwned(id, userID uint) (*models.Goal, error) {
+	var goal models.Goal
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&goal).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &goal, nil
+}
+
+func (r *goalRepository) Lock(id uint) error {
+	var goal models.Goal
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", id).First(&goal).Error
+	return wrapErr(err)
+}
+
+func (r *goalRepository) Create(goal *models.Goal) error {
+	return r.db.Create(goal).Error
+}
+
+func (r *goalRepository) Save(goal *models.Goal) error {
+	return r.db.Save(goal).Error
+}
+
+func (r *goalRepository) Delete(goal *models.Goal) error {
+	return r.db.Delete(goal).Error
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"gorm.io/gorm"
+)
+
+type importMappingRepository struct {
+	db *gorm.DB
+}
+
+func (r *importMappingRepository) ListForUser(userID uint) ([]models.ImportMapping, error) {
+	var mappings []models.ImportMapping
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&mappings).Error; err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+func (r *importMappingRepository) GetOwned(id, userID uint) (*models.ImportMapping, error) {
+	var mapping models.ImportMapping
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&mapping).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &mapping, nil
+}
+
+func (r *importMappingRepository) Create(mapping *models.ImportMapping) error {
+	return r.db.Create(mapping).Error
+}
+
+func (r *importMappingRepository) Save(mapping *models.ImportMapping) error {
+	return r.db.Save(mapping).Error
+}
+
+func (r *importMappingRepository) Delete(mapping *models.ImportMapping) error {
+	return r.db.Delete(mapping).Error
+}
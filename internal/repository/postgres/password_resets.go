@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"gorm.io/gorm"
+)
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func (r *passwordResetRepository) Create(reset *models.PasswordReset) error {
+	return r.db.Create(reset).Error
+}
+
+func (r *passwordResetRepository) GetByHash(hash string) (*models.PasswordReset, error) {
+	var reset models.PasswordReset
+	if err := r.db.Where("token_hash = ?", hash).First(&reset).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &reset, nil
+}
+
+func (r *passwordResetRepository) ListActive(userID uint, now time.Time) ([]models.PasswordReset, error) {
+	var resets []models.PasswordReset
+	err := r.db.Where("user_id = ? AND used_at IS NULL AND expires_at > ?", userID, now).
+		Order("created_at DESC, id DESC").
+		Find(&resets).Error
+	return resets, err
+}
+
+func (r *passwordResetRepository) DeleteInactive(userID uint, now time.Time) error {
+	return r.db.Where("user_id = ? AND (used_at IS NOT NULL OR expires_at <= ?)", userID, now).
+		Delete(&models.PasswordReset{}).Error
+}
+
+func (r *passwordResetRepository) MarkUsed(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&models.PasswordReset{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *passwordResetRepository) InvalidateUser(userID uint, at time.Time) error {
+	return r.db.Model(&models.PasswordReset{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", at).Error
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"gorm.io/gorm"
+)
+
+type recurringRuleRepository struct {
+	db *gorm.DB
+}
+
+func (r *recurringRuleRepository) ListForUser(userID uint) ([]models.RecurringRule, error) {
+	var rules []models.RecurringRule
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *recurringRuleRepository) GetOwned(id, userID uint) (*models.RecurringRule, error) {
+	var rule models.RecurringRule
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&rule).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &rule, nil
+}
+
+func (r *recurringRuleRepository) ListDue(now time.Time) ([]models.RecurringRule, error) {
+	var rules []models.RecurringRule
+	err := r.db.Where("next_run IS NOT NULL AND next_run <= ? AND NOT paused", now).
+		Order("next_run, id").Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *recurringRuleRepository) Create(rule *models.RecurringRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *recurringRuleRepository) Save(rule *models.RecurringRule) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *recurringRuleRepository) Delete(rule *models.RecurringRule) error {
+	return r.db.Delete(rule).Error
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"gorm.io/gorm"
+)
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func (r *sessionRepository) Create(session *models.Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r *sessionRepository) GetByToken(tokenID string) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.Where("token_id = ?", tokenID).First(&session).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) GetOwned(id, userID uint) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&session).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) ListActive(userID uint, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_used_at DESC, id DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *sessionRepository) Touch(tokenID string, at time.Time, ip string) error {
+	return r.db.Model(&models.Session{}).Where("token_id = ?", tokenID).
+		Updates(map[string]any{"last_used_at": at, "ip": ip}).Error
+}
+
+func (r *sessionRepository) Extend(tokenID string, expiresAt time.Time) error {
+	return r.db.Model(&models.Session{}).Where("token_id = ?", tokenID).
+		Update("expires_at", expiresAt).Error
+}
+
+func (r *sessionRepository) Revoke(tokenID string, at time.Time) error {
+	return r.db.Model(&models.Session{}).
+		Where("token_id = ? AND revoked_at IS NULL", tokenID).
+		Update("revoked_at", at).Error
+}
+
+func (r *sessionRepository) RevokeUser(userID uint, exceptTokenID string, at time.Time) error {
+	query := r.db.Model(&models.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptTokenID != "" {
+		query = query.Where("token_id <> ?", exceptTokenID)
+	}
+	return query.Update("revoked_at", at).Error
+}
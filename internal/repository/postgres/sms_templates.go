@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"gorm.io/gorm"
+)
+
+type smsTemplateRepository struct {
+	db *gorm.DB
+}
+
+func (r *smsTemplateRepository) ListForUser(userID uint) ([]models.SMSTemplate, error) {
+	var templates []models.SMSTemplate
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *smsTemplateRepository) GetOwned(id, userID uint) (*models.SMSTemplate, error) {
+	var template models.SMSTemplate
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&template).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &template, nil
+}
+
+func (r *smsTemplateRepository) Create(template *models.SMSTemplate) error {
+	return r.db.Create(template).Error
+}
+
+func (r *smsTemplateRepository) Save(template *models.SMSTemplate) error {
+	return r.db.Save(template).Error
+}
+
+func (r *smsTemplateRepository) Delete(template *models.SMSTemplate) error {
+	return r.db.Delete(template).Error
+}
@@ -0,0 +1,83 @@
+// Package postgres реализует интерфейсы repository поверх GORM и PostgreSQL.
+package postgres
+
+import (
+	"errors"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"gorm.io/gorm"
+)
+
+type Store struct {
+	db *gorm.DB
+}
+
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) Users() repository.UserRepository {
+	return &userRepository{db: s.db}
+}
+
+func (s *Store) Accounts() repository.AccountRepository {
+	return &accountRepository{db: s.db}
+}
+
+func (s *Store) Categories() repository.CategoryRepository {
+	return &categoryRepository{db: s.db}
+}
+
+func (s *Store) Transactions() repository.TransactionRepository {
+	return &transactionRepository{db: s.db}
+}
+
+func (s *Store) ImportMappings() repository.ImportMappingRepository {
+	return &importMappingRepository{db: s.db}
+}
+
+func (s *Store) SMSTemplates() repository.SMSTemplateRepository {
+	return &smsTemplateRepository{db: s.db}
+}
+
+func (s *Store) RecurringRules() repository.RecurringRuleRepository {
+	return &recurringRuleRepository{db: s.db}
+}
+
+func (s *Store) Budgets() repository.BudgetRepository {
+	return &budgetRepository{db: s.db}
+}
+
+func (s *Store) Goals() repository.GoalRepository {
+	return &goalRepository{db: s.db}
+}
+
+func (s *Store) RefreshTokens() repository.RefreshTokenRepository {
+	return &refreshTokenRepository{db: s.db}
+}
+
+func (s *Store) PasswordResets() repository.PasswordResetRepository {
+	return &passwordResetRepository{db: s.db}
+}
+
+func (s *Store) RecoveryCodes() repository.RecoveryCodeRepository {
+	return &recoveryCodeRepository{db: s.db}
+}
+
+func (s *Store) Sessions() repository.SessionRepository {
+	return &sessionRepository{db: s.db}
+}
+
+func (s *Store) Atomic(fn func(s repository.Store) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&Store{db: tx})
+	})
+}
+
+// wrapErr приводит ошибку GORM об отсутствии записи к repository.ErrNotFound.
+func wrapErr(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repository.ErrNotFound
+	}
+	return err
+}
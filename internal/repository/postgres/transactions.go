@@ -0,0 +1,334 @@
+package postgres
+
+import (
+	"database/sql"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"gorm.io/gorm"
+)
+
+type transactionRepository struct {
+	db *gorm.DB
+}
+
+func (r *transactionRepository) Create(transaction *models.Transaction) error {
+	return r.db.Create(transaction).Error
+}
+
+func (r *transactionRepository) GetOwned(id, userID uint) (*models.Transaction, error) {
+	var transaction models.Transaction
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&transaction).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &transaction, nil
+}
+
+func (r *transactionRepository) Save(transaction *models.Transaction) error {
+	return r.db.Save(transaction).Error
+}
+
+func (r *transactionRepository) Delete(transaction *models.Transaction) error {
+	return r.db.Delete(transaction).Error
+}
+
+// tsQuery объединяет разбор запроса русской и английской конфигурациями,
+// как и колонка search_vector из миграции 0008.
+const tsQuery = "(websearch_to_tsquery('russian', @q) || websearch_to_tsquery('english', @q))"
+
+func (r *transactionRepository) Search(userID uint, filter repository.TransactionFilter) ([]repository.SearchHit, error) {
+	if filter.Query == "" {
+		var transactions []models.Transaction
+		if err := applyFilter(r.db, userID, filter).Order("date DESC").Find(&transactions).Error; err != nil {
+			return nil, err
+		}
+		hits := make([]repository.SearchHit, len(transactions))
+		for i, t := range transactions {
+			hits[i].Transaction = t
+		}
+		return hits, nil
+	}
+
+	q := sql.Named("q", filter.Query)
+	var hits []repository.SearchHit
+	err := applyFilter(r.db.Model(&models.Transaction{}), userID, filter).
+		Select(`transactions.*,
+			ts_rank(search_vector, `+tsQuery+`) AS rank,
+			ts_headline('russian', title, `+tsQuery+`, 'StartSel=`+markStart+`, StopSel=`+markStop+`, HighlightAll=true') AS title_highlight,
+			CASE WHEN description = '' THEN '' ELSE
+				ts_headline('russian', description, `+tsQuery+`, 'StartSel=`+markStart+`, StopSel=`+markStop+`, MaxWords=20, MinWords=5')
+			END AS description_highlight`, q).
+		Order("rank DESC, date DESC").
+		Scan(&hits).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range hits {
+		hits[i].TitleHighlight = markHTML(hits[i].TitleHighlight)
+		hits[i].DescriptionHighlight = markHTML(hits[i].DescriptionHighlight)
+	}
+	return hits, nil
+}
+
+// ts_headline выделяет найденные слова управляющими символами вместо <mark>:
+// фрагмент сначала экранируется, и только потом они заменяются на теги.
+const (
+	markStart = "\x01"
+	markStop  = "\x02"
+)
+
+var markReplacer = strings.NewReplacer(markStart, "<mark>", markStop, "</mark>")
+
+// markHTML экранирует фрагмент ts_headline и заменяет разделители на <mark>.
+func markHTML(s string) string {
+	return markReplacer.Replace(html.EscapeString(s))
+}
+
+// sortColumns сопоставляет поле сортировки с колонкой таблицы.
+var sortColumns = map[repository.SortField]string{
+	repository.SortByDate:    "date",
+	repository.SortByAmount:  "amount",
+	repository.SortByTitle:   "title",
+	repository.SortByCreated: "created_at",
+}
+
+func (r *transactionRepository) List(userID uint, filter repository.TransactionFilter, opts repository.ListOptions) (*repository.TransactionPage, error) {
+	column, ok := sortColumns[opts.Sort]
+	if !ok {
+		column = "date"
+	}
+	direction, cmp := "ASC", ">"
+	if opts.Desc {
+		direction, cmp = "DESC", "<"
+	}
+
+	query := applyFilter(r.db, userID, filter)
+	if opts.After != nil {
+		var value interface{}
+		switch opts.Sort {
+		case repository.SortByAmount:
+			value = opts.After.Amount
+		case repository.SortByTitle:
+			value = opts.After.Title
+		case repository.SortByCreated:
+			value = opts.After.CreatedAt
+		default:
+			value = opts.After.Date
+		}
+		// Сравнение кортежей даёт устойчивую пагинацию при равных значениях поля
+		query = query.Where("("+column+", id) "+cmp+" (?, ?)", value, opts.After.ID)
+	}
+
+	var items []models.Transaction
+	err := query.Order(column + " " + direction).Order("id " + direction).Limit(opts.Limit + 1).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+
+	page := &repository.TransactionPage{Items: items, Sums: map[string]repository.TransactionSums{}}
+	if len(items) > opts.Limit {
+		page.Items = items[:opts.Limit]
+		page.HasMore = true
+	}
+	if opts.SkipTotals {
+		return page, nil
+	}
+
+	if err := applyFilter(r.db.Model(&models.Transaction{}), userID, filter).Count(&page.Total).Error; err != nil {
+		return nil, err
+	}
+
+	var sums []struct {
+		Currency string
+		Income   money.Amount
+		Expense  money.Amount
+	}
+	err = applyFilter(r.db.Model(&models.Transaction{}), userID, filter).
+		Select(`currency,
+			coalesce(sum(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0)::bigint AS income,
+			coalesce(sum(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0)::bigint AS expense`).
+		Group("currency").
+		Scan(&sums).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, sum := range sums {
+		page.Sums[sum.Currency] = repository.TransactionSums{Income: sum.Income, Expense: sum.Expense}
+	}
+	return page, nil
+}
+
+// applyFilter добавляет к запросу условия фильтра транзакций пользователя.
+func applyFilter(query *gorm.DB, userID uint, filter repository.TransactionFilter) *gorm.DB {
+	query = query.Where("user_id = ?", userID)
+
+	if filter.Query != "" {
+		query = query.Where("search_vector @@ "+tsQuery, sql.Named("q", filter.Query))
+	}
+	if filter.Title != nil && *filter.Title != "" {
+		query = query.Where("title ILIKE ?", "%"+*filter.Title+"%")
+	}
+	if filter.Description != nil && *filter.Description != "" {
+		query = query.Where("description ILIKE ?", "%"+*filter.Description+"%")
+	}
+	if filter.AmountMin != nil {
+		query = query.Where("amount >= ?", *filter.AmountMin)
+	}
+	if filter.AmountMax != nil {
+		query = query.Where("amount <= ?", *filter.AmountMax)
+	}
+	if filter.BonusMin != nil {
+		query = query.Where("bonus_change >= ?", *filter.BonusMin)
+	}
+	if filter.BonusMax != nil {
+		query = query.Where("bonus_change <= ?", *filter.BonusMax)
+	}
+	if filter.DateFrom != nil {
+		query = query.Where("date >= ?", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		query = query.Where("date < ?", *filter.DateTo)
+	}
+	if len(filter.Categories) > 0 {
+		query = query.Where("category IN ?", filter.Categories)
+	}
+	if len(filter.Accounts) > 0 {
+		query = query.Where("(account_id IN ? OR counter_account_id IN ?)", filter.Accounts, filter.Accounts)
+	}
+	if len(filter.Currencies) > 0 {
+		query = query.Where("currency IN ?", filter.Currencies)
+	}
+	if len(filter.Types) > 0 {
+		query = query.Where("type IN ?", filter.Types)
+	}
+	if filter.BonusType != nil && *filter.BonusType != "" {
+		query = query.Where("bonus_type = ?", *filter.BonusType)
+	}
+
+	if len(filter.ExcludeCategories) > 0 {
+		query = query.Where("category NOT IN ?", filter.ExcludeCategories)
+	}
+	if len(filter.ExcludeAccounts) > 0 {
+		// У обычных транзакций counter_account_id пуст, NOT IN для NULL не сработает
+		query = query.Where("account_id NOT IN ? AND (counter_account_id IS NULL OR counter_account_id NOT IN ?)",
+			filter.ExcludeAccounts, filter.ExcludeAccounts)
+	}
+	if len(filter.ExcludeCurrencies) > 0 {
+		query = query.Where("currency NOT IN ?", filter.ExcludeCurrencies)
+	}
+	if len(filter.ExcludeTypes) > 0 {
+		query = query.Where("type NOT IN ?", filter.ExcludeTypes)
+	}
+
+	return query
+}
+
+func (r *transactionRepository) ExistsForAccount(accountID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.Transaction{}).Where("account_id = ? OR counter_account_id = ?", accountID, accountID).Limit(1).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *transactionRepository) ExistingExternalIDs(accountID uint, ids []string) (map[string]bool, error) {
+	existing := map[string]bool{}
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	var found []string
+	err := r.db.Model(&models.Transaction{}).
+		Where("account_id = ? AND external_id IN ?", accountID, ids).
+		Pluck("external_id", &found).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range found {
+		existing[id] = true
+	}
+	return existing, nil
+}
+
+func (r *transactionRepository) FindByReceipt(userID uint, drive, document, sign string) (*models.Transaction, error) {
+	var transaction models.Transaction
+	err := r.db.Where("user_id = ? AND fiscal_drive = ? AND fiscal_document = ? AND fiscal_sign = ?", userID, drive, document, sign).
+		First(&transaction).Error
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	return &transaction, nil
+}
+
+func (r *transactionRepository) ExistsForOccurrence(ruleID uint, occurrence time.Time) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Transaction{}).
+		Where("recurring_id = ? AND occurrence = ?", ruleID, occurrence).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *transactionRepository) ListForGoal(goalID uint) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	if err := r.db.Where("goal_id = ?", goalID).Order("date, id").Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (r *transactionRepository) ReassignCategory(userID, from, to uint) error {
+	return r.db.Model(&models.Transaction{}).
+		Where("category = ? AND user_id = ?", from, userID).
+		Update("category", to).Error
+}
+
+// Ledger считает суммы в SQL. Формулы повторяют models.Transaction.BalanceEffect,
+// CounterEffect и BonusEffect.
+func (r *transactionRepository) Ledger(userID uint) (*repository.LedgerTotals, error) {
+	var rows []struct {
+		AccountID uint
+		Total     money.Amount
+	}
+	err := r.db.Raw(`
+		SELECT account_id, sum(total)::bigint AS total FROM (
+			SELECT account_id,
+			       CASE type
+			           WHEN 'income' THEN amount
+			           WHEN 'expense' THEN -amount
+			           WHEN 'transfer' THEN -(amount + fee)
+			           ELSE 0
+			       END AS total
+			FROM transactions
+			WHERE user_id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT counter_account_id, counter_amount
+			FROM transactions
+			WHERE user_id = ? AND deleted_at IS NULL AND type = 'transfer' AND counter_account_id IS NOT NULL
+		) effects
+		GROUP BY account_id`, userID, userID).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := &repository.LedgerTotals{Accounts: make(map[uint]money.Amount, len(rows))}
+	for _, row := range rows {
+		totals.Accounts[row.AccountID] = row.Total
+	}
+
+	err = r.db.Raw(`
+		SELECT coalesce(sum(CASE bonus_type
+		                        WHEN 'income' THEN bonus_change
+		                        WHEN 'expense' THEN -bonus_change
+		                        ELSE 0
+		                    END), 0)::bigint
+		FROM transactions
+		WHERE user_id = ? AND deleted_at IS NULL`, userID).Scan(&totals.Bonus).Error
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
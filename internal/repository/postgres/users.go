@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func (r *userRepository) GetByID(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &user, nil
+}
+
+func (r *userRepository) GetByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, wrapErr(err)
+	}
+	return &user, nil
+}
+
+func (r *userRepository) Lock(id uint) error {
+	var user models.User
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", id).First(&user).Error
+	return wrapErr(err)
+}
+
+func (r *userRepository) Create(user *models.User) error {
+	return r.db.Create(user).Error
+}
+
+func (r *userRepository) Save(user *models.User) error {
+	return r.db.Save(user).Error
+}
+
+func (r *userRepository) ListIDs() ([]uint, error) {
+	var ids []uint
+	if err := r.db.Model(&models.User{}).Order("id").Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *userRepository) AdjustBonus(userID uint, delta money.Amount) error {
+	return adjust(r.db.Model(&models.User{}).Where("id = ?", userID), "bonus", delta)
+}
+
+func (r *userRepository) AdjustOpeningBonus(userID uint, delta money.Amount) error {
+	if delta == 0 {
+		return nil
+	}
+	result := r.db.Model(&models.User{}).Where("id = ?", userID).UpdateColumns(map[string]any{
+		"opening_bonus": gorm.Expr("opening_bonus + ?", delta),
+		"bonus":         gorm.Expr("bonus + ?", delta),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) RecordVerifyAttempt(userID uint, max int) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND verification_attempts < ?", userID, max).
+		Update("verification_attempts", gorm.Expr("verification_attempts + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *userRepository) UseTOTPStep(userID uint, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *userRepository) RecordTOTPFailure(userID uint, max int, lockUntil time.Time) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]any{
+		"totp_locked_until":    gorm.Expr("CASE WHEN totp_failed_attempts + 1 >= ? THEN ? ELSE totp_locked_until END", max, lockUntil),
+		"totp_failed_attempts": gorm.Expr("CASE WHEN totp_failed_attempts + 1 >= ? THEN 0 ELSE totp_failed_attempts + 1 END", max),
+	}).Error
+}
+
+func (r *userRepository) SetBudgetAlerts(userID uint, enabled bool) error {
+	return r.updateColumns(userID, map[string]any{"budget_alerts": enabled})
+}
+
+func (r *userRepository) SetAdmin(userID uint, isAdmin bool) error {
+	return r.updateColumns(userID, map[string]any{"is_admin": isAdmin})
+}
+
+func (r *userRepository) SetPassword(userID uint, hash string) error {
+	return r.updateColumns(userID, map[string]any{"password": hash})
+}
+
+func (r *userRepository) SetVerificationCode(userID uint, hash string, expiresAt, sentAt time.Time) error {
+	return r.updateColumns(userID, map[string]any{
+		"verification_code":       hash,
+		"verification_expires_at": expiresAt,
+		"verification_attempts":   0,
+		"verification_sent_at":    sentAt,
+	})
+}
+
+func (r *userRepository) SetVerificationSentAt(userID uint, sentAt *time.Time) error {
+	return r.updateColumns(userID, map[string]any{"verification_sent_at": sentAt})
+}
+
+func (r *userRepository) MarkVerified(userID uint) error {
+	return r.updateColumns(userID, map[string]any{
+		"verified":                true,
+		"verification_code":       "",
+		"verification_expires_at": nil,
+		"verification_attempts":   0,
+	})
+}
+
+func (r *userRepository) SetTOTP(userID uint, secret string, enabled bool) error {
+	return r.updateColumns(userID, map[string]any{"totp_secret": secret, "totp_enabled": enabled})
+}
+
+func (r *userRepository) ResetTOTPFailures(userID uint) error {
+	return r.updateColumns(userID, map[string]any{"totp_failed_attempts": 0, "totp_locked_until": nil})
+}
+
+// updateColumns меняет только указанные колонки пользователя, не перезаписывая
+// остальные, например бонусы, которые транзакции меняют атомарно.
+func (r *userRepository) updateColumns(userID uint, columns map[string]any) error {
+	result := r.db.Model(&models.User{}).Where("id = ?", userID).Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+// Package repository описывает доступ к данным приложения через интерфейсы,
+// чтобы обработчики не зависели от конкретного хранилища.
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+)
+
+// ErrNotFound возвращается, если запись не найдена или не принадлежит пользователю.
+var ErrNotFound = errors.New("запись не найдена")
+
+type UserRepository interface {
+	GetByID(id uint) (*models.User, error)
+	GetByEmail(email string) (*models.User, error)
+	// Lock блокирует строку пользователя до конца транзакции (SELECT ... FOR UPDATE).
+	Lock(id uint) error
+	Create(user *models.User) error
+	Save(user *models.User) error
+	// AdjustBonus атомарно прибавляет delta к бонусному балансу пользователя.
+	AdjustBonus(userID uint, delta money.Amount) error
+	// AdjustOpeningBonus атомарно прибавляет delta к начальным бонусам и бонусному балансу.
+	AdjustOpeningBonus(userID uint, delta money.Amount) error
+	ListIDs() ([]uint, error)
+	SetBudgetAlerts(userID uint, enabled bool) error
+	// SetAdmin выдаёт или отзывает права администратора, не трогая остальные поля.
+	SetAdmin(userID uint, isAdmin bool) error
+	// SetPassword сохраняет новый bcrypt-хеш пароля.
+	SetPassword(userID uint, hash string) error
+	// SetVerificationCode сохраняет хеш нового кода подтверждения и сбрасывает счётчик попыток.
+	SetVerificationCode(userID uint, hash string, expiresAt, sentAt time.Time) error
+	SetVerificationSentAt(userID uint, sentAt *time.Time) error
+	// MarkVerified подтверждает почту и удаляет код подтверждения.
+	MarkVerified(userID uint) error
+	// RecordVerifyAttempt атомарно засчитывает попытку ввода кода подтверждения.
+	// Возвращает false, если использованы все max попыток.
+	RecordVerifyAttempt(userID uint, max int) (bool, error)
+	// SetTOTP сохраняет TOTP-секрет и признак включённой 2FA.
+	SetTOTP(userID uint, secret string, enabled bool) error
+	// UseTOTPStep атомарно запоминает интервал принятого TOTP-кода.
+	// Возвращает false, если код этого или более позднего интервала уже принят.
+	UseTOTPStep(userID uint, step int64) (bool, error)
+	// RecordTOTPFailure засчитывает неверный код второго фактора. На max-й
+	// ошибке счётчик сбрасывается, а ввод блокируется до lockUntil.
+	RecordTOTPFailure(userID uint, max int, lockUntil time.Time) error
+	// ResetTOTPFailures сбрасывает счётчик неверных кодов и блокировку.
+	ResetTOTPFailures(userID uint) error
+}
+
+type AccountRepository interface {
+	ListForUser(userID uint, includeArchived bool) ([]models.Account, error)
+	GetOwned(id, userID uint) (*models.Account, error)
+	// Primary возвращает самый старый неархивный счёт пользователя.
+	Primary(userID uint) (*models.Account, error)
+	// LockForUser блокирует все счета пользователя до конца транзакции
+	// (SELECT ... FOR UPDATE), включая архивные.
+	LockForUser(userID uint) error
+	Create(account *models.Account) error
+	Save(account *models.Account) error
+	// UpdateDetails сохраняет название, тип, валюту и признак архива, не трогая остатки.
+	UpdateDetails(account *models.Account) error
+	Delete(account *models.Account) error
+	// AdjustBalance атомарно прибавляет delta к балансу счёта.
+	AdjustBalance(id uint, delta money.Amount) error
+	// AdjustOpeningBalance атомарно прибавляет delta к начальному остатку и балансу счёта.
+	AdjustOpeningBalance(id uint, delta money.Amount) error
+}
+
+type CategoryRepository interface {
+	// ListForUser возвращает категории пользователя вместе с действующими категориями по умолчанию.
+	ListForUser(userID uint) ([]models.Category, error)
+	// GetAvailable ищет категорию пользователя или действующую категорию по умолчанию.
+	GetAvailable(id, userID uint) (*models.Category, error)
+	// GetOwned ищет только категорию, созданную пользователем.
+	GetOwned(id, userID uint) (*models.Category, error)
+	FindOwnedByName(userID uint, name string) (*models.Category, error)
+	// ListDefaults возвращает все категории по умолчанию, включая выведенные из оборота.
+	ListDefaults() ([]models.Category, error)
+	GetDefault(id uint) (*models.Category, error)
+	FindDefaultBySlug(slug string) (*models.Category, error)
+	Create(category *models.Category) error
+	Save(category *models.Category) error
+	Delete(category *models.Category) error
+}
+
+// TransactionFilter задаёт условия поиска транзакций.
+// Пустые поля не участвуют в фильтрации. Списки значений объединяются
+// через ИЛИ, а все условия между собой — через И.
+type TransactionFilter struct {
+	// Query — полнотекстовый запрос по названию и описанию: слова, "фразы" и -исключения.
+	Query       string
+	Title       *string
+	Description *string
+	AmountMin   *money.Amount
+	AmountMax   *money.Amount
+	BonusMin    *money.Amount
+	BonusMax    *money.Amount
+	DateFrom    *time.Time // включительно
+	DateTo      *time.Time // не включительно
+	Categories  []uint
+	Accounts    []uint // совпадает и со счётом зачисления перевода
+	Currencies  []string
+	Types       []string
+	BonusType   *string
+
+	ExcludeCategories []uint
+	ExcludeAccounts   []uint
+	ExcludeCurrencies []string
+	ExcludeTypes      []string
+}
+
+// SearchHit — найденная транзакция. При полнотекстовом поиске заполняются
+// релевантность и экранированные HTML-фрагменты с найденными словами,
+// выделенными тегом <mark>.
+type SearchHit struct {
+	models.Transaction
+	Rank                 float64 `json:"rank,omitempty"`
+	TitleHighlight       string  `json:"titleHighlight,omitempty"`
+	DescriptionHighlight string  `json:"descriptionHighlight,omitempty"`
+}
+
+// SortField — поле сортировки списка транзакций.
+type SortField string
+
+const (
+	SortByDate    SortField = "date"
+	SortByAmount  SortField = "amount"
+	SortByTitle   SortField = "title"
+	SortByCreated SortField = "created"
+)
+
+// Cursor указывает на последнюю транзакцию предыдущей страницы.
+// Используется поле, соответствующее сортировке, и ID для однозначности.
+type Cursor struct {
+	Date      time.Time    `json:"d,omitempty"`
+	Amount    money.Amount `json:"a,omitempty"`
+	Title     string       `json:"t,omitempty"`
+	CreatedAt time.Time    `json:"c,omitempty"`
+	ID        uint         `json:"i"`
+}
+
+// CursorFor строит курсор по транзакции.
+func CursorFor(t models.Transaction) Cursor {
+	return Cursor{Date: t.Date, Amount: t.Amount, Title: t.Title, CreatedAt: t.CreatedAt, ID: t.ID}
+}
+
+type ListOptions struct {
+	Sort  SortField
+	Desc  bool
+	Limit int
+	After *Cursor
+	// SkipTotals разрешает не считать Total и Sums, когда они не нужны,
+	// например при выгрузке по страницам.
+	SkipTotals bool
+}
+
+// TransactionSums — суммы доходов и расходов в одной валюте. Переводы не учитываются.
+type TransactionSums struct {
+	Income  money.Amount `json:"income" swaggertype:"number"`
+	Expense money.Amount `json:"expense" swaggertype:"number"`
+}
+
+type TransactionPage struct {
+	Items   []models.Transaction
+	HasMore bool
+	// Total и Sums считаются по всему фильтру без учёта курсора и лимита.
+	Total int64
+	Sums  map[string]TransactionSums
+}
+
+type TransactionRepository interface {
+	Create(transaction *models.Transaction) error
+	GetOwned(id, userID uint) (*models.Transaction, error)
+	Save(transaction *models.Transaction) error
+	Delete(transaction *models.Transaction) error
+	// Search возвращает транзакции пользователя, отсортированные по дате (сначала новые),
+	// а при полнотекстовом запросе — по релевантности.
+	Search(userID uint, filter TransactionFilter) ([]SearchHit, error)
+	// List возвращает страницу транзакций с итогами по фильтру.
+	List(userID uint, filter TransactionFilter, opts ListOptions) (*TransactionPage, error)
+	// ReassignCategory переносит транзакции пользователя из одной категории в другую.
+	ReassignCategory(userID, from, to uint) error
+	// ExistsForAccount сообщает, есть ли транзакции по счёту.
+	ExistsForAccount(accountID uint) (bool, error)
+	// ExistingExternalIDs возвращает те из ids, которые уже есть у транзакций счёта.
+	ExistingExternalIDs(accountID uint, ids []string) (map[string]bool, error)
+	// FindByReceipt ищет транзакцию пользователя по фискальным признакам чека.
+	FindByReceipt(userID uint, drive, document, sign string) (*models.Transaction, error)
+	// ExistsForOccurrence сообщает, создана ли уже транзакция повторения, в том числе удалённая.
+	ExistsForOccurrence(ruleID uint, occurrence time.Time) (bool, error)
+	// ListForGoal возвращает транзакции цели накоплений по дате, сначала старые.
+	ListForGoal(goalID uint) ([]models.Transaction, error)
+	// Ledger суммирует влияние всех транзакций пользователя на счета и бонусы.
+	Ledger(userID uint) (*LedgerTotals, error)
+}
+
+// LedgerTotals — суммарное влияние транзакций на балансы без учёта начальных остатков.
+type LedgerTotals struct {
+	Accounts map[uint]money.Amount
+	Bonus    money.Amount
+}
+
+type ImportMappingRepository interface {
+	ListForUser(userID uint) ([]models.ImportMapping, error)
+	GetOwned(id, userID uint) (*models.ImportMapping, error)
+	Create(mapping *models.ImportMapping) error
+	Save(mapping *models.ImportMapping) error
+	Delete(mapping *models.ImportMapping) error
+}
+
+type SMSTemplateRepository interface {
+	ListForUser(userID uint) ([]models.SMSTemplate, error)
+	GetOwned(id, userID uint) (*models.SMSTemplate, error)
+	Create(template *models.SMSTemplate) error
+	Save(template *models.SMSTemplate) error
+	Delete(template *models.SMSTemplate) error
+}
+
+type RecurringRuleRepository interface {
+	ListForUser(userID uint) ([]models.RecurringRule, error)
+	GetOwned(id, userID uint) (*models.RecurringRule, error)
+	// ListDue возвращает действующие правила всех пользователей, у которых NextRun не позже now.
+	ListDue(now time.Time) ([]models.RecurringRule, error)
+	Create(rule *models.RecurringRule) error
+	Save(rule *models.RecurringRule) error
+	Delete(rule *models.RecurringRule) error
+}
+
+type BudgetRepository interface {
+	ListForUser(userID uint) ([]models.Budget, error)
+	GetOwned(id, userID uint) (*models.Budget, error)
+	Create(budget *models.Budget) error
+	Save(budget *models.Budget) error
+	Delete(budget *models.Budget) error
+	// RecordAlert запоминает уведомление о пороге бюджета за период.
+	// Возвращает false, если такое уведомление уже было.
+	RecordAlert(budgetID uint, periodStart time.Time, threshold int) (bool, error)
+	// DeleteAlert забывает уведомление, например если письмо не удалось отправить.
+	DeleteAlert(budgetID uint, periodStart time.Time, threshold int) error
+}
+
+type GoalRepository interface {
+	ListForUser(userID uint) ([]models.Goal, error)
+	GetOwned(id, userID uint) (*models.Goal, error)
+	// Lock блокирует строку цели до конца транзакции (SELECT ... FOR UPDATE),
+	// чтобы параллельные снятия не прошли проверку по одной и той же сумме.
+	Lock(id uint) error
+	Create(goal *models.Goal) error
+	Save(goal *models.Goal) error
+	Delete(goal *models.Goal) error
+}
+
+type RefreshTokenRepository interface {
+	Create(token *models.RefreshToken) error
+	GetByHash(hash string) (*models.RefreshToken, error)
+	// MarkUsed атомарно помечает токен использованным. Возвращает false,
+	// если токен уже использован или отозван.
+	MarkUsed(id uint, at time.Time) (bool, error)
+	// RevokeFamily отзывает все действующие токены семейства.
+	RevokeFamily(familyID string, at time.Time) error
+	// RevokeUser отзывает все действующие токены пользователя, кроме семейства exceptFamilyID, если он не пуст.
+	RevokeUser(userID uint, exceptFamilyID string, at time.Time) error
+}
+
+type RecoveryCodeRepository interface {
+	// Replace заменяет все коды восстановления пользователя новыми.
+	Replace(userID uint, hashes []string) error
+	// Use атомарно помечает код использованным. Возвращает false, если кода нет или он уже использован.
+	Use(userID uint, hash string, at time.Time) (bool, error)
+	CountUnused(userID uint) (int, error)
+	DeleteForUser(userID uint) error
+}
+
+type SessionRepository interface {
+	Create(session *models.Session) error
+	GetByToken(tokenID string) (*models.Session, error)
+	GetOwned(id, userID uint) (*models.Session, error)
+	// ListActive возвращает неотозванные и не истёкшие сессии пользователя, последние использованные первыми.
+	ListActive(userID uint, now time.Time) ([]models.Session, error)
+	// Touch запоминает время и адрес последнего использования сессии.
+	Touch(tokenID string, at time.Time, ip string) error
+	Extend(tokenID string, expiresAt time.Time) error
+	Revoke(tokenID string, at time.Time) error
+	// RevokeUser отзывает все сессии пользователя, кроме exceptTokenID, если он не пуст.
+	RevokeUser(userID uint, exceptTokenID string, at time.Time) error
+}
+
+type PasswordResetRepository interface {
+	Create(reset *models.PasswordReset) error
+	GetByHash(hash string) (*models.PasswordReset, error)
+	// ListActive возвращает неиспользованные и не истёкшие токены пользователя, новые первыми.
+	ListActive(userID uint, now time.Time) ([]models.PasswordReset, error)
+	// DeleteInactive удаляет использованные и истёкшие токены пользователя.
+	DeleteInactive(userID uint, now time.Time) error
+	// MarkUsed атомарно помечает токен использованным. Возвращает false, если он уже использован.
+	MarkUsed(id uint, at time.Time) (bool, error)
+	// InvalidateUser помечает использованными все неиспользованные токены пользователя.
+	InvalidateUser(userID uint, at time.Time) error
+}
+
+// Store объединяет репозитории одного хранилища.
+type Store interface {
+	Users() UserRepository
+	Accounts() AccountRepository
+	Categories() CategoryRepository
+	Transactions() TransactionRepository
+	ImportMappings() ImportMappingRepository
+	SMSTemplates() SMSTemplateRepository
+	RecurringRules() RecurringRuleRepository
+	Budgets() BudgetRepository
+	Goals() GoalRepository
+	RefreshTokens() RefreshTokenRepository
+	PasswordResets() PasswordResetRepository
+	RecoveryCodes() RecoveryCodeRepository
+	Sessions() SessionRepository
+	// Atomic выполняет fn в одной транзакции: при ошибке все изменения откатываются.
+	Atomic(fn func(s Store) error) error
+}
@@ -1,5 +1,11 @@
 package response
 
+import (
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+)
+
 // ErrorResponse представляет стандартный формат ответа при ошибке
 // @Description Стандартный ответ при ошибке
 type ErrorResponse struct {
@@ -13,7 +19,17 @@ type SuccessResponse struct {
 }
 
 type TokenResponse struct {
-	Token string `json:"token" example:"Ваш токен"`
+	Token            string    `json:"token" example:"Ваш токен"` // access-токен для заголовка Authorization
+	ExpiresAt        time.Time `json:"expiresAt"`
+	RefreshToken     string    `json:"refreshToken"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+}
+
+// TwoFactorChallengeResponse возвращается при входе, если включена двухфакторная аутентификация
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool      `json:"twoFactorRequired"`
+	ChallengeToken    string    `json:"challengeToken"` // передаётся в /auth/2fa/verify вместе с кодом
+	ExpiresAt         time.Time `json:"expiresAt"`
 }
 
 type MessageResponse struct {
@@ -21,9 +37,10 @@ type MessageResponse struct {
 }
 
 type BalanceResponse struct {
-	Balance float64 `json:"balance"`
+	Balance money.Amount            `json:"balance" swaggertype:"number"`
+	Totals  map[string]money.Amount `json:"totals" swaggertype:"object,number"`
 }
 
 type BonusResponse struct {
-	Balance float64 `json:"balance"`
+	Balance money.Amount `json:"balance" swaggertype:"number"`
 }
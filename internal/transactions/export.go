@@ -0,0 +1,405 @@
+package transactions
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/httputil"
+	"github.com/Anabol1ks/pers-fin-m/internal/journal"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// Форматы выгрузки.
+const (
+	ExportCSV  = "csv"
+	ExportXLSX = "xlsx"
+	ExportJSON = "json"
+
+	ExportLedger    = journal.Ledger
+	ExportBeancount = journal.Beancount
+)
+
+// exportBatch — сколько транзакций читается из хранилища за раз.
+const exportBatch = 500
+
+type TransactionExportInput struct {
+	TransactionSearchInput
+	Format string `form:"format" binding:"omitempty,oneof=csv xlsx json ledger beancount"`
+}
+
+// ExportRow — транзакция в выгрузке: с названиями категории и счетов вместо ID.
+type ExportRow struct {
+	ID             uint         `json:"id"`
+	Date           time.Time    `json:"date"`
+	Type           string       `json:"type"`
+	Amount         money.Amount `json:"amount" swaggertype:"number"`
+	Currency       string       `json:"currency"`
+	Account        string       `json:"account"`
+	Category       string       `json:"category"`
+	Title          string       `json:"title"`
+	Description    string       `json:"description"`
+	BonusChange    money.Amount `json:"bonusChange" swaggertype:"number"`
+	BonusType      string       `json:"typeBonus"`
+	CounterAccount string       `json:"counterAccount,omitempty"`
+	CounterAmount  money.Amount `json:"counterAmount,omitempty" swaggertype:"number"`
+	Fee            money.Amount `json:"fee,omitempty" swaggertype:"number"`
+}
+
+// exportColumns — заголовки CSV и XLSX в порядке полей ExportRow.
+var exportColumns = []string{
+	"ID", "Дата", "Тип", "Сумма", "Валюта", "Счёт", "Категория", "Название", "Описание",
+	"Бонусы", "Тип бонусов", "Счёт зачисления", "Сумма зачисления", "Комиссия",
+}
+
+var typeNames = map[string]string{
+	string(models.Income):   "Доход",
+	string(models.Expense):  "Расход",
+	string(models.Transfer): "Перевод",
+}
+
+// exportWriter записывает транзакции в одном формате.
+type exportWriter interface {
+	Write(t models.Transaction) error
+	Close() error
+}
+
+// rowWriter записывает строки табличных форматов и JSON.
+type rowWriter interface {
+	WriteRow(row ExportRow) error
+	Close() error
+}
+
+// rowExport превращает транзакции в строки ExportRow с названиями вместо ID.
+type rowExport struct {
+	rowWriter
+	names *exportNames
+}
+
+func (e rowExport) Write(t models.Transaction) error {
+	return e.WriteRow(e.names.row(t))
+}
+
+// journalExport записывает журнал ledger или beancount.
+type journalExport struct {
+	*journal.Writer
+}
+
+func (e journalExport) Close() error {
+	return e.Flush()
+}
+
+// @Security BearerAuth
+// ExportTransactions godoc
+// @Summary Выгрузить транзакции
+// @Description Выгружает транзакции в CSV (UTF-8, разделитель «,»), XLSX, JSON или журнал ledger/hledger и beancount в хронологическом порядке.
+// @Description В журнале счета становятся счетами Assets (кредитные карты — Liabilities), категории — счетами Income и Expenses,
+// @Description комиссии переводов — Expenses:Комиссии, бонусы учитываются товаром BONUS на счёте Assets:Бонусы. Без фильтров в журнал
+// @Description записываются начальные остатки счетов и бонусов. Журнал можно загрузить обратно через /transactions/import.
+// @Description Фильтры совпадают с /transactions/search. Вместо ID указываются названия категорий и счетов, названия категорий по умолчанию переводятся по lang или Accept-Language.
+// @Description Файл отдаётся потоком, транзакции читаются из базы частями.
+// @Tags Transactions
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce json
+// @Produce text/plain
+// @Param format query string false "Формат файла, по умолчанию csv" Enums(csv, xlsx, json, ledger, beancount)
+// @Param lang query string false "Язык названий категорий"
+// @Param q query string false "Полнотекстовый поиск по названию и описанию: слова, «фразы» в кавычках, -исключения"
+// @Param title query string false "Название транзакции (частичное совпадение)"
+// @Param amount query number false "Приблизительная сумма транзакции (±10%)"
+// @Param amountMin query number false "Минимальная сумма"
+// @Param amountMax query number false "Максимальная сумма"
+// @Param bonusChange query number false "Приблизительное количество бонусов"
+// @Param date query string false "Дата транзакции (формат YYYY-MM-DD)"
+// @Param dateFrom query string false "Начало периода включительно (YYYY-MM-DD)"
+// @Param dateTo query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Param period query string false "Относительный период" Enums(today, yesterday, this_week, last_week, this_month, last_month, this_year, last_year, last_7_days, last_30_days)
+// @Param category query []int false "ID категорий" collectionFormat(multi)
+// @Param account query []int false "ID счетов" collectionFormat(multi)
+// @Param currency query []string false "Валюты" collectionFormat(multi)
+// @Param type query []string false "Типы транзакций (income, expense, transfer)" collectionFormat(multi)
+// @Param typeBonus query string false "Тип бонуса"
+// @Param excludeCategory query []int false "Исключить категории" collectionFormat(multi)
+// @Param excludeAccount query []int false "Исключить счета" collectionFormat(multi)
+// @Param excludeCurrency query []string false "Исключить валюты" collectionFormat(multi)
+// @Param excludeType query []string false "Исключить типы транзакций" collectionFormat(multi)
+// @Success 200 {array} ExportRow "Файл выгрузки; для format=json — массив строк"
+// @Failure 400 {object} response.ErrorResponse "Неверные параметры запроса"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при выгрузке транзакций"
+// @Router /transactions/export [get]
+func (h *Handler) ExportTransactions(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input TransactionExportInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.Format == "" {
+		input.Format = ExportCSV
+	}
+
+	filter, err := searchFilter(input.TransactionSearchInput, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	names, err := h.exportNames(userID, httputil.RequestLang(c))
+	if err != nil {
+		log.Println("Ошибка при выгрузке транзакций:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при выгрузке транзакций"})
+		return
+	}
+
+	opts := repository.ListOptions{Sort: repository.SortByDate, Limit: exportBatch, SkipTotals: true}
+	// Первая порция читается до заголовков ответа, чтобы ошибку можно было вернуть в JSON
+	page, err := h.store.Transactions().List(userID, filter, opts)
+	if err != nil {
+		log.Println("Ошибка при выгрузке транзакций:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при выгрузке транзакций"})
+		return
+	}
+
+	// Начальные остатки имеют смысл, только если выгружается вся история
+	full := reflect.ValueOf(input.TransactionSearchInput).IsZero()
+	var book journal.Book
+	if input.Format == ExportLedger || input.Format == ExportBeancount {
+		if book, err = h.journalBook(userID, names, full); err != nil {
+			log.Println("Ошибка при выгрузке транзакций:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при выгрузке транзакций"})
+			return
+		}
+	}
+
+	extension := input.Format
+	if input.Format == ExportLedger {
+		extension = "journal"
+	}
+	filename := "transactions-" + time.Now().Format("2006-01-02") + "." + extension
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	var w exportWriter
+	switch input.Format {
+	case ExportXLSX:
+		c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		var rows rowWriter
+		rows, err = newXLSXExport(c.Writer)
+		w = rowExport{rows, names}
+	case ExportJSON:
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		var rows rowWriter
+		rows, err = newJSONExport(c.Writer)
+		w = rowExport{rows, names}
+	case ExportLedger, ExportBeancount:
+		c.Header("Content-Type", "text/plain; charset=utf-8")
+		var first time.Time
+		if len(page.Items) > 0 {
+			first = page.Items[0].Date
+		}
+		writer := journal.NewWriter(c.Writer, input.Format, book)
+		writer.Header(first, full)
+		w = journalExport{writer}
+	default:
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		var rows rowWriter
+		rows, err = newCSVExport(c.Writer)
+		w = rowExport{rows, names}
+	}
+	c.Status(http.StatusOK)
+
+	for err == nil {
+		for _, t := range page.Items {
+			if err = w.Write(t); err != nil {
+				break
+			}
+		}
+		if err != nil || !page.HasMore {
+			break
+		}
+		if flusher, ok := w.(journalExport); ok {
+			err = flusher.Flush()
+		}
+		c.Writer.Flush()
+
+		cursor := repository.CursorFor(page.Items[len(page.Items)-1])
+		opts.After = &cursor
+		page, err = h.store.Transactions().List(userID, filter, opts)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	// Заголовки уже отправлены, ошибку можно только записать в лог:
+	// JSON и XLSX без завершения не откроются, CSV окажется неполным
+	if err != nil {
+		log.Println("Ошибка при выгрузке транзакций:", err)
+	}
+}
+
+// exportNames — названия категорий и счета пользователя по ID.
+type exportNames struct {
+	categories map[uint]string
+	accounts   map[uint]models.Account
+}
+
+// exportNames собирает названия категорий и счета пользователя.
+// Категории по умолчанию берутся все, включая выведенные из оборота.
+func (h *Handler) exportNames(userID uint, lang string) (*exportNames, error) {
+	names := &exportNames{categories: map[uint]string{}, accounts: map[uint]models.Account{}}
+	defaults, err := h.store.Categories().ListDefaults()
+	if err != nil {
+		return nil, err
+	}
+	own, err := h.store.Categories().ListForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, category := range append(defaults, own...) {
+		names.categories[category.ID] = category.LocalizedName(lang)
+	}
+
+	list, err := h.store.Accounts().ListForUser(userID, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range list {
+		names.accounts[account.ID] = account
+	}
+	return names, nil
+}
+
+// row возвращает строку выгрузки для транзакции.
+func (n *exportNames) row(t models.Transaction) ExportRow {
+	row := ExportRow{
+		ID:          t.ID,
+		Date:        t.Date,
+		Type:        string(t.Type),
+		Amount:      t.Amount,
+		Currency:    t.Currency,
+		Account:     n.accounts[t.AccountID].Name,
+		Category:    n.categories[t.Category],
+		Title:       t.Title,
+		Description: t.Description,
+		BonusChange: t.BonusChange,
+		BonusType:   string(t.BonusType),
+		Fee:         t.Fee,
+	}
+	if t.CounterAccountID != nil {
+		row.CounterAccount = n.accounts[*t.CounterAccountID].Name
+		row.CounterAmount = t.CounterAmount
+	}
+	return row
+}
+
+// journalBook собирает справочники для журнала. Начальные бонусы
+// запрашиваются, только если выгружается вся история.
+func (h *Handler) journalBook(userID uint, names *exportNames, full bool) (journal.Book, error) {
+	book := journal.Book{Accounts: names.accounts, Categories: names.categories}
+	if !full {
+		return book, nil
+	}
+	user, err := h.store.Users().GetByID(userID)
+	if err != nil {
+		return book, err
+	}
+	book.OpeningBonus = user.OpeningBonus
+	return book, nil
+}
+
+// cells возвращает значения строки для табличных форматов.
+func (r ExportRow) cells() []string {
+	cells := []string{
+		strconv.FormatUint(uint64(r.ID), 10),
+		r.Date.Format("2006-01-02 15:04:05"),
+		typeNames[r.Type],
+		r.Amount.String(),
+		r.Currency,
+		textCell(r.Account),
+		textCell(r.Category),
+		textCell(r.Title),
+		textCell(r.Description),
+		"", "", textCell(r.CounterAccount), "", "",
+	}
+	if r.BonusChange != 0 {
+		cells[9], cells[10] = r.BonusChange.String(), typeNames[r.BonusType]
+	}
+	if r.CounterAccount != "" {
+		cells[12] = r.CounterAmount.String()
+	}
+	if r.Fee != 0 {
+		cells[13] = r.Fee.String()
+	}
+	return cells
+}
+
+// textCell экранирует текст, который табличный редактор принял бы за формулу
+// (CSV injection): такие значения предваряются апострофом.
+func textCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+type csvExport struct {
+	w *csv.Writer
+}
+
+// newCSVExport пишет CSV с меткой порядка байтов, чтобы Excel распознал UTF-8.
+func newCSVExport(w io.Writer) (*csvExport, error) {
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return nil, err
+	}
+	e := &csvExport{w: csv.NewWriter(w)}
+	return e, e.w.Write(exportColumns)
+}
+
+func (e *csvExport) WriteRow(row ExportRow) error {
+	return e.w.Write(row.cells())
+}
+
+func (e *csvExport) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonExport struct {
+	w     io.Writer
+	count int
+}
+
+// newJSONExport пишет массив по одному элементу, не собирая его в памяти.
+func newJSONExport(w io.Writer) (*jsonExport, error) {
+	_, err := io.WriteString(w, "[")
+	return &jsonExport{w: w}, err
+}
+
+func (e *jsonExport) WriteRow(row ExportRow) error {
+	data, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ",\n"); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonExport) Close() error {
+	_, err := fmt.Fprint(e.w, "]\n")
+	return err
+}
@@ -0,0 +1,22 @@
+package transactions
+
+import "testing"
+
+func TestTextCell(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", ""},
+		{"Пятёрочка", "Пятёрочка"},
+		{"=HYPERLINK(\"x\")", "'=HYPERLINK(\"x\")"},
+		{"+7 999", "'+7 999"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tcmd", "'\tcmd"},
+		{"\rcmd", "'\rcmd"},
+		{"a=b", "a=b"},
+	}
+	for _, tt := range tests {
+		if got := textCell(tt.in); got != tt.want {
+			t.Errorf("textCell(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
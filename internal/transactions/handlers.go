@@ -1,26 +1,42 @@
 package transactions
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/Anabol1ks/pers-fin-m/internal/budgets"
+	"github.com/Anabol1ks/pers-fin-m/internal/httputil"
 	"github.com/Anabol1ks/pers-fin-m/internal/models"
-	"github.com/Anabol1ks/pers-fin-m/internal/storage"
-	"github.com/Anabol1ks/pers-fin-m/internal/users"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/period"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
 	"github.com/gin-gonic/gin"
 )
 
+type Handler struct {
+	store repository.Store
+}
+
+func NewHandler(store repository.Store) *Handler {
+	return &Handler{store: store}
+}
+
 type TransactionInput struct {
-	Amount      float64   `json:"amount" binding:"required"`
-	BonusChange float64   `json:"bonusChange"`
-	Currency    string    `json:"currency"`
-	Date        time.Time `json:"date"`
-	Title       string    `json:"title" binding:"required"`
-	Description string    `json:"description"`
-	Category    uint      `json:"category"`
-	Type        string    `json:"type" binding:"required,oneof=income expense"`
-	BonusType   string    `json:"typeBonus"`
+	Amount      money.Amount `json:"amount" binding:"required" swaggertype:"number"`
+	BonusChange money.Amount `json:"bonusChange" swaggertype:"number"`
+	Currency    string       `json:"currency"`
+	Date        time.Time    `json:"date"`
+	Title       string       `json:"title" binding:"required"`
+	Description string       `json:"description"`
+	Category    uint         `json:"category"`
+	Account     uint         `json:"account"` // если не указан, используется основной счёт
+	Type        string       `json:"type" binding:"required,oneof=income expense"`
+	BonusType   string       `json:"typeBonus"`
 }
 
 // @Security BearerAuth
@@ -35,7 +51,7 @@ type TransactionInput struct {
 // @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
 // @Failure 500 {object} response.ErrorResponse "Ошибка создания транзакции"
 // @Router /transactions [post]
-func CreateTransaction(c *gin.Context) {
+func (h *Handler) CreateTransaction(c *gin.Context) {
 	userID := c.GetUint("userID")
 
 	var input TransactionInput
@@ -45,31 +61,55 @@ func CreateTransaction(c *gin.Context) {
 		return
 	}
 
-	if input.Amount <= 0 {
-		log.Println("Сумма должна быть больше 0")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Сумма должна быть больше 0"})
+	transaction, errMsg := h.buildTransaction(userID, input)
+	if errMsg != "" {
+		log.Println(errMsg)
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
 		return
 	}
 
-	if input.BonusChange == 0 && input.BonusType != "" {
-		log.Println("При нулевом бонусе тип бонуса должен быть пустым")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "При нулевом бонусе тип бонуса должен быть пустым"})
+	err := h.store.Atomic(func(s repository.Store) error {
+		if err := s.Transactions().Create(transaction); err != nil {
+			errMsg = "Ошибка создания транзакции"
+			return err
+		}
+		if err := ApplyEffect(s, transaction, 1); err != nil {
+			errMsg = "Не удалось обновить баланс"
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errMsg})
 		return
 	}
 
+	budgets.Notify(h.store, userID, *transaction)
+	c.JSON(http.StatusCreated, transaction)
+}
+
+// buildTransaction проверяет ввод и собирает из него транзакцию.
+// Вторым значением возвращается текст ошибки.
+func (h *Handler) buildTransaction(userID uint, input TransactionInput) (*models.Transaction, string) {
+	if input.Amount <= 0 {
+		return nil, "Сумма должна быть больше 0"
+	}
+
+	if input.BonusChange == 0 && input.BonusType != "" {
+		return nil, "При нулевом бонусе тип бонуса должен быть пустым"
+	}
+
 	if input.BonusChange != 0 && input.BonusType == "" {
-		log.Println("При ненулевом бонусе тип бонуса должен быть указан")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "При ненулевом бонусе тип бонуса должен быть указан"})
-		return
+		return nil, "При ненулевом бонусе тип бонуса должен быть указан"
 	}
 
-	var category models.Category
-	if err := storage.DB.
-		Where("id = ? AND (user_id IS NULL OR user_id = ?)", input.Category, userID).
-		First(&category).Error; err != nil {
-		log.Println("Указана неверная категория")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Указана неверная категория"})
-		return
+	if _, err := h.store.Categories().GetAvailable(input.Category, userID); err != nil {
+		return nil, "Указана неверная категория"
+	}
+
+	account, errMsg := UserAccount(h.store, userID, input.Account)
+	if errMsg != "" {
+		return nil, errMsg
 	}
 
 	if input.Date.IsZero() {
@@ -77,12 +117,15 @@ func CreateTransaction(c *gin.Context) {
 	}
 
 	if input.Currency == "" {
-		input.Currency = "RUB"
+		input.Currency = account.Currency
+	}
+	if input.Currency != account.Currency {
+		return nil, "Валюта транзакции должна совпадать с валютой счёта"
 	}
 
-	// Создание транзакции в базе данных
-	transaction := models.Transaction{
+	return &models.Transaction{
 		UserID:      userID,
+		AccountID:   account.ID,
 		Amount:      input.Amount,
 		BonusChange: input.BonusChange,
 		BonusType:   models.TransactionType(input.BonusType),
@@ -92,81 +135,54 @@ func CreateTransaction(c *gin.Context) {
 		Description: input.Description,
 		Category:    input.Category,
 		Type:        models.TransactionType(input.Type),
-	}
+	}, ""
+}
 
-	tx := storage.DB.Begin()
-	if err := tx.Create(&transaction).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка создания транзакции"})
-		return
+// UserAccount возвращает счёт для операции: указанный пользователем
+// или основной, если id равен 0. Архивный счёт не подходит.
+// Вторым значением возвращается текст ошибки.
+func UserAccount(store repository.Store, userID, accountID uint) (*models.Account, string) {
+	var account *models.Account
+	var err error
+	if accountID == 0 {
+		account, err = store.Accounts().Primary(userID)
+	} else {
+		account, err = store.Accounts().GetOwned(accountID, userID)
 	}
-
-	// Получаем пользователя
-	var user users.User
-	if err := tx.First(&user, userID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
-		return
+	if err != nil {
+		return nil, "Указан неверный счёт"
 	}
-
-	// Обновляем баланс
-	if transaction.Type == models.Income {
-		user.Balance += float64(transaction.Amount)
-	} else if transaction.Type == models.Expense {
-		user.Balance -= float64(transaction.Amount)
+	if account.Archived {
+		return nil, "Счёт находится в архиве"
 	}
+	return account, ""
+}
 
-	if transaction.BonusChange != 0 {
-		if transaction.BonusType == models.Income {
-			user.Bonus += transaction.BonusChange
-		} else if transaction.BonusType == models.Expense {
-			user.Bonus -= transaction.BonusChange
-		}
+// ApplyEffect применяет (sign = 1) или отменяет (sign = -1) влияние транзакции
+// на балансы счетов и бонусы пользователя.
+func ApplyEffect(s repository.Store, t *models.Transaction, sign money.Amount) error {
+	if err := s.Accounts().AdjustBalance(t.AccountID, sign*t.BalanceEffect()); err != nil {
+		return err
 	}
-
-	if err := tx.Save(&user).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось обновить баланс"})
-		return
+	if t.CounterAccountID != nil {
+		if err := s.Accounts().AdjustBalance(*t.CounterAccountID, sign*t.CounterEffect()); err != nil {
+			return err
+		}
 	}
-
-	tx.Commit()
-	c.JSON(http.StatusCreated, transaction)
+	return s.Users().AdjustBonus(t.UserID, sign*t.BonusEffect())
 }
 
-// // @Security BearerAuth
-// // GetAllTransactions godoc
-// // @Summary Получить все транзакции пользователя
-// // @Description Получить все транзакции пользователя
-// // @Tags Transactions
-// // @Produce json
-// // @Success 200 {array} TransactionInput "Список транзакций"
-// // @Failure 500 {object} response.ErrorResponse "Ошибка при получении транзакций"
-// // @Router /transactions [get]
-// func GetAllTransactions(c *gin.Context) {
-// 	userID := c.GetUint("userID")
-
-// 	var transactions []models.Transaction
-// 	if err := storage.DB.Where("user_id = ?", userID).Find(&transactions).Error; err != nil {
-// 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении транзакций"})
-// 		return
-// 	}
-
-// 	c.JSON(http.StatusOK, transactions)
-// }
-
-// ВОЗМОЖНО ЭТО НЕ НУЖНО
-
 type TransactionUpdate struct {
-	Amount      *float64   `json:"amount"`
-	BonusChange *float64   `json:"bonusChange"`
-	Currency    *string    `json:"currency"`
-	Date        *time.Time `json:"date"`
-	Title       *string    `json:"title"`
-	Description *string    `json:"description"`
-	Category    *uint      `json:"category"`
-	Type        *string    `json:"type" binding:"omitempty,oneof=income expense"`
-	BonusType   *string    `json:"typeBonus"`
+	Amount      *money.Amount `json:"amount" swaggertype:"number"`
+	BonusChange *money.Amount `json:"bonusChange" swaggertype:"number"`
+	Currency    *string       `json:"currency"`
+	Date        *time.Time    `json:"date"`
+	Title       *string       `json:"title"`
+	Description *string       `json:"description"`
+	Category    *uint         `json:"category"`
+	Account     *uint         `json:"account"`
+	Type        *string       `json:"type" binding:"omitempty,oneof=income expense"`
+	BonusType   *string       `json:"typeBonus"`
 }
 
 // @Security BearerAuth
@@ -183,9 +199,13 @@ type TransactionUpdate struct {
 // @Failure 404 {object} response.ErrorResponse "Транзакция не найдена"
 // @Failure 500 {object} response.ErrorResponse "Ошибка обновления транзакции"
 // @Router /transactions/{id} [put]
-func UpdateTransaction(c *gin.Context) {
+func (h *Handler) UpdateTransaction(c *gin.Context) {
 	userID := c.GetUint("userID")
-	transactionID := c.Param("id")
+	transactionID, err := httputil.ParamID(c, "id")
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Транзакция не найдена"})
+		return
+	}
 
 	var input TransactionUpdate
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -194,17 +214,18 @@ func UpdateTransaction(c *gin.Context) {
 		return
 	}
 
-	var transaction models.Transaction
-	if err := storage.DB.Where("id = ? AND user_id = ?", transactionID, userID).First(&transaction).Error; err != nil {
+	transaction, err := h.store.Transactions().GetOwned(transactionID, userID)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Транзакция не найдена"})
 		return
 	}
 
-	oldAmount := transaction.Amount
-	oldType := transaction.Type
+	if transaction.Type == models.Transfer {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Для изменения перевода используйте PUT /transfers/{id}"})
+		return
+	}
 
-	oldBonusChange := transaction.BonusChange
-	oldBonusType := transaction.BonusType
+	old := *transaction
 
 	// Обновление суммы
 	if input.Amount != nil {
@@ -224,7 +245,7 @@ func UpdateTransaction(c *gin.Context) {
 			transaction.BonusType = models.TransactionType(*input.BonusType)
 		}
 
-		if *input.BonusChange == 0 {
+		if input.BonusChange != nil && *input.BonusChange == 0 {
 			transaction.BonusType = ""
 		}
 
@@ -245,10 +266,30 @@ func UpdateTransaction(c *gin.Context) {
 		}
 	}
 
+	// Обновление счёта: валюта по умолчанию берётся из нового счёта
+	if input.Account != nil && *input.Account != transaction.AccountID {
+		account, errMsg := UserAccount(h.store, userID, *input.Account)
+		if errMsg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+			return
+		}
+		transaction.AccountID = account.ID
+		transaction.Currency = account.Currency
+	}
+
 	// Обновление валюты
 	if input.Currency != nil {
 		transaction.Currency = *input.Currency
 	}
+	account, err := h.store.Accounts().GetOwned(transaction.AccountID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Счёт транзакции не найден"})
+		return
+	}
+	if transaction.Currency != account.Currency {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Валюта транзакции должна совпадать с валютой счёта"})
+		return
+	}
 
 	// Обновление даты
 	if input.Date != nil {
@@ -267,10 +308,7 @@ func UpdateTransaction(c *gin.Context) {
 
 	// Обновление категории
 	if input.Category != nil {
-		var category models.Category
-		if err := storage.DB.
-			Where("id = ? AND (user_id IS NULL OR user_id = ?)", *input.Category, userID).
-			First(&category).Error; err != nil {
+		if _, err := h.store.Categories().GetAvailable(*input.Category, userID); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Указана неверная категория"})
 			return
 		}
@@ -282,50 +320,30 @@ func UpdateTransaction(c *gin.Context) {
 		transaction.Type = models.TransactionType(*input.Type)
 	}
 
-	tx := storage.DB.Begin()
-
-	if err := tx.Save(&transaction).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка обновления транзакции"})
-		return
-	}
-
-	// Получаем пользователя
-	var user users.User
-	if err := tx.First(&user, userID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-
-	// Рассчитываем разницу
-	// Предположим, что обновление типа транзакции не меняется, или если меняется – надо логически обработать
-	var delta float64
-	if oldType == models.Income {
-		delta = float64(transaction.Amount) - float64(oldAmount)
-	} else if oldType == models.Expense {
-		// Для расхода: если новая сумма больше, то баланс должен уменьшиться еще больше
-		delta = float64(oldAmount) - float64(transaction.Amount)
-	}
-
-	// Обновляем баланс
-	user.Balance += delta
-
-	var bonusDelta float64
-	if oldBonusType == models.Income {
-		bonusDelta = float64(transaction.BonusChange) - float64(oldBonusChange)
-	} else if oldBonusType == models.Expense {
-		bonusDelta = float64(oldBonusChange) - float64(transaction.BonusChange)
-	}
-	user.Bonus += bonusDelta
-
-	if err := tx.Save(&user).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось обновить баланс"})
+	// Отменяем влияние старой версии транзакции и применяем новую,
+	// поэтому смена типа, суммы или счёта корректно отражается на балансах
+	var errMsg string
+	err = h.store.Atomic(func(s repository.Store) error {
+		if err := s.Transactions().Save(transaction); err != nil {
+			errMsg = "Ошибка обновления транзакции"
+			return err
+		}
+		if err := ApplyEffect(s, &old, -1); err != nil {
+			errMsg = "Не удалось обновить баланс"
+			return err
+		}
+		if err := ApplyEffect(s, transaction, 1); err != nil {
+			errMsg = "Не удалось обновить баланс"
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errMsg})
 		return
 	}
 
-	tx.Commit()
+	budgets.Notify(h.store, userID, *transaction)
 	c.JSON(http.StatusOK, transaction)
 }
 
@@ -340,89 +358,101 @@ func UpdateTransaction(c *gin.Context) {
 // @Failure 404 {object} response.ErrorResponse "Транзакция не найдена"
 // @Failure 500 {object} response.ErrorResponse "Ошибка при удалении транзакции"
 // @Router /transactions/{id} [delete]
-func DelTransactions(c *gin.Context) {
+func (h *Handler) DelTransactions(c *gin.Context) {
 	userID := c.GetUint("userID")
-	transactionID := c.Param("id")
-
-	var transaction models.Transaction
-	if err := storage.DB.Where("id = ? AND user_id = ?", transactionID, userID).First(&transaction).Error; err != nil {
+	transactionID, err := httputil.ParamID(c, "id")
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Транзакция не найдена"})
 		return
 	}
 
-	tx := storage.DB.Begin()
-
-	// Получаем пользователя
-	var user users.User
-	if err := tx.First(&user, userID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
+	transaction, err := h.store.Transactions().GetOwned(transactionID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Транзакция не найдена"})
 		return
 	}
 
-	// Обновляем баланс — отменяем влияние транзакции
-	if transaction.Type == models.Income {
-		// Если доход, то удаление означает уменьшение баланса
-		user.Balance -= float64(transaction.Amount)
-	} else if transaction.Type == models.Expense {
-		// Если расход, то удаление означает увеличение баланса
-		user.Balance += float64(transaction.Amount)
-	}
-
-	if transaction.BonusChange != 0 {
-		if transaction.BonusType == models.Income {
-			user.Bonus -= transaction.BonusChange
-		} else if transaction.BonusType == models.Expense {
-			user.Bonus += transaction.BonusChange
+	var errMsg string
+	err = h.store.Atomic(func(s repository.Store) error {
+		// Обновляем баланс — отменяем влияние транзакции
+		if err := ApplyEffect(s, transaction, -1); err != nil {
+			errMsg = "Не удалось обновить баланс"
+			return err
 		}
-	}
-
-	if err := tx.Save(&user).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось обновить баланс"})
-		return
-	}
-
-	if err := tx.Delete(&transaction).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении транзакции"})
+		if err := s.Transactions().Delete(transaction); err != nil {
+			errMsg = "Ошибка при удалении транзакции"
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errMsg})
 		return
 	}
 
-	tx.Commit()
 	c.JSON(http.StatusOK, gin.H{"message": "Транзакция удалена"})
 }
 
 // TransactionSearchInput определяет фильтры поиска транзакций.
-// Все поля опциональные.
+// Все поля опциональные. Списочные параметры можно повторять
+// (category=1&category=2) или перечислять через запятую (category=1,2).
 type TransactionSearchInput struct {
-	Title       *string    `form:"title"`
-	Description *string    `form:"description"`
-	Amount      *float64   `form:"amount"`
-	BonusChange *float64   `form:"bonusChange"`
-	Date        *time.Time `form:"date"`
-	Category    *uint      `form:"category"`
-	Type        *string    `form:"type"`
-	BonusType   *string    `form:"typeBonus"`
+	Query       string        `form:"q"`
+	Title       *string       `form:"title"`
+	Description *string       `form:"description"`
+	Amount      *money.Amount `form:"amount"`
+	AmountMin   *money.Amount `form:"amountMin"`
+	AmountMax   *money.Amount `form:"amountMax"`
+	BonusChange *money.Amount `form:"bonusChange"`
+	Date        *time.Time    `form:"date"`
+	DateFrom    *time.Time    `form:"dateFrom" time_format:"2006-01-02"`
+	DateTo      *time.Time    `form:"dateTo" time_format:"2006-01-02"` // включительно
+	Period      string        `form:"period"`
+	Category    []string      `form:"category"`
+	Account     []string      `form:"account"`
+	Currency    []string      `form:"currency"`
+	Type        []string      `form:"type"`
+	BonusType   *string       `form:"typeBonus"`
+
+	ExcludeCategory []string `form:"excludeCategory"`
+	ExcludeAccount  []string `form:"excludeAccount"`
+	ExcludeCurrency []string `form:"excludeCurrency"`
+	ExcludeType     []string `form:"excludeType"`
 }
 
 // SearchTransactions godoc
 // @Security BearerAuth
 // @Summary Поиск транзакций
-// @Description Ищет транзакции пользователя по различным опциональным параметрам
+// @Description Ищет транзакции пользователя по различным опциональным параметрам. Все условия объединяются через И,
+// @Description значения одного списочного параметра — через ИЛИ. Период, дата и диапазон дат пересекаются.
+// @Description С параметром q результаты сортируются по релевантности, а titleHighlight и descriptionHighlight
+// @Description содержат HTML-фрагменты с найденными словами в теге <mark>. Остальной текст фрагментов экранирован, title и description возвращаются как есть
 // @Tags Transactions
 // @Produce json
+// @Param q query string false "Полнотекстовый поиск по названию и описанию: слова, «фразы» в кавычках, -исключения"
 // @Param title query string false "Название транзакции (частичное совпадение)"
-// @Param amount query int false "Приблизительная сумма транзакции"
+// @Param amount query number false "Приблизительная сумма транзакции (±10%)"
+// @Param amountMin query number false "Минимальная сумма"
+// @Param amountMax query number false "Максимальная сумма"
 // @Param bonusChange query number false "Приблизительное количество бонусов"
 // @Param date query string false "Дата транзакции (формат YYYY-MM-DD)"
-// @Param category query int false "ID категории"
-// @Param type query string false "Тип транзакции (income или expense)"
+// @Param dateFrom query string false "Начало периода включительно (YYYY-MM-DD)"
+// @Param dateTo query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Param period query string false "Относительный период" Enums(today, yesterday, this_week, last_week, this_month, last_month, this_year, last_year, last_7_days, last_30_days)
+// @Param category query []int false "ID категорий" collectionFormat(multi)
+// @Param account query []int false "ID счетов" collectionFormat(multi)
+// @Param currency query []string false "Валюты" collectionFormat(multi)
+// @Param type query []string false "Типы транзакций (income, expense, transfer)" collectionFormat(multi)
 // @Param typeBonus query string false "Тип бонуса"
-// @Success 200 {array} TransactionInput "Найденные транзакции"
+// @Param excludeCategory query []int false "Исключить категории" collectionFormat(multi)
+// @Param excludeAccount query []int false "Исключить счета" collectionFormat(multi)
+// @Param excludeCurrency query []string false "Исключить валюты" collectionFormat(multi)
+// @Param excludeType query []string false "Исключить типы транзакций" collectionFormat(multi)
+// @Success 200 {array} repository.SearchHit "Найденные транзакции"
+// @Failure 400 {object} response.ErrorResponse "Неверные параметры запроса"
 // @Failure 500 {object} response.ErrorResponse "Ошибка при поиске транзакций"
 // @Router /transactions/search [get]
-func SearchTransactions(c *gin.Context) {
+func (h *Handler) SearchTransactions(c *gin.Context) {
 	userID := c.GetUint("userID")
 
 	var input TransactionSearchInput
@@ -432,73 +462,152 @@ func SearchTransactions(c *gin.Context) {
 		return
 	}
 
-	// Начинаем строить запрос с обязательным условием по пользователю
-	query := storage.DB.Where("user_id = ?", userID)
+	filter, err := searchFilter(input, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	// Фильтрация по названию (частичное совпадение)
-	if input.Title != nil && *input.Title != "" {
-		// Для PostgreSQL можно использовать ILIKE для регистронезависимого поиска
-		query = query.Where("title ILIKE ?", "%"+*input.Title+"%")
+	transactions, err := h.store.Transactions().Search(userID, filter)
+	if err != nil {
+		log.Println("Ошибка при поиске транзакций:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при поиске транзакций"})
+		return
 	}
 
-	// Фильтрация по описанию (частичное совпадение)
-	if input.Description != nil && *input.Description != "" {
-		query = query.Where("description ILIKE ?", "%"+*input.Description+"%")
+	c.JSON(http.StatusOK, transactions)
+}
+
+// searchFilter переводит параметры поиска в фильтр репозитория.
+// now нужен для относительных периодов.
+func searchFilter(input TransactionSearchInput, now time.Time) (repository.TransactionFilter, error) {
+	filter := repository.TransactionFilter{
+		Query:       strings.TrimSpace(input.Query),
+		Title:       input.Title,
+		Description: input.Description,
+		AmountMin:   input.AmountMin,
+		AmountMax:   input.AmountMax,
+		Currencies:  upperList(input.Currency),
+		Types:       splitList(input.Type),
+		BonusType:   input.BonusType,
+
+		ExcludeCurrencies: upperList(input.ExcludeCurrency),
+		ExcludeTypes:      splitList(input.ExcludeType),
+	}
+
+	var err error
+	if filter.Categories, err = splitIDs(input.Category, "category"); err != nil {
+		return filter, err
+	}
+	if filter.Accounts, err = splitIDs(input.Account, "account"); err != nil {
+		return filter, err
+	}
+	if filter.ExcludeCategories, err = splitIDs(input.ExcludeCategory, "excludeCategory"); err != nil {
+		return filter, err
+	}
+	if filter.ExcludeAccounts, err = splitIDs(input.ExcludeAccount, "excludeAccount"); err != nil {
+		return filter, err
 	}
 
 	// Фильтрация по сумме с допуском ±10%
 	if input.Amount != nil {
-		tol := (float64(*input.Amount) * 0.1)
-		if tol < 1 {
-			tol = 1
+		tol := input.Amount.Div(10).Abs()
+		if tol < money.FromUnits(1) {
+			tol = money.FromUnits(1)
 		}
-		min := *input.Amount - tol
-		max := *input.Amount + tol
-		query = query.Where("amount BETWEEN ? AND ?", min, max)
+		narrowAmount(&filter, *input.Amount-tol, *input.Amount+tol)
+	}
+	if filter.AmountMin != nil && filter.AmountMax != nil && *filter.AmountMin > *filter.AmountMax {
+		return filter, errors.New("Минимальная сумма больше максимальной")
 	}
 
 	// Фильтрация по бонусам с допуском ±10%
 	if input.BonusChange != nil {
-		tol := *input.BonusChange * 0.1
-		if tol < 0.1 {
-			tol = 0.1
+		tol := input.BonusChange.Div(10).Abs()
+		if tol < money.MustParse("0.1") {
+			tol = money.MustParse("0.1")
 		}
 		min := *input.BonusChange - tol
 		max := *input.BonusChange + tol
-		query = query.Where("bonus_change BETWEEN ? AND ?", min, max)
+		filter.BonusMin, filter.BonusMax = &min, &max
 	}
 
 	// Фильтрация по дате (ищем транзакции в пределах указанного дня)
 	if input.Date != nil {
-		// Определяем начало и конец дня
-		year, month, day := input.Date.Date()
-		loc := input.Date.Location()
-		start := time.Date(year, month, day, 0, 0, 0, 0, loc)
-		end := start.Add(24 * time.Hour)
-		query = query.Where("date >= ? AND date < ?", start, end)
+		start := period.StartOfDay(*input.Date)
+		narrowDates(&filter, start, start.AddDate(0, 0, 1))
+	}
+	if input.Period != "" {
+		from, to, err := period.Range(input.Period, now)
+		if err != nil {
+			return filter, err
+		}
+		narrowDates(&filter, from, to)
+	}
+	if input.DateFrom != nil {
+		narrowDates(&filter, *input.DateFrom, time.Time{})
+	}
+	if input.DateTo != nil {
+		narrowDates(&filter, time.Time{}, input.DateTo.AddDate(0, 0, 1))
+	}
+	if filter.DateFrom != nil && filter.DateTo != nil && !filter.DateFrom.Before(*filter.DateTo) {
+		return filter, errors.New("Начало периода должно быть раньше конца")
 	}
 
-	// Фильтрация по категории
-	if input.Category != nil {
-		query = query.Where("category = ?", *input.Category)
+	return filter, nil
+}
+
+// narrowAmount сужает диапазон сумм фильтра до пересечения с [min, max].
+func narrowAmount(f *repository.TransactionFilter, min, max money.Amount) {
+	if f.AmountMin == nil || *f.AmountMin < min {
+		f.AmountMin = &min
+	}
+	if f.AmountMax == nil || *f.AmountMax > max {
+		f.AmountMax = &max
 	}
+}
 
-	// Фильтрация по типу транзакции
-	if input.Type != nil && *input.Type != "" {
-		query = query.Where("type = ?", *input.Type)
+// narrowDates сужает период фильтра до пересечения с [from, to).
+// Нулевая граница не ограничивает период.
+func narrowDates(f *repository.TransactionFilter, from, to time.Time) {
+	if !from.IsZero() && (f.DateFrom == nil || f.DateFrom.Before(from)) {
+		f.DateFrom = &from
+	}
+	if !to.IsZero() && (f.DateTo == nil || f.DateTo.After(to)) {
+		f.DateTo = &to
 	}
+}
 
-	// Фильтрация по типу бонуса
-	if input.BonusType != nil && *input.BonusType != "" {
-		query = query.Where("bonus_type = ?", *input.BonusType)
+// splitList разбирает повторяющийся параметр, значения которого могут быть
+// перечислены через запятую.
+func splitList(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
 	}
+	return result
+}
 
-	var transactions []models.Transaction
-	if err := query.Order("date DESC").Find(&transactions).Error; err != nil {
-		log.Println("Ошибка при поиске транзакций:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при поиске транзакций"})
-		return
+func upperList(values []string) []string {
+	result := splitList(values)
+	for i := range result {
+		result[i] = strings.ToUpper(result[i])
 	}
+	return result
+}
 
-	c.JSON(http.StatusOK, transactions)
+func splitIDs(values []string, param string) ([]uint, error) {
+	var ids []uint
+	for _, item := range splitList(values) {
+		id, err := strconv.ParseUint(item, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Неверный ID в параметре %s: %s", param, item)
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
 }
@@ -0,0 +1,384 @@
+package transactions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository/memory"
+	"github.com/gin-gonic/gin"
+)
+
+// testServer — маршруты транзакций поверх хранилища в памяти. Пользователь
+// запроса берётся из заголовка X-User-ID вместо токена.
+type testServer struct {
+	t      *testing.T
+	store  *memory.Store
+	router *gin.Engine
+
+	card, cash, usd, foreign uint // счета: три у пользователя 1, один у пользователя 2
+	category, private        uint // «Без категории» и категория пользователя 2
+}
+
+func newTestServer(t *testing.T) *testServer {
+	gin.SetMode(gin.TestMode)
+	s := &testServer{t: t, store: memory.NewStore()}
+
+	for _, user := range []*models.User{{Username: "first", Email: "first@example.com"}, {Username: "second", Email: "second@example.com"}} {
+		if err := s.store.Users().Create(user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, account := range []struct {
+		id       *uint
+		userID   uint
+		name     string
+		currency string
+	}{
+		{&s.card, 1, "Основной счёт", "RUB"},
+		{&s.cash, 1, "Наличные", "RUB"},
+		{&s.usd, 1, "Доллары", "USD"},
+		{&s.foreign, 2, "Основной счёт", "RUB"},
+	} {
+		a := models.Account{UserID: account.userID, Name: account.name, Type: models.AccountCard, Currency: account.currency}
+		if err := s.store.Accounts().Create(&a); err != nil {
+			t.Fatal(err)
+		}
+		*account.id = a.ID
+	}
+
+	slug := models.UncategorizedSlug
+	owner := uint(2)
+	for _, category := range []struct {
+		id       *uint
+		category models.Category
+	}{
+		{&s.category, models.Category{Name: "Без категории", IsDefault: true, Slug: &slug}},
+		{&s.private, models.Category{Name: "Личное", UserID: &owner}},
+	} {
+		if err := s.store.Categories().Create(&category.category); err != nil {
+			t.Fatal(err)
+		}
+		*category.id = category.category.ID
+	}
+
+	h := NewHandler(s.store)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		id, _ := strconv.Atoi(c.GetHeader("X-User-ID"))
+		c.Set("userID", uint(id))
+	})
+	r.GET("/transactions", h.ListTransactions)
+	r.POST("/transactions", h.CreateTransaction)
+	r.GET("/transactions/search", h.SearchTransactions)
+	r.PUT("/transactions/:id", h.UpdateTransaction)
+	r.DELETE("/transactions/:id", h.DelTransactions)
+	r.POST("/transfers", h.CreateTransfer)
+	r.PUT("/transfers/:id", h.UpdateTransfer)
+	s.router = r
+	return s
+}
+
+// call выполняет запрос от имени userID и разбирает ответ в out, если он задан.
+func (s *testServer) call(userID uint, method, path string, body, out any) *httptest.ResponseRecorder {
+	s.t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			s.t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", strconv.Itoa(int(userID)))
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	if out != nil && w.Code < 300 {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			s.t.Fatalf("%s %s: %v: %s", method, path, err, w.Body)
+		}
+	}
+	return w
+}
+
+// create создаёт транзакцию пользователя 1 и возвращает её ID.
+func (s *testServer) create(input gin.H) uint {
+	s.t.Helper()
+	s.defaultCategory(input)
+	var transaction models.Transaction
+	if w := s.call(1, "POST", "/transactions", input, &transaction); w.Code != http.StatusCreated {
+		s.t.Fatalf("create %v: status %d: %s", input, w.Code, w.Body)
+	}
+	return transaction.ID
+}
+
+// defaultCategory подставляет «Без категории», если категория не указана.
+func (s *testServer) defaultCategory(input gin.H) {
+	if _, ok := input["category"]; !ok {
+		input["category"] = s.category
+	}
+}
+
+// balances возвращает балансы счетов пользователя 1 и его бонусы.
+func (s *testServer) balances() [4]money.Amount {
+	s.t.Helper()
+	var result [4]money.Amount
+	for i, id := range []uint{s.card, s.cash, s.usd} {
+		account, err := s.store.Accounts().GetOwned(id, 1)
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		result[i] = account.Balance
+	}
+	user, err := s.store.Users().GetByID(1)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	result[3] = user.Bonus
+	return result
+}
+
+func TestCreateTransaction(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name  string
+		input gin.H
+		want  int
+	}{
+		{"расход с основного счёта", gin.H{"amount": "100,50", "title": "Кофе", "type": "expense"}, http.StatusCreated},
+		{"доход на другой счёт", gin.H{"amount": 1000, "title": "Зарплата", "type": "income", "account": s.cash}, http.StatusCreated},
+		{"бонусы", gin.H{"amount": 10, "title": "Кешбэк", "type": "expense", "account": s.cash, "bonusChange": 5, "typeBonus": "income"}, http.StatusCreated},
+		{"валютный счёт", gin.H{"amount": 3, "title": "Кофе", "type": "expense", "account": s.usd, "currency": "USD"}, http.StatusCreated},
+		{"нулевая сумма", gin.H{"amount": 0, "title": "Ноль", "type": "expense"}, http.StatusBadRequest},
+		{"отрицательная сумма", gin.H{"amount": -5, "title": "Минус", "type": "expense"}, http.StatusBadRequest},
+		{"без названия", gin.H{"amount": 5, "type": "expense"}, http.StatusBadRequest},
+		{"перевод через транзакции", gin.H{"amount": 5, "title": "Перевод", "type": "transfer"}, http.StatusBadRequest},
+		{"чужая категория", gin.H{"amount": 5, "title": "Кофе", "type": "expense", "category": s.private}, http.StatusBadRequest},
+		{"чужой счёт", gin.H{"amount": 5, "title": "Кофе", "type": "expense", "account": s.foreign}, http.StatusBadRequest},
+		{"валюта не совпадает со счётом", gin.H{"amount": 5, "title": "Кофе", "type": "expense", "account": s.usd, "currency": "RUB"}, http.StatusBadRequest},
+		{"тип бонуса без бонуса", gin.H{"amount": 5, "title": "Кофе", "type": "expense", "typeBonus": "income"}, http.StatusBadRequest},
+		{"бонус без типа", gin.H{"amount": 5, "title": "Кофе", "type": "expense", "bonusChange": 5}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		s.defaultCategory(tt.input)
+		if w := s.call(1, "POST", "/transactions", tt.input, nil); w.Code != tt.want {
+			t.Errorf("%s: status %d, want %d: %s", tt.name, w.Code, tt.want, w.Body)
+		}
+	}
+
+	want := [4]money.Amount{-10050, 99000, -300, 500}
+	if got := s.balances(); got != want {
+		t.Errorf("балансы = %v, want %v", got, want)
+	}
+}
+
+func TestUpdateTransaction(t *testing.T) {
+	s := newTestServer(t)
+	id := s.create(gin.H{"amount": 100, "title": "Кофе", "type": "expense"})
+	path := fmt.Sprintf("/transactions/%d", id)
+
+	steps := []struct {
+		name   string
+		userID uint
+		input  gin.H
+		want   int
+		// Балансы карты, наличных, долларового счёта и бонусы после шага
+		balances [4]money.Amount
+	}{
+		{"сумма и тип", 1, gin.H{"amount": 250, "type": "income"}, http.StatusOK, [4]money.Amount{25000, 0, 0, 0}},
+		{"другой счёт", 1, gin.H{"account": s.cash}, http.StatusOK, [4]money.Amount{0, 25000, 0, 0}},
+		{"бонусы", 1, gin.H{"bonusChange": 3, "typeBonus": "expense"}, http.StatusOK, [4]money.Amount{0, 25000, 0, -300}},
+		{"сброс бонусов", 1, gin.H{"bonusChange": 0}, http.StatusOK, [4]money.Amount{0, 25000, 0, 0}},
+		{"валютный счёт без смены валюты", 1, gin.H{"account": s.usd, "currency": "RUB"}, http.StatusBadRequest, [4]money.Amount{0, 25000, 0, 0}},
+		{"валютный счёт", 1, gin.H{"account": s.usd}, http.StatusOK, [4]money.Amount{0, 0, 25000, 0}},
+		{"нулевая сумма", 1, gin.H{"amount": 0}, http.StatusBadRequest, [4]money.Amount{0, 0, 25000, 0}},
+		{"неверный тип", 1, gin.H{"type": "transfer"}, http.StatusBadRequest, [4]money.Amount{0, 0, 25000, 0}},
+		{"чужая категория", 1, gin.H{"category": s.private}, http.StatusBadRequest, [4]money.Amount{0, 0, 25000, 0}},
+		{"чужой счёт", 1, gin.H{"account": s.foreign}, http.StatusBadRequest, [4]money.Amount{0, 0, 25000, 0}},
+		{"чужая транзакция", 2, gin.H{"amount": 1}, http.StatusNotFound, [4]money.Amount{0, 0, 25000, 0}},
+	}
+	for _, step := range steps {
+		if w := s.call(step.userID, "PUT", path, step.input, nil); w.Code != step.want {
+			t.Errorf("%s: status %d, want %d: %s", step.name, w.Code, step.want, w.Body)
+		}
+		if got := s.balances(); got != step.balances {
+			t.Errorf("%s: балансы = %v, want %v", step.name, got, step.balances)
+		}
+	}
+
+	if w := s.call(2, "DELETE", path, nil, nil); w.Code != http.StatusNotFound {
+		t.Errorf("удаление чужой транзакции: status %d, want 404", w.Code)
+	}
+	if w := s.call(1, "DELETE", path, nil, nil); w.Code != http.StatusOK {
+		t.Errorf("удаление: status %d, want 200", w.Code)
+	}
+	if got := s.balances(); got != [4]money.Amount{} {
+		t.Errorf("балансы после удаления = %v, want нули", got)
+	}
+	if w := s.call(1, "DELETE", path, nil, nil); w.Code != http.StatusNotFound {
+		t.Errorf("повторное удаление: status %d, want 404", w.Code)
+	}
+}
+
+func TestTransfers(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name  string
+		input gin.H
+		want  int
+	}{
+		{"между рублёвыми счетами с комиссией", gin.H{"fromAccount": s.card, "toAccount": s.cash, "amount": 1000, "fee": 10}, http.StatusCreated},
+		{"по курсу", gin.H{"fromAccount": s.card, "toAccount": s.usd, "amount": 1000, "rate": "0.011"}, http.StatusCreated},
+		{"с суммой зачисления", gin.H{"fromAccount": s.usd, "toAccount": s.cash, "amount": 1, "counterAmount": 90}, http.StatusCreated},
+		{"без курса между валютами", gin.H{"fromAccount": s.card, "toAccount": s.usd, "amount": 1000}, http.StatusBadRequest},
+		{"на тот же счёт", gin.H{"fromAccount": s.card, "toAccount": s.card, "amount": 1000}, http.StatusBadRequest},
+		{"отрицательная комиссия", gin.H{"fromAccount": s.card, "toAccount": s.cash, "amount": 1000, "fee": -1}, http.StatusBadRequest},
+		{"на чужой счёт", gin.H{"fromAccount": s.card, "toAccount": s.foreign, "amount": 1000}, http.StatusBadRequest},
+	}
+	var transfers []uint
+	for _, tt := range tests {
+		var transfer models.Transaction
+		w := s.call(1, "POST", "/transfers", tt.input, &transfer)
+		if w.Code != tt.want {
+			t.Errorf("%s: status %d, want %d: %s", tt.name, w.Code, tt.want, w.Body)
+		}
+		if w.Code == http.StatusCreated {
+			transfers = append(transfers, transfer.ID)
+		}
+	}
+	if want := [4]money.Amount{-201000, 109000, 1000, 0}; s.balances() != want {
+		t.Fatalf("балансы = %v, want %v", s.balances(), want)
+	}
+
+	// Перевод меняется только через /transfers, балансы пересчитываются целиком
+	path := fmt.Sprintf("/transactions/%d", transfers[0])
+	if w := s.call(1, "PUT", path, gin.H{"amount": 1}, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("изменение перевода через /transactions: status %d, want 400", w.Code)
+	}
+	path = fmt.Sprintf("/transfers/%d", transfers[0])
+	if w := s.call(1, "PUT", path, gin.H{"amount": 500, "fee": 0, "toAccount": s.usd, "rate": "0.01"}, nil); w.Code != http.StatusOK {
+		t.Errorf("изменение перевода: status %d: %s", w.Code, w.Body)
+	}
+	if want := [4]money.Amount{-150000, 9000, 1500, 0}; s.balances() != want {
+		t.Errorf("балансы после изменения = %v, want %v", s.balances(), want)
+	}
+	if w := s.call(2, "PUT", path, gin.H{"amount": 1}, nil); w.Code != http.StatusNotFound {
+		t.Errorf("изменение чужого перевода: status %d, want 404", w.Code)
+	}
+
+	// Удаление перевода возвращает деньги на оба счёта
+	for _, id := range transfers {
+		if w := s.call(1, "DELETE", fmt.Sprintf("/transactions/%d", id), nil, nil); w.Code != http.StatusOK {
+			t.Errorf("удаление перевода %d: status %d", id, w.Code)
+		}
+	}
+	if got := s.balances(); got != [4]money.Amount{} {
+		t.Errorf("балансы после удаления = %v, want нули", got)
+	}
+}
+
+func TestListTransactions(t *testing.T) {
+	s := newTestServer(t)
+	day := func(d int) time.Time {
+		return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC)
+	}
+	for d := 1; d <= 5; d++ {
+		kind := "expense"
+		if d%2 == 0 {
+			kind = "income"
+		}
+		s.create(gin.H{"amount": d * 100, "title": fmt.Sprintf("Операция %d", d), "type": kind, "date": day(d)})
+	}
+	if w := s.call(2, "POST", "/transactions", gin.H{"amount": 1, "title": "Чужая", "type": "expense", "category": s.category}, nil); w.Code != http.StatusCreated {
+		t.Fatalf("create: status %d", w.Code)
+	}
+
+	// Страницы по курсору от новых к старым
+	var titles []string
+	cursor := ""
+	for page := 0; page < 5; page++ {
+		var list TransactionList
+		w := s.call(1, "GET", "/transactions?limit=2&cursor="+cursor, nil, &list)
+		if w.Code != http.StatusOK {
+			t.Fatalf("list: status %d: %s", w.Code, w.Body)
+		}
+		if list.Total != 5 || w.Header().Get("X-Total-Count") != "5" {
+			t.Errorf("total = %d, X-Total-Count = %q, want 5", list.Total, w.Header().Get("X-Total-Count"))
+		}
+		for _, item := range list.Items {
+			titles = append(titles, item.Title)
+		}
+		if cursor = list.NextCursor; cursor == "" {
+			break
+		}
+	}
+	want := []string{"Операция 5", "Операция 4", "Операция 3", "Операция 2", "Операция 1"}
+	if !reflect.DeepEqual(titles, want) {
+		t.Errorf("страницы = %v, want %v", titles, want)
+	}
+
+	var first TransactionList
+	s.call(1, "GET", "/transactions?limit=2", nil, &first)
+	tests := []struct {
+		name  string
+		query string
+		want  int
+		total int64
+		sums  money.Amount // доходы минус расходы в рублях
+	}{
+		{"по сумме по возрастанию", "sort=amount&order=asc", http.StatusOK, 5, -300},
+		{"только доходы", "type=income", http.StatusOK, 2, 600},
+		{"период", "dateFrom=2024-03-02&dateTo=2024-03-03", http.StatusOK, 2, -100},
+		{"диапазон сумм", "amountMin=150&amountMax=450", http.StatusOK, 3, 300},
+		{"курсор другой сортировки", "sort=amount&cursor=" + first.NextCursor, http.StatusBadRequest, 0, 0},
+		{"неверный курсор", "cursor=abc", http.StatusBadRequest, 0, 0},
+		{"неверное поле сортировки", "sort=color", http.StatusBadRequest, 0, 0},
+		{"перевёрнутый период", "dateFrom=2024-03-05&dateTo=2024-03-01", http.StatusBadRequest, 0, 0},
+	}
+	for _, tt := range tests {
+		var list TransactionList
+		w := s.call(1, "GET", "/transactions?"+tt.query, nil, &list)
+		if w.Code != tt.want {
+			t.Errorf("%s: status %d, want %d: %s", tt.name, w.Code, tt.want, w.Body)
+			continue
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+		sums := list.Sums["RUB"]
+		if list.Total != tt.total || sums.Income-sums.Expense != money.FromUnits(int64(tt.sums)) {
+			t.Errorf("%s: total = %d, sums = %+v, want %d, %d", tt.name, list.Total, sums, tt.total, tt.sums)
+		}
+	}
+}
+
+func TestSearchHighlight(t *testing.T) {
+	s := newTestServer(t)
+	s.create(gin.H{"amount": 100, "title": `Обед <script>alert("кафе")</script>`, "description": "Кафе & бар", "type": "expense"})
+
+	var hits []repository.SearchHit
+	if w := s.call(1, "GET", "/transactions/search?q=кафе", nil, &hits); w.Code != http.StatusOK || len(hits) != 1 {
+		t.Fatalf("search: status %d: %s", w.Code, w.Body)
+	}
+	wantTitle := `Обед &lt;script&gt;alert(&#34;<mark>кафе</mark>&#34;)&lt;/script&gt;`
+	if hits[0].TitleHighlight != wantTitle {
+		t.Errorf("titleHighlight = %q, want %q", hits[0].TitleHighlight, wantTitle)
+	}
+	if want := "<mark>Кафе</mark> &amp; бар"; hits[0].DescriptionHighlight != want {
+		t.Errorf("descriptionHighlight = %q, want %q", hits[0].DescriptionHighlight, want)
+	}
+	if want := `Обед <script>alert("кафе")</script>`; hits[0].Title != want {
+		t.Errorf("title = %q, want исходное название", hits[0].Title)
+	}
+}
@@ -0,0 +1,151 @@
+package transactions
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+type TransactionListInput struct {
+	TransactionSearchInput
+	Sort   string `form:"sort" binding:"omitempty,oneof=date amount title created"`
+	Order  string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1"`
+	Cursor string `form:"cursor"`
+}
+
+// TransactionList — страница списка транзакций.
+type TransactionList struct {
+	Items      []models.Transaction                  `json:"items"`
+	NextCursor string                                `json:"nextCursor,omitempty"` // пустой на последней странице
+	Total      int64                                 `json:"total"`
+	Sums       map[string]repository.TransactionSums `json:"sums"` // по валютам
+}
+
+// pageCursor — содержимое курсора. Сортировка сохраняется, чтобы курсор
+// нельзя было применить к списку с другим порядком.
+type pageCursor struct {
+	Sort repository.SortField `json:"s"`
+	Desc bool                 `json:"o,omitempty"`
+	repository.Cursor
+}
+
+// @Security BearerAuth
+// ListTransactions godoc
+// @Summary Получить транзакции
+// @Description Возвращает транзакции пользователя постранично. Для следующей страницы передайте nextCursor в параметре cursor
+// @Description с теми же фильтрами и сортировкой. Фильтры совпадают с /transactions/search. total и sums считаются по всему фильтру, общее количество дублируется в заголовке X-Total-Count
+// @Tags Transactions
+// @Produce json
+// @Param sort query string false "Поле сортировки: date (по умолчанию), amount, title или created"
+// @Param order query string false "Порядок: desc (по умолчанию) или asc"
+// @Param limit query int false "Размер страницы, по умолчанию 50, не больше 200"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Param q query string false "Полнотекстовый поиск по названию и описанию: слова, «фразы» в кавычках, -исключения"
+// @Param title query string false "Название транзакции (частичное совпадение)"
+// @Param amount query number false "Приблизительная сумма транзакции (±10%)"
+// @Param amountMin query number false "Минимальная сумма"
+// @Param amountMax query number false "Максимальная сумма"
+// @Param bonusChange query number false "Приблизительное количество бонусов"
+// @Param date query string false "Дата транзакции (формат YYYY-MM-DD)"
+// @Param dateFrom query string false "Начало периода включительно (YYYY-MM-DD)"
+// @Param dateTo query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Param period query string false "Относительный период" Enums(today, yesterday, this_week, last_week, this_month, last_month, this_year, last_year, last_7_days, last_30_days)
+// @Param category query []int false "ID категорий" collectionFormat(multi)
+// @Param account query []int false "ID счетов" collectionFormat(multi)
+// @Param currency query []string false "Валюты" collectionFormat(multi)
+// @Param type query []string false "Типы транзакций (income, expense, transfer)" collectionFormat(multi)
+// @Param typeBonus query string false "Тип бонуса"
+// @Param excludeCategory query []int false "Исключить категории" collectionFormat(multi)
+// @Param excludeAccount query []int false "Исключить счета" collectionFormat(multi)
+// @Param excludeCurrency query []string false "Исключить валюты" collectionFormat(multi)
+// @Param excludeType query []string false "Исключить типы транзакций" collectionFormat(multi)
+// @Success 200 {object} TransactionList "Страница транзакций"
+// @Header 200 {integer} X-Total-Count "Количество транзакций по фильтру"
+// @Failure 400 {object} response.ErrorResponse "Неверные параметры запроса"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении транзакций"
+// @Router /transactions [get]
+func (h *Handler) ListTransactions(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input TransactionListInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	opts := repository.ListOptions{
+		Sort:  repository.SortByDate,
+		Desc:  input.Order != "asc",
+		Limit: defaultPageSize,
+	}
+	if input.Sort != "" {
+		opts.Sort = repository.SortField(input.Sort)
+	}
+	if input.Limit > 0 {
+		opts.Limit = min(input.Limit, maxPageSize)
+	}
+	if input.Cursor != "" {
+		cursor, err := decodeCursor(input.Cursor)
+		if err != nil || cursor.Sort != opts.Sort || cursor.Desc != opts.Desc {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный курсор"})
+			return
+		}
+		opts.After = &cursor.Cursor
+	}
+
+	filter, err := searchFilter(input.TransactionSearchInput, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.store.Transactions().List(userID, filter, opts)
+	if err != nil {
+		log.Println("Ошибка при получении транзакций:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении транзакций"})
+		return
+	}
+
+	result := TransactionList{Items: page.Items, Total: page.Total, Sums: page.Sums}
+	if page.HasMore {
+		last := page.Items[len(page.Items)-1]
+		result.NextCursor = encodeCursor(pageCursor{Sort: opts.Sort, Desc: opts.Desc, Cursor: repository.CursorFor(last)})
+	}
+
+	c.Header("X-Total-Count", strconv.FormatInt(page.Total, 10))
+	c.JSON(http.StatusOK, result)
+}
+
+func encodeCursor(cursor pageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (pageCursor, error) {
+	var cursor pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor, err
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, err
+	}
+	if cursor.ID == 0 {
+		return cursor, errors.New("пустой курсор")
+	}
+	return cursor, nil
+}
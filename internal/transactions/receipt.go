@@ -0,0 +1,332 @@
+package transactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/budgets"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// Признак расчёта n из QR-кода чека.
+const (
+	ReceiptSale         = 1 // приход — обычная покупка
+	ReceiptSaleReturn   = 2 // возврат прихода
+	ReceiptPayout       = 3 // расход — магазин платит покупателю
+	ReceiptPayoutReturn = 4 // возврат расхода
+)
+
+// Receipt — данные кассового чека из QR-кода.
+type Receipt struct {
+	Date     time.Time
+	Amount   money.Amount
+	Drive    string // fn, номер фискального накопителя
+	Document string // i, номер фискального документа
+	Sign     string // fp, фискальный признак документа
+	Kind     int    // n, признак расчёта
+}
+
+// TransactionType возвращает тип транзакции для чека: покупка — расход,
+// возврат покупки — доход.
+func (r Receipt) TransactionType() models.TransactionType {
+	if r.Kind == ReceiptSaleReturn || r.Kind == ReceiptPayout {
+		return models.Income
+	}
+	return models.Expense
+}
+
+var receiptDigits = regexp.MustCompile(`^\d+$`)
+
+// ParseReceiptQR разбирает строку QR-кода кассового чека вида
+// t=20240101T1200&s=1234.50&fn=9999078900004792&i=12345&fp=1234567890&n=1.
+// Время чека — местное время магазина, оно сохраняется в часовом поясе сервера.
+func ParseReceiptQR(raw string) (*Receipt, error) {
+	values, err := url.ParseQuery(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, errors.New("Строка QR-кода не похожа на чек")
+	}
+	for _, key := range []string{"t", "s", "fn", "i", "fp"} {
+		if values.Get(key) == "" {
+			return nil, fmt.Errorf("В QR-коде чека нет поля %s", key)
+		}
+	}
+
+	receipt := &Receipt{
+		Drive:    values.Get("fn"),
+		Document: values.Get("i"),
+		Sign:     values.Get("fp"),
+		Kind:     ReceiptSale,
+	}
+	if len(receipt.Drive) > 16 || !receiptDigits.MatchString(receipt.Drive) {
+		return nil, fmt.Errorf("Неверный номер фискального накопителя %q", receipt.Drive)
+	}
+	if len(receipt.Document) > 10 || !receiptDigits.MatchString(receipt.Document) {
+		return nil, fmt.Errorf("Неверный номер фискального документа %q", receipt.Document)
+	}
+	if len(receipt.Sign) > 10 || !receiptDigits.MatchString(receipt.Sign) {
+		return nil, fmt.Errorf("Неверный фискальный признак %q", receipt.Sign)
+	}
+
+	t := values.Get("t")
+	for _, layout := range []string{"20060102T1504", "20060102T150405"} {
+		if receipt.Date, err = time.ParseInLocation(layout, t, time.Local); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Неверная дата чека %q", t)
+	}
+
+	receipt.Amount, err = money.Parse(values.Get("s"))
+	if err != nil || receipt.Amount <= 0 {
+		return nil, fmt.Errorf("Неверная сумма чека %q", values.Get("s"))
+	}
+
+	if n := values.Get("n"); n != "" {
+		switch n {
+		case "1", "2", "3", "4":
+			receipt.Kind = int(n[0] - '0')
+		default:
+			return nil, fmt.Errorf("Неизвестный признак расчёта %q", n)
+		}
+	}
+	return receipt, nil
+}
+
+// ReceiptInput — чек из QR-кода и необязательные поля транзакции.
+// Если категория не указана, транзакция попадает в «Без категории».
+type ReceiptInput struct {
+	QR          string       `json:"qr" form:"qr"` // строка из QR-кода; для /transactions/receipt/image не нужна
+	Title       string       `json:"title" form:"title"`
+	Description string       `json:"description" form:"description"`
+	Category    uint         `json:"category" form:"category"`
+	Account     uint         `json:"account" form:"account"` // если не указан, используется основной счёт
+	BonusChange money.Amount `json:"bonusChange" form:"bonusChange" swaggertype:"number"`
+	BonusType   string       `json:"typeBonus" form:"typeBonus"`
+}
+
+// @Security BearerAuth
+// CreateFromReceipt godoc
+// @Summary Добавить транзакцию по QR-коду чека
+// @Description Создаёт расход (или доход для возврата) по строке из QR-кода кассового чека: дата, сумма и фискальные признаки fn, i, fp берутся из чека. Один чек нельзя добавить дважды.
+// @Tags Transactions
+// @Accept json
+// @Produce json
+// @Param input body ReceiptInput true "Строка QR-кода и данные транзакции"
+// @Success 201 {object} models.Transaction
+// @Failure 400 {object} response.ErrorResponse "Неверный QR-код или данные транзакции"
+// @Failure 409 {object} response.ErrorResponse "Чек уже добавлен"
+// @Failure 500 {object} response.ErrorResponse "Ошибка создания транзакции"
+// @Router /transactions/receipt [post]
+func (h *Handler) CreateFromReceipt(c *gin.Context) {
+	var input ReceiptInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	receipt, err := ParseReceiptQR(input.QR)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.createFromReceipt(c, receipt, input)
+}
+
+// maxImageSize ограничивает размер фотографии чека.
+const maxImageSize = 10 << 20
+
+// @Security BearerAuth
+// CreateFromReceiptImage godoc
+// @Summary Добавить транзакцию по фотографии QR-кода чека
+// @Description Распознаёт QR-код чека на изображении на сервере (утилитой zbarimg, путь задаётся QR_DECODER) и создаёт транзакцию так же, как POST /transactions/receipt.
+// @Tags Transactions
+// @Accept mpfd
+// @Produce json
+// @Param image formData file true "Изображение с QR-кодом, до 10 МБ"
+// @Param title formData string false "Название, по умолчанию «Покупка по чеку»"
+// @Param description formData string false "Описание"
+// @Param category formData int false "ID категории"
+// @Param account formData int false "ID счёта, по умолчанию основной"
+// @Param bonusChange formData number false "Изменение бонусов"
+// @Param typeBonus formData string false "Тип изменения бонусов"
+// @Success 201 {object} models.Transaction
+// @Failure 400 {object} response.ErrorResponse "QR-код не найден или данные неверны"
+// @Failure 409 {object} response.ErrorResponse "Чек уже добавлен"
+// @Failure 500 {object} response.ErrorResponse "Ошибка создания транзакции"
+// @Failure 501 {object} response.ErrorResponse "Распознавание изображений не настроено"
+// @Router /transactions/receipt/image [post]
+func (h *Handler) CreateFromReceiptImage(c *gin.Context) {
+	var input ReceiptInput
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, header, err := c.Request.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Загрузите изображение чека в поле image"})
+		return
+	}
+	defer file.Close()
+	if header.Size > maxImageSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Изображение больше 10 МБ"})
+		return
+	}
+
+	codes, err := decodeQRImage(c.Request.Context(), file)
+	if errors.Is(err, errNoDecoder) {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Распознавание QR-кодов на изображениях не настроено на сервере"})
+		return
+	}
+	if err != nil {
+		log.Println("Ошибка распознавания QR-кода:", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не удалось распознать изображение"})
+		return
+	}
+
+	// На фото может попасть несколько кодов, берём первый похожий на чек
+	for _, code := range codes {
+		if receipt, err := ParseReceiptQR(code); err == nil {
+			h.createFromReceipt(c, receipt, input)
+			return
+		}
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "На изображении не найден QR-код чека"})
+}
+
+func (h *Handler) createFromReceipt(c *gin.Context, receipt *Receipt, input ReceiptInput) {
+	userID := c.GetUint("userID")
+
+	if input.Category == 0 {
+		uncategorized, err := h.store.Categories().FindDefaultBySlug(models.UncategorizedSlug)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка создания транзакции"})
+			return
+		}
+		input.Category = uncategorized.ID
+	}
+	if input.Title == "" {
+		input.Title = "Покупка по чеку"
+		if receipt.TransactionType() == models.Income {
+			input.Title = "Возврат по чеку"
+		}
+	}
+
+	transaction, errMsg := h.buildTransaction(userID, TransactionInput{
+		Amount:      receipt.Amount,
+		BonusChange: input.BonusChange,
+		Date:        receipt.Date,
+		Title:       input.Title,
+		Description: input.Description,
+		Category:    input.Category,
+		Account:     input.Account,
+		Type:        string(receipt.TransactionType()),
+		BonusType:   input.BonusType,
+	})
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+	transaction.FiscalDrive = &receipt.Drive
+	transaction.FiscalDocument = &receipt.Document
+	transaction.FiscalSign = &receipt.Sign
+
+	var duplicate *models.Transaction
+	err := h.store.Atomic(func(s repository.Store) error {
+		existing, err := s.Transactions().FindByReceipt(userID, receipt.Drive, receipt.Document, receipt.Sign)
+		if err == nil {
+			duplicate = existing
+			return errReceiptExists
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			errMsg = "Ошибка создания транзакции"
+			return err
+		}
+		if err := s.Transactions().Create(transaction); err != nil {
+			errMsg = "Ошибка создания транзакции"
+			return err
+		}
+		if err := ApplyEffect(s, transaction, 1); err != nil {
+			errMsg = "Не удалось обновить баланс"
+			return err
+		}
+		return nil
+	})
+	if duplicate != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Этот чек уже добавлен", "transaction": duplicate.ID})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errMsg})
+		return
+	}
+
+	budgets.Notify(h.store, userID, *transaction)
+	c.JSON(http.StatusCreated, transaction)
+}
+
+var (
+	errReceiptExists = errors.New("чек уже добавлен")
+	errNoDecoder     = errors.New("программа распознавания QR-кодов не найдена")
+)
+
+// decodeQRImage распознаёт QR-коды на изображении программой zbarimg
+// (другой путь задаётся QR_DECODER). Изображение не отправляется во внешние
+// сервисы. Возвращает содержимое всех найденных кодов.
+func decodeQRImage(ctx context.Context, image io.Reader) ([]string, error) {
+	decoder := os.Getenv("QR_DECODER")
+	if decoder == "" {
+		decoder = "zbarimg"
+	}
+	path, err := exec.LookPath(decoder)
+	if err != nil {
+		return nil, errNoDecoder
+	}
+
+	tmp, err := os.CreateTemp("", "receipt-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, io.LimitReader(image, maxImageSize))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "--raw", "--quiet", "-Sdisable", "-Sqrcode.enable", tmp.Name()).Output()
+	var exitErr *exec.ExitError
+	// Код выхода 4 означает, что на изображении нет ни одного кода
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 4 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			codes = append(codes, line)
+		}
+	}
+	return codes, nil
+}
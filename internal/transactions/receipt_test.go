@@ -0,0 +1,78 @@
+package transactions
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+)
+
+func TestParseReceiptQR(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    *Receipt
+		wantErr bool
+	}{
+		{
+			name: "покупка",
+			raw:  "t=20240101T1200&s=1234.50&fn=9999078900004792&i=12345&fp=1234567890&n=1",
+			want: &Receipt{Date: time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local), Amount: 123450, Drive: "9999078900004792", Document: "12345", Sign: "1234567890", Kind: ReceiptSale},
+		},
+		{
+			name: "время с секундами, без n",
+			raw:  " fp=42&i=7&fn=1&s=10&t=20240229T235959 ",
+			want: &Receipt{Date: time.Date(2024, 2, 29, 23, 59, 59, 0, time.Local), Amount: 1000, Drive: "1", Document: "7", Sign: "42", Kind: ReceiptSale},
+		},
+		{
+			name: "возврат прихода",
+			raw:  "t=20240101T1200&s=5&fn=1&i=2&fp=3&n=2",
+			want: &Receipt{Date: time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local), Amount: 500, Drive: "1", Document: "2", Sign: "3", Kind: ReceiptSaleReturn},
+		},
+		{name: "не чек", raw: "https://example.com", wantErr: true},
+		{name: "нет fp", raw: "t=20240101T1200&s=5&fn=1&i=2", wantErr: true},
+		{name: "буквы в fn", raw: "t=20240101T1200&s=5&fn=12a&i=2&fp=3", wantErr: true},
+		{name: "длинный fn", raw: "t=20240101T1200&s=5&fn=12345678901234567&i=2&fp=3", wantErr: true},
+		{name: "длинный i", raw: "t=20240101T1200&s=5&fn=1&i=12345678901&fp=3", wantErr: true},
+		{name: "неверная дата", raw: "t=2024-01-01&s=5&fn=1&i=2&fp=3", wantErr: true},
+		{name: "нулевая сумма", raw: "t=20240101T1200&s=0&fn=1&i=2&fp=3", wantErr: true},
+		{name: "сумма с тремя знаками", raw: "t=20240101T1200&s=1.005&fn=1&i=2&fp=3", wantErr: true},
+		{name: "неизвестный n", raw: "t=20240101T1200&s=5&fn=1&i=2&fp=3&n=5", wantErr: true},
+		{name: "неверная строка запроса", raw: "t=%zz", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReceiptQR(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseReceiptQR() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseReceiptQR() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseReceiptQR() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReceiptTransactionType(t *testing.T) {
+	tests := []struct {
+		kind int
+		want models.TransactionType
+	}{
+		{ReceiptSale, models.Expense},
+		{ReceiptSaleReturn, models.Income},
+		{ReceiptPayout, models.Income},
+		{ReceiptPayoutReturn, models.Expense},
+	}
+	for _, tt := range tests {
+		if got := (Receipt{Kind: tt.kind}).TransactionType(); got != tt.want {
+			t.Errorf("Receipt{Kind: %d}.TransactionType() = %s, want %s", tt.kind, got, tt.want)
+		}
+	}
+}
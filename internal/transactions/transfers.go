@@ -0,0 +1,266 @@
+package transactions
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/httputil"
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type TransferInput struct {
+	FromAccount   uint          `json:"fromAccount" binding:"required"`
+	ToAccount     uint          `json:"toAccount" binding:"required"`
+	Amount        money.Amount  `json:"amount" binding:"required" swaggertype:"number"` // списывается в валюте счёта-источника
+	CounterAmount *money.Amount `json:"counterAmount" swaggertype:"number"`             // зачисляется в валюте счёта-получателя
+	Rate          string        `json:"rate"`                                           // курс вместо counterAmount
+	Fee           money.Amount  `json:"fee" swaggertype:"number"`
+	Date          time.Time     `json:"date"`
+	Title         string        `json:"title"`
+	Description   string        `json:"description"`
+}
+
+// @Security BearerAuth
+// CreateTransfer godoc
+// @Summary Перевод между счетами
+// @Description Списывает сумму и комиссию с одного счёта и зачисляет на другой одной транзакцией.
+// @Description Для счетов в разных валютах нужно указать counterAmount или rate. Переводы не учитываются как доходы и расходы
+// @Tags Transactions
+// @Accept json
+// @Produce json
+// @Param input body TransferInput true "Перевод"
+// @Success 201 {object} models.Transaction
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 500 {object} response.ErrorResponse "Ошибка создания перевода"
+// @Router /transfers [post]
+func (h *Handler) CreateTransfer(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input TransferInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Println("Ошибка валидации:", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Date.IsZero() {
+		input.Date = time.Now()
+	}
+	if input.Title == "" {
+		input.Title = "Перевод"
+	}
+
+	transaction := models.Transaction{
+		UserID:      userID,
+		Type:        models.Transfer,
+		Date:        input.Date,
+		Title:       input.Title,
+		Description: input.Description,
+	}
+	if errMsg := h.fillTransfer(&transaction, input); errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	var errMsg string
+	err := h.store.Atomic(func(s repository.Store) error {
+		if err := s.Transactions().Create(&transaction); err != nil {
+			errMsg = "Ошибка создания перевода"
+			return err
+		}
+		if err := ApplyEffect(s, &transaction, 1); err != nil {
+			errMsg = "Не удалось обновить баланс"
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errMsg})
+		return
+	}
+
+	c.JSON(http.StatusCreated, transaction)
+}
+
+type TransferUpdate struct {
+	FromAccount   *uint         `json:"fromAccount"`
+	ToAccount     *uint         `json:"toAccount"`
+	Amount        *money.Amount `json:"amount" swaggertype:"number"`
+	CounterAmount *money.Amount `json:"counterAmount" swaggertype:"number"`
+	Rate          *string       `json:"rate"`
+	Fee           *money.Amount `json:"fee" swaggertype:"number"`
+	Date          *time.Time    `json:"date"`
+	Title         *string       `json:"title"`
+	Description   *string       `json:"description"`
+}
+
+// @Security BearerAuth
+// UpdateTransfer godoc
+// @Summary Обновить перевод
+// @Description Изменяет перевод целиком: балансы обоих счетов пересчитываются в одной транзакции.
+// @Description При смене суммы между разными валютами нужно заново указать counterAmount или rate
+// @Tags Transactions
+// @Accept json
+// @Produce json
+// @Param id path string true "ID перевода"
+// @Param input body TransferUpdate true "Данные для обновления перевода"
+// @Success 200 {object} models.Transaction
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 404 {object} response.ErrorResponse "Перевод не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка обновления перевода"
+// @Router /transfers/{id} [put]
+func (h *Handler) UpdateTransfer(c *gin.Context) {
+	userID := c.GetUint("userID")
+	transactionID, err := httputil.ParamID(c, "id")
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Перевод не найден"})
+		return
+	}
+
+	var input TransferUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transaction, err := h.store.Transactions().GetOwned(transactionID, userID)
+	if err != nil || transaction.Type != models.Transfer {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Перевод не найден"})
+		return
+	}
+	old := *transaction
+
+	// Собираем полные параметры перевода из текущих значений и изменений
+	merged := TransferInput{
+		FromAccount: transaction.AccountID,
+		Amount:      transaction.Amount,
+		Fee:         transaction.Fee,
+		Rate:        transaction.Rate,
+	}
+	if transaction.CounterAccountID != nil {
+		merged.ToAccount = *transaction.CounterAccountID
+	}
+	if input.Rate == nil && input.Amount == nil {
+		counter := transaction.CounterAmount
+		merged.CounterAmount = &counter
+	}
+	if input.FromAccount != nil {
+		merged.FromAccount = *input.FromAccount
+	}
+	if input.ToAccount != nil {
+		merged.ToAccount = *input.ToAccount
+	}
+	if input.Amount != nil {
+		merged.Amount = *input.Amount
+	}
+	if input.CounterAmount != nil {
+		merged.CounterAmount = input.CounterAmount
+	}
+	if input.Rate != nil {
+		merged.Rate = *input.Rate
+	}
+	if input.Fee != nil {
+		merged.Fee = *input.Fee
+	}
+
+	if errMsg := h.fillTransfer(transaction, merged); errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+	if input.Date != nil {
+		transaction.Date = *input.Date
+	}
+	if input.Title != nil && *input.Title != "" {
+		transaction.Title = *input.Title
+	}
+	if input.Description != nil {
+		transaction.Description = *input.Description
+	}
+
+	var errMsg string
+	err = h.store.Atomic(func(s repository.Store) error {
+		if err := s.Transactions().Save(transaction); err != nil {
+			errMsg = "Ошибка обновления перевода"
+			return err
+		}
+		if err := ApplyEffect(s, &old, -1); err != nil {
+			errMsg = "Не удалось обновить баланс"
+			return err
+		}
+		if err := ApplyEffect(s, transaction, 1); err != nil {
+			errMsg = "Не удалось обновить баланс"
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errMsg})
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// fillTransfer проверяет параметры перевода и записывает их в транзакцию.
+// Возвращает текст ошибки для ответа 400.
+func (h *Handler) fillTransfer(t *models.Transaction, input TransferInput) string {
+	if input.Amount <= 0 {
+		return "Сумма должна быть больше 0"
+	}
+	if input.Fee < 0 {
+		return "Комиссия не может быть отрицательной"
+	}
+	if input.FromAccount == input.ToAccount {
+		return "Счета списания и зачисления должны различаться"
+	}
+
+	from, errMsg := UserAccount(h.store, t.UserID, input.FromAccount)
+	if errMsg != "" || input.FromAccount == 0 {
+		return "Указан неверный счёт списания"
+	}
+	to, errMsg := UserAccount(h.store, t.UserID, input.ToAccount)
+	if errMsg != "" || input.ToAccount == 0 {
+		return "Указан неверный счёт зачисления"
+	}
+
+	counter := input.Amount
+	rate := ""
+	if from.Currency != to.Currency {
+		switch {
+		case input.CounterAmount != nil:
+			counter = *input.CounterAmount
+		case input.Rate != "":
+			converted, err := money.Convert(input.Amount, input.Rate)
+			if err != nil {
+				return err.Error()
+			}
+			counter = converted
+			rate = input.Rate
+		default:
+			return "Для перевода между валютами укажите counterAmount или rate"
+		}
+		if counter <= 0 {
+			return "Сумма зачисления должна быть больше 0"
+		}
+	}
+
+	uncategorized, err := h.store.Categories().FindDefaultBySlug(models.UncategorizedSlug)
+	if err != nil {
+		return "Категория «Без категории» не найдена"
+	}
+
+	toID := to.ID
+	t.AccountID = from.ID
+	t.CounterAccountID = &toID
+	t.Amount = input.Amount
+	t.Currency = from.Currency
+	t.CounterAmount = counter
+	t.Fee = input.Fee
+	t.Rate = rate
+	t.Category = uncategorized.ID
+	return ""
+}
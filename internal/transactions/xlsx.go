@@ -0,0 +1,213 @@
+package transactions
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Минимальная книга XLSX из одного листа. Строки записываются как inline-строки,
+// поэтому таблицу общих строк не нужно собирать заранее и лист пишется потоком.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`
+
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Транзакции" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+
+	// Стили: 1 — заголовок, 2 — дата и время, 3 — сумма с двумя знаками
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<numFmts count="1"><numFmt numFmtId="164" formatCode="dd.mm.yyyy hh:mm"/></numFmts>` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="4">` +
+		`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`</cellXfs>` +
+		`</styleSheet>`
+
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>` +
+		`<cols><col min="1" max="1" width="8" customWidth="1"/><col min="2" max="2" width="17" customWidth="1"/>` +
+		`<col min="3" max="7" width="14" customWidth="1"/><col min="8" max="9" width="32" customWidth="1"/>` +
+		`<col min="10" max="14" width="14" customWidth="1"/></cols>` +
+		`<sheetData>`
+
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// Стили ячеек из xlsxStyles.
+const (
+	xlsxHeader = 1
+	xlsxDate   = 2
+	xlsxAmount = 3
+)
+
+// excelEpoch — нулевой день дат Excel с учётом ошибки 1900 года.
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+type xlsxExport struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	row   int
+	col   int
+}
+
+func newXLSXExport(w io.Writer) (*xlsxExport, error) {
+	e := &xlsxExport{zip: zip.NewWriter(w)}
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		f, err := e.zip.Create(part.name)
+		if err != nil {
+			return e, err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return e, err
+		}
+	}
+
+	// Лист создаётся последним: архив пишется последовательно, и он остаётся открытым
+	f, err := e.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return e, err
+	}
+	e.sheet = bufio.NewWriter(f)
+	e.sheet.WriteString(xlsxSheetStart)
+
+	e.startRow()
+	for _, title := range exportColumns {
+		e.text(title, xlsxHeader)
+	}
+	return e, e.endRow()
+}
+
+func (e *xlsxExport) WriteRow(row ExportRow) error {
+	e.startRow()
+	e.number(strconv.FormatUint(uint64(row.ID), 10), 0)
+	e.date(row.Date)
+	e.text(typeNames[row.Type], 0)
+	e.number(row.Amount.String(), xlsxAmount)
+	e.text(row.Currency, 0)
+	e.text(row.Account, 0)
+	e.text(row.Category, 0)
+	e.text(row.Title, 0)
+	e.text(row.Description, 0)
+	if row.BonusChange != 0 {
+		e.number(row.BonusChange.String(), xlsxAmount)
+		e.text(typeNames[row.BonusType], 0)
+	} else {
+		e.empty()
+		e.empty()
+	}
+	e.text(row.CounterAccount, 0)
+	if row.CounterAccount != "" {
+		e.number(row.CounterAmount.String(), xlsxAmount)
+	} else {
+		e.empty()
+	}
+	if row.Fee != 0 {
+		e.number(row.Fee.String(), xlsxAmount)
+	}
+	return e.endRow()
+}
+
+func (e *xlsxExport) Close() error {
+	if _, err := e.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+	if err := e.sheet.Flush(); err != nil {
+		return err
+	}
+	return e.zip.Close()
+}
+
+func (e *xlsxExport) startRow() {
+	e.row++
+	e.col = 0
+	e.sheet.WriteString(`<row r="` + strconv.Itoa(e.row) + `">`)
+}
+
+// endRow закрывает строку и сбрасывает буфер в архив. Ошибки записи
+// в bufio.Writer сохраняются до Flush, поэтому проверяются здесь.
+func (e *xlsxExport) endRow() error {
+	e.sheet.WriteString(`</row>`)
+	if e.sheet.Buffered() < 32<<10 {
+		return nil
+	}
+	return e.sheet.Flush()
+}
+
+// cellStart открывает ячейку в следующей колонке. Адрес указывается явно,
+// поэтому пустые ячейки можно не записывать.
+func (e *xlsxExport) cellStart(style int, kind string) {
+	e.col++
+	e.sheet.WriteString(`<c r="` + string(rune('A'+e.col-1)) + strconv.Itoa(e.row) + `"`)
+	if style != 0 {
+		e.sheet.WriteString(` s="` + strconv.Itoa(style) + `"`)
+	}
+	if kind != "" {
+		e.sheet.WriteString(` t="` + kind + `"`)
+	}
+	e.sheet.WriteString(`>`)
+}
+
+func (e *xlsxExport) text(s string, style int) {
+	if s == "" {
+		e.empty()
+		return
+	}
+	e.cellStart(style, "inlineStr")
+	e.sheet.WriteString(`<is><t xml:space="preserve">`)
+	xml.EscapeText(e.sheet, []byte(s))
+	e.sheet.WriteString(`</t></is></c>`)
+}
+
+func (e *xlsxExport) number(value string, style int) {
+	e.cellStart(style, "")
+	e.sheet.WriteString(`<v>` + value + `</v></c>`)
+}
+
+// date записывает дату как число дней от начала эпохи Excel по настенному времени транзакции.
+func (e *xlsxExport) date(t time.Time) {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	days := float64(wall.Sub(excelEpoch)) / float64(24*time.Hour)
+	e.number(strconv.FormatFloat(days, 'f', -1, 64), xlsxDate)
+}
+
+func (e *xlsxExport) empty() {
+	e.col++
+}
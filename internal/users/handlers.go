@@ -2,40 +2,68 @@ package users
 
 import (
 	"net/http"
+	"strings"
 
-	"github.com/Anabol1ks/pers-fin-m/internal/storage"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/Anabol1ks/pers-fin-m/internal/response"
 	"github.com/gin-gonic/gin"
 )
 
+type Handler struct {
+	store repository.Store
+}
+
+func NewHandler(store repository.Store) *Handler {
+	return &Handler{store: store}
+}
+
 // @Security BearerAuth
 // GetBalanceHandler godoc
 // @Summary Получить текущий баланс пользователя
-// @Description Получает текущий баланс пользователя
+// @Description Получает сумму балансов всех неархивных счетов: balance — в рублях, totals — по каждой валюте
 // @Tags Users
 // @Produce json
 // @Success 200 {object} response.BalanceResponse "Баланс пользователя"
 // @Failure 500 {object} response.ErrorResponse "Ошибка при получении баланса"
 // @Router /users/balance [get]
-func GetBalanceHandler(c *gin.Context) {
+func (h *Handler) GetBalanceHandler(c *gin.Context) {
 	userID := c.GetUint("userID")
 
-	var user User
-	if err := storage.DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
+	totals, err := h.totals(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении баланса"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"balance": user.Balance})
+	c.JSON(http.StatusOK, response.BalanceResponse{Balance: totals[DefaultCurrency], Totals: totals})
+}
+
+// DefaultCurrency — валюта, в которой возвращается общий баланс пользователя.
+const DefaultCurrency = "RUB"
+
+// totals суммирует балансы неархивных счетов пользователя по валютам.
+func (h *Handler) totals(userID uint) (map[string]money.Amount, error) {
+	accounts, err := h.store.Accounts().ListForUser(userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := map[string]money.Amount{}
+	for _, account := range accounts {
+		totals[account.Currency] += account.Balance
+	}
+	return totals, nil
 }
 
 type UpdateBalanceInput struct {
-	Balance float64 `json:"balance" binding:"required"`
+	Balance money.Amount `json:"balance" binding:"required" swaggertype:"number"`
 }
 
 // @Security BearerAuth
 // UpdateBalanceHandler godoc
 // @Summary Обновить баланс пользователя
-// @Description Обновляет баланс пользователя
+// @Description Устанавливает баланс основного счёта пользователя. Для остальных счетов используйте PUT /accounts/{id}
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -44,7 +72,7 @@ type UpdateBalanceInput struct {
 // @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
 // @Failure 500 {object} response.ErrorResponse "Ошибка при обновлении баланса"
 // @Router /users/balance [put]
-func UpdateBalanceHandler(c *gin.Context) {
+func (h *Handler) UpdateBalanceHandler(c *gin.Context) {
 	userID := c.GetUint("userID")
 
 	var input UpdateBalanceInput
@@ -58,20 +86,25 @@ func UpdateBalanceHandler(c *gin.Context) {
 		return
 	}
 
-	var user User
-	if err := storage.DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
+	account, err := h.store.Accounts().Primary(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Основной счёт не найден"})
 		return
 	}
 
-	// Обновляем баланс
-	user.Balance = input.Balance
-	if err := storage.DB.Save(&user).Error; err != nil {
+	// Обновляем баланс через начальный остаток, чтобы он сходился с транзакциями
+	if err := h.store.Accounts().AdjustOpeningBalance(account.ID, input.Balance-account.Balance); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось обновить баланс"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"balance": user.Balance})
+	totals, err := h.totals(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении баланса"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.BalanceResponse{Balance: totals[DefaultCurrency], Totals: totals})
 }
 
 // @Security BearerAuth
@@ -83,11 +116,11 @@ func UpdateBalanceHandler(c *gin.Context) {
 // @Success 200 {object} response.BonusResponse "Бонусы пользователя"
 // @Failure 500 {object} response.ErrorResponse "Ошибка при получении бонусов"
 // @Router /users/bonus [get]
-func GetBonusHandler(c *gin.Context) {
+func (h *Handler) GetBonusHandler(c *gin.Context) {
 	userID := c.GetUint("userID")
 
-	var user User
-	if err := storage.DB.First(&user, userID).Error; err != nil {
+	user, err := h.store.Users().GetByID(userID)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
 		return
 	}
@@ -96,7 +129,7 @@ func GetBonusHandler(c *gin.Context) {
 }
 
 type UpdateBonusInput struct {
-	Bonus float64 `json:"bonus" binding:"required"`
+	Bonus money.Amount `json:"bonus" binding:"required" swaggertype:"number"`
 }
 
 // @Security BearerAuth
@@ -111,7 +144,7 @@ type UpdateBonusInput struct {
 // @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
 // @Failure 500 {object} response.ErrorResponse "Ошибка при обновлении баланса бонусов"
 // @Router /users/bonus [put]
-func UpdateBonusHandler(c *gin.Context) {
+func (h *Handler) UpdateBonusHandler(c *gin.Context) {
 	userID := c.GetUint("userID")
 
 	var input UpdateBonusInput
@@ -125,28 +158,33 @@ func UpdateBonusHandler(c *gin.Context) {
 		return
 	}
 
-	var user User
-	if err := storage.DB.First(&user, userID).Error; err != nil {
+	user, err := h.store.Users().GetByID(userID)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
 		return
 	}
 
-	// Обновляем баланс
-	user.Bonus = input.Bonus
-	if err := storage.DB.Save(&user).Error; err != nil {
+	// Обновляем баланс через начальные бонусы, чтобы он сходился с транзакциями.
+	// Сдвиг атомарный, чтобы не потерять бонусы транзакций, проведённых после чтения
+	if err := h.store.Users().AdjustOpeningBonus(userID, input.Bonus-user.Bonus); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось обновить баланс бонусов"})
 		return
 	}
 
+	if user, err = h.store.Users().GetByID(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"balance": user.Bonus})
 }
 
 type UserInfo struct {
-	Username string  `json:"username"`
-	Email    string  `json:"email"`
-	Balance  float64 `json:"balance"`
-	Bonus    float64 `json:"bonus"`
-	Verified bool    `json:"verify"`
+	Username  string       `json:"username"`
+	Email     string       `json:"email"`
+	Balance   money.Amount `json:"balance" swaggertype:"number"`
+	Bonus     money.Amount `json:"bonus" swaggertype:"number"`
+	Verified  bool         `json:"verify"`
+	TwoFactor bool         `json:"twoFactor"` // включена двухфакторная аутентификация
 }
 
 // @Security BearerAuth
@@ -159,22 +197,101 @@ type UserInfo struct {
 // @Success 200 {object} UserInfo "Пользователь"
 // @Failure 500 {object} response.ErrorResponse "Пользователь не найден"
 // @Router /users/info [get]
-func UserInfoHandler(c *gin.Context) {
+func (h *Handler) UserInfoHandler(c *gin.Context) {
 	userID := c.GetUint("userID")
 
-	var user User
-	if err := storage.DB.First(&user, userID).Error; err != nil {
+	user, err := h.store.Users().GetByID(userID)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
 		return
 	}
 
+	totals, err := h.totals(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении баланса"})
+		return
+	}
+
 	resUser := UserInfo{
-		Username: user.Username,
-		Email:    user.Email,
-		Balance:  user.Balance,
-		Bonus:    user.Bonus,
-		Verified: user.Verified,
+		Username:  user.Username,
+		Email:     user.Email,
+		Balance:   totals[DefaultCurrency],
+		Bonus:     user.Bonus,
+		Verified:  user.Verified,
+		TwoFactor: user.TOTPEnabled,
 	}
 
 	c.JSON(http.StatusOK, resUser)
 }
+
+// NotificationSettings — настройки уведомлений пользователя.
+type NotificationSettings struct {
+	BudgetAlerts bool `json:"budgetAlerts"` // письма при расходовании 80% и 100% бюджета
+}
+
+type UpdateNotificationsInput struct {
+	BudgetAlerts *bool `json:"budgetAlerts"`
+}
+
+// @Security BearerAuth
+// GetNotificationsHandler godoc
+// @Summary Настройки уведомлений
+// @Tags Users
+// @Produce json
+// @Success 200 {object} NotificationSettings
+// @Failure 500 {object} response.ErrorResponse "Пользователь не найден"
+// @Router /users/notifications [get]
+func (h *Handler) GetNotificationsHandler(c *gin.Context) {
+	user, err := h.store.Users().GetByID(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
+		return
+	}
+
+	c.JSON(http.StatusOK, NotificationSettings{BudgetAlerts: user.BudgetAlerts})
+}
+
+// @Security BearerAuth
+// UpdateNotificationsHandler godoc
+// @Summary Изменить настройки уведомлений
+// @Description Меняет переданные настройки, остальные остаются прежними. Письма отправляются только на подтверждённую почту.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param input body UpdateNotificationsInput true "Настройки уведомлений"
+// @Success 200 {object} NotificationSettings
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при сохранении настроек"
+// @Router /users/notifications [put]
+func (h *Handler) UpdateNotificationsHandler(c *gin.Context) {
+	var input UpdateNotificationsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("userID")
+
+	if input.BudgetAlerts != nil {
+		if err := h.store.Users().SetBudgetAlerts(userID, *input.BudgetAlerts); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении настроек"})
+			return
+		}
+	}
+
+	user, err := h.store.Users().GetByID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Пользователь не найден"})
+		return
+	}
+	c.JSON(http.StatusOK, NotificationSettings{BudgetAlerts: user.BudgetAlerts})
+}
+
+// SetAdmin выдаёт или отзывает права администратора у пользователя с указанной почтой.
+func SetAdmin(store repository.Store, email string, isAdmin bool) error {
+	user, err := store.Users().GetByEmail(strings.ToLower(email))
+	if err != nil {
+		return err
+	}
+	return store.Users().SetAdmin(user.ID, isAdmin)
+}
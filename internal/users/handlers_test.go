@@ -0,0 +1,251 @@
+package users
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/money"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository/memory"
+	"github.com/Anabol1ks/pers-fin-m/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// newTestRouter поднимает маршруты пользователя поверх хранилища в памяти.
+// Пользователь запроса берётся из заголовка X-User-ID вместо токена.
+func newTestRouter(t *testing.T) (*memory.Store, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	store := memory.NewStore()
+
+	users := []*models.User{
+		{Username: "first", Email: "first@example.com", Verified: true, BudgetAlerts: true},
+		{Username: "second", Email: "second@example.com"},
+	}
+	for _, user := range users {
+		if err := store.Users().Create(user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	accounts := []models.Account{
+		{UserID: 1, Name: "Основной счёт", Currency: "RUB", OpeningBalance: 100000, Balance: 100000},
+		{UserID: 1, Name: "Наличные", Currency: "RUB", OpeningBalance: 5050, Balance: 5050},
+		{UserID: 1, Name: "Доллары", Currency: "USD", OpeningBalance: 2000, Balance: 2000},
+		{UserID: 1, Name: "Старый счёт", Currency: "RUB", OpeningBalance: 70000, Balance: 70000, Archived: true},
+		{UserID: 2, Name: "Основной счёт", Currency: "RUB", OpeningBalance: 300, Balance: 300},
+	}
+	for i := range accounts {
+		accounts[i].Type = models.AccountCard
+		if err := store.Accounts().Create(&accounts[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h := NewHandler(store)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		id, _ := strconv.Atoi(c.GetHeader("X-User-ID"))
+		c.Set("userID", uint(id))
+	})
+	r.GET("/users/balance", h.GetBalanceHandler)
+	r.PUT("/users/balance", h.UpdateBalanceHandler)
+	r.GET("/users/bonus", h.GetBonusHandler)
+	r.PUT("/users/bonus", h.UpdateBonusHandler)
+	r.GET("/users/info", h.UserInfoHandler)
+	r.GET("/users/notifications", h.GetNotificationsHandler)
+	r.PUT("/users/notifications", h.UpdateNotificationsHandler)
+	return store, r
+}
+
+func call(t *testing.T, r *gin.Engine, userID uint, method, path string, body, out any) int {
+	t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", strconv.Itoa(int(userID)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if out != nil && w.Code < 300 {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: %v: %s", method, path, err, w.Body)
+		}
+	}
+	return w.Code
+}
+
+func TestBalance(t *testing.T) {
+	store, r := newTestRouter(t)
+
+	var balance response.BalanceResponse
+	if code := call(t, r, 1, "GET", "/users/balance", nil, &balance); code != http.StatusOK {
+		t.Fatalf("balance: status %d", code)
+	}
+	want := response.BalanceResponse{Balance: 105050, Totals: map[string]money.Amount{"RUB": 105050, "USD": 2000}}
+	if !reflect.DeepEqual(balance, want) {
+		t.Errorf("balance = %+v, want %+v", balance, want)
+	}
+
+	tests := []struct {
+		name  string
+		input any
+		want  int
+		total money.Amount
+	}{
+		{"новый баланс основного счёта", gin.H{"balance": "1500,50"}, http.StatusOK, 155100},
+		{"ноль", gin.H{"balance": 0}, http.StatusBadRequest, 155100},
+		{"отрицательный", gin.H{"balance": -1}, http.StatusBadRequest, 155100},
+		{"не число", gin.H{"balance": "много"}, http.StatusBadRequest, 155100},
+	}
+	for _, tt := range tests {
+		var got response.BalanceResponse
+		if code := call(t, r, 1, "PUT", "/users/balance", tt.input, &got); code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, code, tt.want)
+		}
+		call(t, r, 1, "GET", "/users/balance", nil, &got)
+		if got.Balance != tt.total {
+			t.Errorf("%s: balance = %s, want %s", tt.name, got.Balance, tt.total)
+		}
+	}
+
+	// Баланс меняется через начальный остаток, чтобы сходиться с транзакциями
+	account, err := store.Accounts().Primary(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.OpeningBalance != 150050 || account.Balance != 150050 {
+		t.Errorf("основной счёт: начальный остаток %s, баланс %s, want 1500.50", account.OpeningBalance, account.Balance)
+	}
+	other, err := store.Accounts().Primary(2)
+	if err != nil || other.Balance != 300 {
+		t.Errorf("счёт другого пользователя = %+v, %v", other, err)
+	}
+}
+
+func TestBonus(t *testing.T) {
+	store, r := newTestRouter(t)
+	if err := store.Users().AdjustBonus(1, 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name   string
+		method string
+		input  any
+		want   int
+		bonus  money.Amount
+	}{
+		{"текущие бонусы", "GET", nil, http.StatusOK, 1000},
+		{"новый баланс", "PUT", gin.H{"bonus": 25}, http.StatusOK, 2500},
+		{"ноль", "PUT", gin.H{"bonus": 0}, http.StatusBadRequest, 2500},
+		{"отрицательный", "PUT", gin.H{"bonus": -5}, http.StatusBadRequest, 2500},
+	}
+	for _, step := range steps {
+		if code := call(t, r, 1, step.method, "/users/bonus", step.input, nil); code != step.want {
+			t.Errorf("%s: status %d, want %d", step.name, code, step.want)
+		}
+		var got struct {
+			Bonus money.Amount `json:"bonus"`
+		}
+		call(t, r, 1, "GET", "/users/bonus", nil, &got)
+		if got.Bonus != step.bonus {
+			t.Errorf("%s: bonus = %s, want %s", step.name, got.Bonus, step.bonus)
+		}
+	}
+
+	// Бонусы меняются через начальный остаток
+	user, err := store.Users().GetByID(1)
+	if err != nil || user.OpeningBonus != 1500 {
+		t.Errorf("начальные бонусы = %+v, %v, want 15", user, err)
+	}
+}
+
+func TestUserInfo(t *testing.T) {
+	_, r := newTestRouter(t)
+
+	tests := []struct {
+		userID uint
+		want   UserInfo
+		code   int
+	}{
+		{1, UserInfo{Username: "first", Email: "first@example.com", Balance: 105050, Verified: true}, http.StatusOK},
+		{2, UserInfo{Username: "second", Email: "second@example.com", Balance: 300}, http.StatusOK},
+		{3, UserInfo{}, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		var got UserInfo
+		if code := call(t, r, tt.userID, "GET", "/users/info", nil, &got); code != tt.code || got != tt.want {
+			t.Errorf("info пользователя %d = %d %+v, want %d %+v", tt.userID, code, got, tt.code, tt.want)
+		}
+	}
+}
+
+func TestNotifications(t *testing.T) {
+	_, r := newTestRouter(t)
+
+	steps := []struct {
+		name  string
+		input any
+		want  NotificationSettings
+	}{
+		{"пустой запрос ничего не меняет", gin.H{}, NotificationSettings{BudgetAlerts: true}},
+		{"отключение", gin.H{"budgetAlerts": false}, NotificationSettings{BudgetAlerts: false}},
+		{"включение", gin.H{"budgetAlerts": true}, NotificationSettings{BudgetAlerts: true}},
+	}
+	for _, step := range steps {
+		var got NotificationSettings
+		if code := call(t, r, 1, "PUT", "/users/notifications", step.input, &got); code != http.StatusOK || got != step.want {
+			t.Errorf("%s: %d %+v, want %+v", step.name, code, got, step.want)
+		}
+		if call(t, r, 1, "GET", "/users/notifications", nil, &got); got != step.want {
+			t.Errorf("%s: GET = %+v, want %+v", step.name, got, step.want)
+		}
+	}
+
+	var other NotificationSettings
+	if call(t, r, 2, "GET", "/users/notifications", nil, &other); other.BudgetAlerts {
+		t.Errorf("настройки другого пользователя изменились: %+v", other)
+	}
+}
+
+func TestSetAdmin(t *testing.T) {
+	store, _ := newTestRouter(t)
+	if err := store.Users().AdjustBonus(1, 500); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		email string
+		grant bool
+		want  bool
+	}{
+		{"выдача, почта в другом регистре", "First@Example.com", true, true},
+		{"повторная выдача", "first@example.com", true, true},
+		{"отзыв", "first@example.com", false, false},
+	}
+	for _, tt := range tests {
+		if err := SetAdmin(store, tt.email, tt.grant); err != nil {
+			t.Fatalf("%s: SetAdmin() = %v", tt.name, err)
+		}
+		user, err := store.Users().GetByID(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.IsAdmin != tt.want || user.Bonus != 500 || !user.BudgetAlerts {
+			t.Errorf("%s: пользователь = %+v, want IsAdmin %v без изменения остальных полей", tt.name, user, tt.want)
+		}
+	}
+
+	if err := SetAdmin(store, "nobody@example.com", true); err == nil {
+		t.Error("SetAdmin() неизвестного пользователя: ошибки нет")
+	}
+}
@@ -6,10 +6,19 @@ import (
 	"os"
 
 	_ "github.com/Anabol1ks/pers-fin-m/docs"
+	"github.com/Anabol1ks/pers-fin-m/internal/accounts"
 	"github.com/Anabol1ks/pers-fin-m/internal/auth"
+	"github.com/Anabol1ks/pers-fin-m/internal/banksms"
+	"github.com/Anabol1ks/pers-fin-m/internal/budgets"
 	сategory "github.com/Anabol1ks/pers-fin-m/internal/category"
 	email "github.com/Anabol1ks/pers-fin-m/internal/emails"
-	"github.com/Anabol1ks/pers-fin-m/internal/models"
+	"github.com/Anabol1ks/pers-fin-m/internal/goals"
+	"github.com/Anabol1ks/pers-fin-m/internal/importer"
+	"github.com/Anabol1ks/pers-fin-m/internal/ledger"
+	"github.com/Anabol1ks/pers-fin-m/internal/recurring"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository/memory"
+	"github.com/Anabol1ks/pers-fin-m/internal/repository/postgres"
 	"github.com/Anabol1ks/pers-fin-m/internal/storage"
 	"github.com/Anabol1ks/pers-fin-m/internal/transactions"
 	"github.com/Anabol1ks/pers-fin-m/internal/users"
@@ -33,51 +42,152 @@ func main() {
 			log.Fatal("Ошибка получения .env")
 		}
 	}
-	storage.ConnectDatabase()
 
-	if err := storage.DB.AutoMigrate(&users.User{}, &models.Transaction{}, &models.Category{}); err != nil {
-		log.Fatal(err)
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1:])
+		return
 	}
 
+	store := newStore()
+	seedCategories(store)
+	startLedgerJob(store)
+	startRecurringJob(store)
+
 	r := gin.Default()
 
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3001"}, // Укажи адрес фронтенда React
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Authorization", "Content-Type"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "X-Total-Count"},
 		AllowCredentials: true,
 	}))
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	r.POST("/auth/register", auth.RegisterHandler)
-	r.POST("/auth/login", auth.LoginHandler)
+	authHandler := auth.NewHandler(store, email.Mailer{})
+	transactionHandler := transactions.NewHandler(store)
+	categoryHandler := сategory.NewHandler(store)
+	userHandler := users.NewHandler(store)
+	accountHandler := accounts.NewHandler(store)
+	ledgerHandler := ledger.NewHandler(store)
+	importHandler := importer.NewHandler(store)
+	smsHandler := banksms.NewHandler(store)
+	recurringHandler := recurring.NewHandler(store)
+	budgetHandler := budgets.NewHandler(store)
+	goalHandler := goals.NewHandler(store)
+
+	r.POST("/auth/register", authHandler.RegisterHandler)
+	r.POST("/auth/login", authHandler.LoginHandler)
+	r.POST("/auth/refresh", authHandler.RefreshHandler)
+	r.POST("/auth/logout", authHandler.LogoutHandler)
+	r.POST("/auth/password/forgot", authHandler.ForgotPasswordHandler)
+	r.POST("/auth/password/reset", authHandler.ResetPasswordHandler)
+	r.POST("/auth/2fa/verify", authHandler.VerifyTwoFactor)
 
 	authorized := r.Group("/")
 	{
-		authorized.Use(auth.AuthMiddleware())
-		authorized.POST("/transactions", transactions.CreateTransaction)
-		// authorized.GET("/transactions", transactions.GetAllTransactions)
-		authorized.GET("/transactions/search", transactions.SearchTransactions)
-		authorized.PUT("/transactions/:id", transactions.UpdateTransaction)
-		authorized.DELETE("/transactions/:id", transactions.DelTransactions)
-
-		authorized.GET("/categories", сategory.GetAllCategories)
-		authorized.POST("/categories", сategory.CreateCategory)
-		authorized.DELETE("/categories/:id", сategory.DelCategory)
-		authorized.PUT("/categories/:id", сategory.UpdateCategory)
-
-		authorized.GET("/users/balance", users.GetBalanceHandler)
-		authorized.PUT("/users/balance", users.UpdateBalanceHandler)
-		authorized.GET("/users/bonus", users.GetBonusHandler)
-		authorized.PUT("/users/bonus", users.UpdateBonusHandler)
-		authorized.GET("/users/info", users.UserInfoHandler)
-
-		authorized.POST("/auth/verify", auth.VerifyEmailHandler)
-		authorized.POST("/auth/newVerify", email.SendNewVerify)
+		authorized.Use(auth.AuthMiddleware(store))
+		authorized.POST("/transactions", transactionHandler.CreateTransaction)
+		authorized.GET("/transactions", transactionHandler.ListTransactions)
+		authorized.GET("/transactions/search", transactionHandler.SearchTransactions)
+		authorized.GET("/transactions/export", transactionHandler.ExportTransactions)
+		authorized.POST("/transactions/receipt", transactionHandler.CreateFromReceipt)
+		authorized.POST("/transactions/receipt/image", transactionHandler.CreateFromReceiptImage)
+		authorized.PUT("/transactions/:id", transactionHandler.UpdateTransaction)
+		authorized.DELETE("/transactions/:id", transactionHandler.DelTransactions)
+		authorized.POST("/transactions/parse", smsHandler.ParseNotification)
+		authorized.GET("/transactions/parse/templates", smsHandler.ListTemplates)
+		authorized.POST("/transactions/parse/templates", smsHandler.CreateTemplate)
+		authorized.PUT("/transactions/parse/templates/:id", smsHandler.UpdateTemplate)
+		authorized.DELETE("/transactions/parse/templates/:id", smsHandler.DeleteTemplate)
+		authorized.POST("/transactions/import", importHandler.ImportTransactions)
+		authorized.GET("/transactions/import/mappings", importHandler.ListMappings)
+		authorized.POST("/transactions/import/mappings", importHandler.CreateMapping)
+		authorized.PUT("/transactions/import/mappings/:id", importHandler.UpdateMapping)
+		authorized.DELETE("/transactions/import/mappings/:id", importHandler.DeleteMapping)
+		authorized.POST("/transfers", transactionHandler.CreateTransfer)
+		authorized.PUT("/transfers/:id", transactionHandler.UpdateTransfer)
+
+		authorized.GET("/recurring", recurringHandler.ListRules)
+		authorized.POST("/recurring", recurringHandler.CreateRule)
+		authorized.GET("/recurring/upcoming", recurringHandler.ListUpcoming)
+		authorized.GET("/recurring/:id", recurringHandler.GetRule)
+		authorized.PUT("/recurring/:id", recurringHandler.UpdateRule)
+		authorized.DELETE("/recurring/:id", recurringHandler.DeleteRule)
+		authorized.POST("/recurring/:id/skip", recurringHandler.SkipOccurrence)
+		authorized.DELETE("/recurring/:id/skip/:date", recurringHandler.RestoreOccurrence)
+
+		authorized.GET("/budgets", budgetHandler.ListBudgets)
+		authorized.POST("/budgets", budgetHandler.CreateBudget)
+		authorized.GET("/budgets/progress", budgetHandler.ListProgress)
+		authorized.GET("/budgets/:id", budgetHandler.GetBudget)
+		authorized.PUT("/budgets/:id", budgetHandler.UpdateBudget)
+		authorized.DELETE("/budgets/:id", budgetHandler.DeleteBudget)
+		authorized.GET("/budgets/:id/progress", budgetHandler.GetProgress)
+
+		authorized.GET("/goals", goalHandler.ListGoals)
+		authorized.POST("/goals", goalHandler.CreateGoal)
+		authorized.GET("/goals/:id", goalHandler.GetGoal)
+		authorized.PUT("/goals/:id", goalHandler.UpdateGoal)
+		authorized.DELETE("/goals/:id", goalHandler.DeleteGoal)
+		authorized.POST("/goals/:id/contribute", goalHandler.Contribute)
+		authorized.POST("/goals/:id/withdraw", goalHandler.Withdraw)
+		authorized.GET("/goals/:id/transactions", goalHandler.ListContributions)
+		authorized.GET("/goals/:id/progress", goalHandler.GetProgress)
+
+		authorized.GET("/categories", categoryHandler.GetAllCategories)
+		authorized.POST("/categories", categoryHandler.CreateCategory)
+		authorized.DELETE("/categories/:id", categoryHandler.DelCategory)
+		authorized.PUT("/categories/:id", categoryHandler.UpdateCategory)
+
+		authorized.GET("/accounts", accountHandler.ListAccounts)
+		authorized.POST("/accounts", accountHandler.CreateAccount)
+		authorized.GET("/accounts/:id", accountHandler.GetAccount)
+		authorized.PUT("/accounts/:id", accountHandler.UpdateAccount)
+		authorized.DELETE("/accounts/:id", accountHandler.DeleteAccount)
+
+		authorized.GET("/users/balance", userHandler.GetBalanceHandler)
+		authorized.PUT("/users/balance", userHandler.UpdateBalanceHandler)
+		authorized.GET("/users/balance/check", ledgerHandler.CheckBalance)
+		authorized.POST("/users/balance/recalculate", ledgerHandler.RecalculateBalance)
+		authorized.GET("/users/bonus", userHandler.GetBonusHandler)
+		authorized.PUT("/users/bonus", userHandler.UpdateBonusHandler)
+		authorized.GET("/users/info", userHandler.UserInfoHandler)
+		authorized.GET("/users/notifications", userHandler.GetNotificationsHandler)
+		authorized.PUT("/users/notifications", userHandler.UpdateNotificationsHandler)
+
+		authorized.POST("/auth/verify", authHandler.VerifyEmailHandler)
+		authorized.POST("/auth/newVerify", authHandler.SendNewVerify)
+		authorized.GET("/auth/2fa", authHandler.GetTwoFactor)
+		authorized.POST("/auth/2fa/setup", authHandler.SetupTwoFactor)
+		authorized.POST("/auth/2fa/enable", authHandler.EnableTwoFactor)
+		authorized.POST("/auth/2fa/disable", authHandler.DisableTwoFactor)
+		authorized.POST("/auth/2fa/recovery-codes", authHandler.RegenerateRecoveryCodes)
+		authorized.GET("/auth/sessions", authHandler.ListSessions)
+		authorized.DELETE("/auth/sessions", authHandler.DeleteSessions)
+		authorized.DELETE("/auth/sessions/:id", authHandler.DeleteSession)
+
+		admin := authorized.Group("/admin")
+		admin.Use(auth.AdminMiddleware(store))
+		admin.GET("/categories", categoryHandler.ListDefaultCategories)
+		admin.POST("/categories", categoryHandler.CreateDefaultCategory)
+		admin.DELETE("/categories/:id", categoryHandler.RetireDefaultCategory)
+		admin.POST("/categories/:id/restore", categoryHandler.RestoreDefaultCategory)
 	}
 
 	if err := r.Run(":8080"); err != nil {
 		log.Fatal("Ошибка запуска сервера: ", err)
 	}
 }
+
+// newStore выбирает хранилище: STORAGE=memory запускает сервер без PostgreSQL.
+func newStore() repository.Store {
+	if os.Getenv("STORAGE") == "memory" {
+		fmt.Println("Используется хранилище в памяти")
+		return memory.NewStore()
+	}
+
+	storage.ConnectDatabase()
+	applyMigrations()
+	return postgres.NewStore(storage.DB)
+}